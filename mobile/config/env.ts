@@ -5,12 +5,14 @@
 
 interface Config {
   API_URL: string;
+  API_KEY?: string; // read-only API key, needed unless the server runs with AUTH_ENABLED=false
 }
 
 const config: Config = {
   // Remember to use the IP of the machine running the server
   // If running on a different machine or emulator, use the IP of the machine running the server
   API_URL: process.env.EXPO_PUBLIC_API_URL || 'http://127.0.0.1:8080',
+  API_KEY: process.env.EXPO_PUBLIC_API_KEY,
 };
 
 // Validate required environment variables
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/digest"
+)
+
+// sendDigest sends the digest of the last completed period right away, or prints it with -dry-run
+func sendDigest(args []string) {
+	flags := flag.NewFlagSet("digest", flag.ExitOnError)
+	schedule := flags.String("schedule", digest.Daily, "digest to send, daily or weekly")
+	dryRun := flags.Bool("dry-run", false, "print the plain text digest instead of sending it")
+	flags.Parse(args)
+
+	if *schedule != digest.Daily && *schedule != digest.Weekly {
+		log.Fatalf("invalid schedule %q: expected daily or weekly", *schedule)
+	}
+
+	cfg := config.Load()
+	appService := app.New(loadRepository(cfg, cfg.AppID), cfg)
+	end := digest.PeriodEnd(*schedule, cfg.DigestHour, time.Now())
+
+	if *dryRun {
+		text, _, err := digest.Render(digest.Build(appService, *schedule, end, time.Now()))
+		if err != nil {
+			log.Fatalf("rendering digest: %v", err)
+		}
+		fmt.Print(text)
+		return
+	}
+
+	job := digest.FromConfig(cfg, appService)
+	if job == nil {
+		log.Fatal("digests are disabled, set SMTP_HOST and DIGEST_RECIPIENTS")
+	}
+	if err := job.Send(*schedule, end); err != nil {
+		log.Fatalf("sending digest: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+const keysUsage = `Usage: server keys <create|list|revoke> [flags]
+
+  create -name NAME -role ROLE   Create a key and print it, roles are read-only, triage and admin
+  list                           List the keys, revoked ones included
+  revoke ID                      Revoke a key
+
+A running server picks up created and revoked keys on its next request.
+`
+
+// keys manages the API keys stored next to the reviews storage
+func keys(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, keysUsage)
+		os.Exit(2)
+	}
+
+	repo := repositories.LoadAPIKeys(config.Load().DataFilePath("api-keys.json"))
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ExitOnError)
+		name := flags.String("name", "", "name of the client using the key")
+		role := flags.String("role", models.RoleReadOnly, "role of the key: "+strings.Join(models.Roles, ", "))
+		flags.Parse(args[1:])
+
+		if strings.TrimSpace(*name) == "" {
+			log.Fatalf("-name is required")
+		}
+		if !slices.Contains(models.Roles, *role) {
+			log.Fatalf("invalid role %q: expected one of %s", *role, strings.Join(models.Roles, ", "))
+		}
+
+		key, secret, err := repo.Create(strings.TrimSpace(*name), *role)
+		if err != nil {
+			log.Fatalf("saving API key: %v", err)
+		}
+		fmt.Printf("Created %s key %s (%s)\n", key.Role, key.ID, key.Name)
+		fmt.Printf("Key: %s\n", secret)
+		fmt.Println("Store it now, it can't be shown again")
+	case "list":
+		for _, key := range repo.List() {
+			status := "active"
+			if key.IsRevoked() {
+				status = "revoked " + key.RevokedAt.Format("2006-01-02")
+			}
+			fmt.Printf("%s  %-9s  %s...  %s  (%s)\n", key.ID, key.Role, key.Prefix, key.Name, status)
+		}
+	case "revoke":
+		if len(args) != 2 {
+			fmt.Fprint(os.Stderr, keysUsage)
+			os.Exit(2)
+		}
+		found, err := repo.Revoke(args[1])
+		if err != nil {
+			log.Fatalf("revoking API key: %v", err)
+		}
+		if !found {
+			log.Fatalf("API key %s not found", args[1])
+		}
+		fmt.Printf("Revoked key %s\n", args[1])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown keys command %q\n\n%s", args[0], keysUsage)
+		os.Exit(2)
+	}
+}
@@ -1,66 +1,56 @@
 package main
 
 import (
-	"context"
-	"log"
-	"net/http"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
-	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api"
-	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
-	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/crons/appstore_reviews_poller"
-	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"strings"
 )
 
-func main() {
-	// Load config
-	cfg := config.Load()
-
-	// Load repositories
-	repositories := repositories.Load(cfg.StorageFilePath)
-
-	// Load app service
-	appService := app.New(repositories, cfg)
+const usage = `Usage: server [command] [flags]
 
-	// Load Gin router setup
-	router := api.NewRouter(appService)
+Commands:
+  serve      Run the HTTP server and the reviews poller (default)
+  poll-once  Fetch the latest reviews of every tracked app once and exit
+  export     Write the stored reviews of an app as JSON
+  import     Add reviews from a JSON file to the storage of an app
+  verify     Check the storage of an app for integrity problems
+  stats      Print a summary of the stored reviews of an app
+  digest     Send the email digest of the last completed period
+  keys       Create, list and revoke API keys
 
-	// Load cron jobs
-	poller := appstore_reviews_poller.New(cfg, appService)
-	// Run cron jobs
-	go poller.Run(context.Background())
+Configuration is read from the same environment variables as serve.
+Run "server <command> -h" for the flags of a command.
+`
 
-	// HTTP server
-	srv := &http.Server{
-		Addr:         ":" + cfg.Port,
-		Handler:      router,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+func main() {
+	command := "serve"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
+		args = args[1:]
 	}
 
-	// Run server in goroutine
-	go func() {
-		log.Printf("🚀 Server running on port %s", cfg.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("listen error: %s\n", err)
-		}
-	}()
-
-	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	switch command {
+	case "serve":
+		serve(args)
+	case "poll-once":
+		pollOnce(args)
+	case "export":
+		exportReviews(args)
+	case "import":
+		importReviews(args)
+	case "verify":
+		verify(args)
+	case "stats":
+		stats(args)
+	case "digest":
+		sendDigest(args)
+	case "keys":
+		keys(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
 	}
-
-	log.Println("Server exited gracefully")
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/crons/appstore_reviews_poller"
+)
+
+// pollOnce runs a single poll of every tracked app and exits
+func pollOnce(args []string) {
+	flags := flag.NewFlagSet("poll-once", flag.ExitOnError)
+	timeout := flags.Duration("timeout", 2*time.Minute, "maximum time to spend polling")
+	flags.Parse(args)
+
+	cfg := config.Load()
+	appService := app.New(loadRepository(cfg, cfg.AppID), cfg)
+	poller, err := appstore_reviews_poller.New(cfg, appService)
+	if err != nil {
+		log.Fatalf("Failed to create poller: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	poller.PollOnce(ctx)
+	appService.Webhooks().DeliverDue(ctx) // failed deliveries stay in the outbox for the server to retry
+	log.Println("Poll finished")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/crons/appstore_reviews_poller"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/digest"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/rpc"
+)
+
+// serve runs the HTTP and gRPC servers and the cron jobs until SIGINT or SIGTERM
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Parse(args)
+
+	// Load config
+	cfg := config.Load()
+	configManager := config.NewManager(cfg)
+
+	// Load repositories
+	repositories := loadRepository(cfg, cfg.AppID)
+
+	// Load app service
+	appService := app.New(repositories, cfg)
+
+	// Load Gin router setup
+	router := api.NewRouter(appService)
+
+	// Load cron jobs
+	poller, err := appstore_reviews_poller.New(cfg, appService)
+	if err != nil {
+		log.Fatalf("Failed to create poller: %v", err)
+	}
+	// Run cron jobs
+	go poller.Run(context.Background())
+	go appService.Webhooks().Run(context.Background(), 10*time.Second)
+	if digestJob := digest.FromConfig(cfg, appService); digestJob != nil {
+		go digestJob.Run(context.Background(), time.Minute)
+	}
+
+	// Hot reload config on SIGHUP or config file changes
+	configUpdates := configManager.Subscribe()
+	go configManager.Watch(context.Background(), 5*time.Second)
+	go func() {
+		for cfg := range configUpdates {
+			appService.ApplyConfig(cfg)
+			poller.UpdateConfig(cfg)
+		}
+	}()
+
+	// HTTP server
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      router,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	// Run server in goroutine
+	go func() {
+		log.Printf("🚀 Server running on port %s", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("listen error: %s\n", err)
+		}
+	}()
+
+	// gRPC server of the ReviewsService, on its own port
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("grpc listen error: %s\n", err)
+	}
+	grpcServer := rpc.NewServer(appService)
+	go func() {
+		log.Printf("🚀 gRPC server running on port %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("grpc serve error: %s\n", err)
+		}
+	}()
+
+	// Graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+	// WatchReviews streams only end with their clients, so they are cut when the timeout is up
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	log.Println("Server exited gracefully")
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// loadAppRepository loads the storage of the app given by the -app flag, the primary app by default
+func loadAppRepository(appID string) *repositories.AppReviewsRepository {
+	cfg := config.Load()
+	if appID == "" {
+		appID = cfg.AppID
+	}
+	return loadRepository(cfg, appID)
+}
+
+// loadRepository loads the storage of an app, moving the storage file of earlier versions
+// to the primary app first
+func loadRepository(cfg *config.Config, appID string) *repositories.AppReviewsRepository {
+	path := cfg.StorageFilePathFor(appID)
+	if legacyPath := cfg.LegacyStorageFilePath(); legacyPath != "" && appID == cfg.AppID {
+		if err := repositories.MigrateLegacyStorage(legacyPath, path); err != nil {
+			log.Fatalf("Failed to migrate storage file: %v", err)
+		}
+	}
+	return repositories.Load(path)
+}
+
+// exportReviews writes the stored reviews of an app to stdout or to the -o file
+func exportReviews(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	appID := flags.String("app", "", "app ID to export, defaults to the primary app")
+	output := flags.String("o", "", "output file, defaults to stdout")
+	flags.Parse(args)
+
+	repo := loadAppRepository(*appID)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("creating output file: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := repo.Export(w); err != nil {
+		log.Fatalf("exporting reviews: %v", err)
+	}
+	log.Printf("Exported %d reviews", len(repo.Reviews))
+}
+
+// importReviews adds the reviews of a JSON file, as written by export, to the storage of an app.
+// The server should not be running, it would overwrite the imported reviews on its next save.
+func importReviews(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	appID := flags.String("app", "", "app ID to import into, defaults to the primary app")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server import [-app ID] <file>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("opening input file: %v", err)
+	}
+	defer file.Close()
+
+	repo := loadAppRepository(*appID)
+	added, err := repo.Import(file)
+	if err != nil {
+		log.Fatalf("importing reviews: %v", err)
+	}
+	log.Printf("Imported %d new reviews", added)
+}
+
+// verify checks the storage of an app for integrity problems, exiting with 1 if any is found
+func verify(args []string) {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	appID := flags.String("app", "", "app ID to verify, defaults to the primary app")
+	flags.Parse(args)
+
+	repo := loadAppRepository(*appID)
+
+	issues := repo.Verify()
+	for _, issue := range issues {
+		fmt.Printf("index %d (id: %s): %s\n", issue.Index, issue.ReviewID, issue.Problem)
+	}
+
+	if len(issues) > 0 {
+		fmt.Printf("❌ %d issues found in %d reviews\n", len(issues), len(repo.Reviews))
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %d reviews verified, no issues found\n", len(repo.Reviews))
+}
+
+// stats prints a summary of the stored reviews of an app
+func stats(args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	appID := flags.String("app", "", "app ID to summarize, defaults to the primary app")
+	asJSON := flags.Bool("json", false, "print the summary as JSON")
+	includeFlagged := flags.Bool("include-flagged", false, "count the reviews flagged as spam")
+	flags.Parse(args)
+
+	summary := loadAppRepository(*appID).Stats(*includeFlagged)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(summary); err != nil {
+			log.Fatalf("encoding stats: %v", err)
+		}
+		return
+	}
+
+	fmt.Printf("Reviews:        %d\n", summary.Count)
+	if !*includeFlagged {
+		fmt.Printf("Flagged:        %d (not counted)\n", summary.FlaggedCount)
+	}
+	fmt.Printf("Average rating: %.2f\n", summary.AverageRating)
+	for rating := 5; rating >= 1; rating-- {
+		fmt.Printf("  %d stars:      %d\n", rating, summary.RatingCounts[rating])
+	}
+	fmt.Printf("Sentiment:      %.3f average, %d positive, %d neutral, %d negative\n", summary.AverageSentiment,
+		summary.SentimentCounts["positive"], summary.SentimentCounts["neutral"], summary.SentimentCounts["negative"])
+	for _, point := range summary.SentimentTrend {
+		fmt.Printf("  %s:   %+.3f (%d reviews)\n", point.Date, point.AverageSentiment, point.Count)
+	}
+	if len(summary.TagCounts) > 0 {
+		fmt.Println("Tags:")
+		for _, tag := range slices.Sorted(maps.Keys(summary.TagCounts)) {
+			fmt.Printf("  %s: %d\n", tag, summary.TagCounts[tag])
+		}
+	}
+	if summary.Newest != nil {
+		fmt.Printf("Newest review:  %s\n", summary.Newest.Format("2006-01-02T15:04:05Z07:00"))
+		fmt.Printf("Oldest review:  %s\n", summary.Oldest.Format("2006-01-02T15:04:05Z07:00"))
+	}
+}
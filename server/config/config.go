@@ -1,36 +1,203 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
 )
 
 type Config struct {
 	Port            string
+	GRPCPort        string // port of the gRPC ReviewsService, served next to the HTTP API
 	PollingInterval time.Duration
-	AppID           string
+	AppID           string   // primary app, used when a request does not specify one
+	AppIDs          []string // every tracked app, AppID included
 	StorageFilePath string
+	ConfigFilePath  string
+	ReviewSources   []string // "kind" or "kind:settings" entries of the sources the poller fetches from
+	Version         int      // incremented by the Manager on every successful reload
+
+	// Slack alerts of new reviews, disabled when no webhook URL is configured
+	SlackWebhookURL   string         // default target of alerted ratings
+	SlackAlertRatings []int          // ratings that trigger an alert
+	SlackRoutes       map[int]string // per-rating webhook URLs overriding SlackWebhookURL
+
+	// Email digests of reviews, disabled when no SMTP host or recipient is configured
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUsername     string // empty disables SMTP authentication
+	SMTPPassword     string
+	SMTPFrom         string
+	DigestRecipients []string
+	DigestSchedules  []string // "daily" and/or "weekly"
+	DigestHour       int      // UTC hour digests are sent at, weekly ones on Mondays
+
+	// API access. Requests need an API key with the role of the route unless AuthEnabled is false.
+	// Browsers may only call the API from CORSAllowedOrigins, "*" allowing every origin.
+	AuthEnabled        bool
+	CORSAllowedOrigins []string
+	TrustedProxies     []string // proxies whose X-Forwarded-For header gives the client IP
+
+	// Token bucket limits per API key, or client IP when auth is disabled. Expensive requests,
+	// like stats, keyword trends, searches and exports, also count against ExpensiveRateLimit.
+	RateLimit          RateLimit
+	ExpensiveRateLimit RateLimit
+
+	// Tag rules read from a JSON file, in addition to the ones created through the API
+	TagRulesFilePath string
+	TagRules         []models.TagRule
+}
+
+// RateLimit allows Burst requests at once, refilled at PerSecond requests per second. Zero PerSecond disables it.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
 }
 
 func Load() *Config {
-	port := os.Getenv("PORT")
-	pollingIntervalSecondsStr := os.Getenv("POLLING_INTERVAL_SECONDS")
-	storageFilePath := os.Getenv("STORAGE_FILE_PATH")
-	appID := os.Getenv("APP_ID")
+	cfg, err := read()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	cfg.Version = 1
+	logConfig(cfg)
+
+	return cfg
+}
+
+// TrackedAppIDs returns every app the poller should track
+func (c *Config) TrackedAppIDs() []string {
+	if len(c.AppIDs) == 0 {
+		return []string{c.AppID}
+	}
+	return c.AppIDs
+}
+
+// StorageFilePathFor returns the storage file path of the given app, reviews-<appId>.json
+// in the folder of StorageFilePath, so a file always holds the reviews of the same app.
+func (c *Config) StorageFilePathFor(appID string) string {
+	return c.DataFilePath("reviews-" + appID + ".json")
+}
+
+// LegacyStorageFilePath returns StorageFilePath when it isn't named reviews-<appId>.json: earlier versions
+// stored the reviews of the primary app there, whatever its ID. Empty otherwise.
+func (c *Config) LegacyStorageFilePath() string {
+	name := filepath.Base(c.StorageFilePath)
+	if c.StorageFilePath == "" || (strings.HasPrefix(name, "reviews-") && strings.HasSuffix(name, ".json")) {
+		return ""
+	}
+	return c.StorageFilePath
+}
+
+// DataFilePath returns the path of a storage file kept next to the reviews storage.
+// Empty when no storage is configured, which disables persistence.
+func (c *Config) DataFilePath(name string) string {
+	if c.StorageFilePath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(c.StorageFilePath), name)
+}
+
+// read builds a Config from the environment, overlaid by CONFIG_FILE_PATH when it is set
+func read() (*Config, error) {
+	lookup := os.Getenv
+
+	if configFilePath := os.Getenv("CONFIG_FILE_PATH"); configFilePath != "" {
+		values, err := readConfigFile(configFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		lookup = func(key string) string {
+			if value, ok := values[key]; ok {
+				return value
+			}
+			return os.Getenv(key)
+		}
+	}
+
+	cfg, err := parse(lookup)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ConfigFilePath = os.Getenv("CONFIG_FILE_PATH")
+
+	return cfg, nil
+}
+
+// readConfigFile reads a dotenv style file (KEY=VALUE per line, # for comments)
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", i+1)
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+
+	return values, nil
+}
+
+// parse builds a Config reading each variable through lookup
+func parse(lookup func(string) string) (*Config, error) {
+	port := lookup("PORT")
+	pollingIntervalSecondsStr := lookup("POLLING_INTERVAL_SECONDS")
+	storageFilePath := lookup("STORAGE_FILE_PATH")
+	appIDsStr := lookup("APP_ID")
+	slackWebhookURL := lookup("SLACK_WEBHOOK_URL")
+	slackAlertRatingsStr := lookup("SLACK_ALERT_RATINGS")
+	slackRoutesStr := lookup("SLACK_ROUTES")
+	smtpPortStr := lookup("SMTP_PORT")
+	smtpFrom := lookup("SMTP_FROM")
+	digestRecipientsStr := lookup("DIGEST_RECIPIENTS")
+	digestSchedulesStr := lookup("DIGEST_SCHEDULES")
+	digestHourStr := lookup("DIGEST_HOUR")
+	tagRulesFilePath := lookup("TAG_RULES_FILE_PATH")
+	authEnabledStr := lookup("AUTH_ENABLED")
 
 	if port == "" {
 		port = "8080"
 	}
+	grpcPort := lookup("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
 
 	if pollingIntervalSecondsStr == "" {
 		pollingIntervalSecondsStr = "30"
 	}
 
-	if appID == "" {
-		appID = "447188370" // Default to Snapchat app ID
+	// REVIEW_SOURCES is a comma separated list, the kinds are checked when the poller builds the sources
+	reviewSources := splitList(lookup("REVIEW_SOURCES"))
+	if len(reviewSources) == 0 {
+		reviewSources = []string{"appstore-rss"}
+	}
+
+	// APP_ID accepts a comma separated list, the first one is the primary app
+	appIDs := splitList(appIDsStr)
+	if len(appIDs) == 0 {
+		appIDs = []string{"447188370"} // Default to Snapchat app ID
 	}
+	appID := appIDs[0]
 
 	if storageFilePath == "" {
 		storageFilePath = "data/reviews-" + appID + ".json"
@@ -38,16 +205,213 @@ func Load() *Config {
 
 	pollingIntervalSeconds, err := strconv.Atoi(pollingIntervalSecondsStr)
 	if err != nil {
-		log.Fatalf("invalid polling interval seconds: %v", err)
+		return nil, fmt.Errorf("invalid polling interval seconds: %v", err)
+	}
+	if pollingIntervalSeconds < 1 {
+		return nil, fmt.Errorf("invalid polling interval seconds: must be at least 1, got %d", pollingIntervalSeconds)
 	}
 
-	// PRINTING CONFIG FOR DEBUGGING PURPOSES, WOULDN'T LOG SENSITIVE DATA IN PRODUCTION ON REAL APP
-	log.Printf("📦 Config loaded. PORT=%s, POLLING_INTERVAL_SECONDS=%d, APP_ID=%s, STORAGE_FILE_PATH=%s", port, pollingIntervalSeconds, appID, storageFilePath)
+	if slackAlertRatingsStr == "" {
+		slackAlertRatingsStr = "1,2"
+	}
+	var slackAlertRatings []int
+	for _, ratingStr := range strings.Split(slackAlertRatingsStr, ",") {
+		rating, err := parseRating(ratingStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slack alert ratings: %v", err)
+		}
+		slackAlertRatings = append(slackAlertRatings, rating)
+	}
+
+	// SLACK_ROUTES is a comma separated list of rating=url pairs
+	slackRoutes := make(map[int]string)
+	for _, route := range strings.Split(slackRoutesStr, ",") {
+		route = strings.TrimSpace(route)
+		if route == "" {
+			continue
+		}
+		ratingStr, url, found := strings.Cut(route, "=")
+		if !found || strings.TrimSpace(url) == "" {
+			return nil, fmt.Errorf("invalid slack route %q: expected rating=url", route)
+		}
+		rating, err := parseRating(ratingStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slack route %q: %v", route, err)
+		}
+		slackRoutes[rating] = strings.TrimSpace(url)
+	}
+
+	if smtpPortStr == "" {
+		smtpPortStr = "587"
+	}
+	smtpPort, err := strconv.Atoi(smtpPortStr)
+	if err != nil || smtpPort < 1 || smtpPort > 65535 {
+		return nil, fmt.Errorf("invalid smtp port %q", smtpPortStr)
+	}
+
+	digestRecipients := splitList(digestRecipientsStr)
+	if smtpFrom == "" && len(digestRecipients) > 0 {
+		smtpFrom = digestRecipients[0]
+	}
+
+	if digestSchedulesStr == "" {
+		digestSchedulesStr = "daily"
+	}
+	digestSchedules := splitList(digestSchedulesStr)
+	for _, schedule := range digestSchedules {
+		if schedule != "daily" && schedule != "weekly" {
+			return nil, fmt.Errorf("invalid digest schedule %q: expected daily or weekly", schedule)
+		}
+	}
+
+	if digestHourStr == "" {
+		digestHourStr = "8"
+	}
+	digestHour, err := strconv.Atoi(digestHourStr)
+	if err != nil || digestHour < 0 || digestHour > 23 {
+		return nil, fmt.Errorf("invalid digest hour %q: expected 0 to 23", digestHourStr)
+	}
+
+	if authEnabledStr == "" {
+		authEnabledStr = "true"
+	}
+	authEnabled, err := strconv.ParseBool(authEnabledStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth enabled %q: expected true or false", authEnabledStr)
+	}
+
+	trustedProxies := splitList(lookup("TRUSTED_PROXIES"))
+	for _, proxy := range trustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: expected an IP or a CIDR range", proxy)
+		}
+	}
+
+	rateLimit, err := parseRateLimit(lookup, "RATE_LIMIT", RateLimit{PerSecond: 10, Burst: 20})
+	if err != nil {
+		return nil, err
+	}
+	expensiveRateLimit, err := parseRateLimit(lookup, "RATE_LIMIT_EXPENSIVE", RateLimit{PerSecond: 0.5, Burst: 5})
+	if err != nil {
+		return nil, err
+	}
+
+	tagRules, err := readTagRulesFile(tagRulesFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading tag rules file: %w", err)
+	}
 
 	return &Config{
-		Port:            port,
-		PollingInterval: time.Duration(pollingIntervalSeconds) * time.Second,
-		AppID:           appID,
-		StorageFilePath: storageFilePath,
+		Port:               port,
+		GRPCPort:           grpcPort,
+		PollingInterval:    time.Duration(pollingIntervalSeconds) * time.Second,
+		AppID:              appID,
+		AppIDs:             appIDs,
+		StorageFilePath:    storageFilePath,
+		ReviewSources:      reviewSources,
+		SlackWebhookURL:    slackWebhookURL,
+		SlackAlertRatings:  slackAlertRatings,
+		SlackRoutes:        slackRoutes,
+		SMTPHost:           lookup("SMTP_HOST"),
+		SMTPPort:           smtpPort,
+		SMTPUsername:       lookup("SMTP_USERNAME"),
+		SMTPPassword:       lookup("SMTP_PASSWORD"),
+		SMTPFrom:           smtpFrom,
+		DigestRecipients:   digestRecipients,
+		DigestSchedules:    digestSchedules,
+		DigestHour:         digestHour,
+		AuthEnabled:        authEnabled,
+		CORSAllowedOrigins: splitList(lookup("CORS_ALLOWED_ORIGINS")),
+		TrustedProxies:     trustedProxies,
+		RateLimit:          rateLimit,
+		ExpensiveRateLimit: expensiveRateLimit,
+		TagRulesFilePath:   tagRulesFilePath,
+		TagRules:           tagRules,
+	}, nil
+}
+
+// readTagRulesFile reads a JSON array of {"tag", "type", "pattern"} rules, none when path is empty
+func readTagRulesFile(path string) ([]models.TagRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []models.TagRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		rules[i].ID = fmt.Sprintf("config-%d", i+1)
+		rules[i].Source = models.TagRuleSourceConfig
+		rules[i].CreatedAt = nil
+	}
+	return rules, nil
+}
+
+// parseRateLimit reads the <prefix>_PER_SECOND and <prefix>_BURST variables, each defaulting to the one of defaults
+func parseRateLimit(lookup func(string) string, prefix string, defaults RateLimit) (RateLimit, error) {
+	limit := defaults
+
+	if perSecondStr := lookup(prefix + "_PER_SECOND"); perSecondStr != "" {
+		perSecond, err := strconv.ParseFloat(perSecondStr, 64)
+		if err != nil || perSecond < 0 {
+			return limit, fmt.Errorf("invalid %s_PER_SECOND %q: expected a number of at least 0", prefix, perSecondStr)
+		}
+		limit.PerSecond = perSecond
+	}
+
+	if burstStr := lookup(prefix + "_BURST"); burstStr != "" {
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil || burst < 1 {
+			return limit, fmt.Errorf("invalid %s_BURST %q: expected a number of at least 1", prefix, burstStr)
+		}
+		limit.Burst = burst
+	}
+
+	return limit, nil
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseRating(ratingStr string) (int, error) {
+	rating, err := strconv.Atoi(strings.TrimSpace(ratingStr))
+	if err != nil || rating < 1 || rating > 5 {
+		return 0, fmt.Errorf("rating must be between 1 and 5, got %q", ratingStr)
+	}
+	return rating, nil
+}
+
+func logConfig(cfg *Config) {
+	// PRINTING CONFIG FOR DEBUGGING PURPOSES, WOULDN'T LOG SENSITIVE DATA IN PRODUCTION ON REAL APP
+	log.Printf("📦 Config loaded (v%d). PORT=%s, GRPC_PORT=%s, POLLING_INTERVAL_SECONDS=%d, APP_ID=%s, REVIEW_SOURCES=%s, STORAGE_FILE_PATH=%s, CONFIG_FILE_PATH=%s, SLACK_ALERTS=%t, DIGESTS=%s, TAG_RULES=%d, AUTH_ENABLED=%t, CORS_ALLOWED_ORIGINS=%s, RATE_LIMIT=%s, RATE_LIMIT_EXPENSIVE=%s",
+		cfg.Version, cfg.Port, cfg.GRPCPort, int(cfg.PollingInterval.Seconds()), strings.Join(cfg.AppIDs, ","), strings.Join(cfg.ReviewSources, ","), cfg.StorageFilePath, cfg.ConfigFilePath, cfg.SlackWebhookURL != "" || len(cfg.SlackRoutes) > 0, digestsLog(cfg), len(cfg.TagRules), cfg.AuthEnabled, strings.Join(cfg.CORSAllowedOrigins, ","), rateLimitLog(cfg.RateLimit), rateLimitLog(cfg.ExpensiveRateLimit))
+}
+
+func digestsLog(cfg *Config) string {
+	if cfg.SMTPHost == "" || len(cfg.DigestRecipients) == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%s at %02d:00 UTC to %d recipients", strings.Join(cfg.DigestSchedules, ","), cfg.DigestHour, len(cfg.DigestRecipients))
+}
+
+func rateLimitLog(limit RateLimit) string {
+	if limit.PerSecond == 0 {
+		return "off"
 	}
+	return fmt.Sprintf("%g/s burst %d", limit.PerSecond, limit.Burst)
 }
@@ -0,0 +1,469 @@
+package config
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	// Suppress logs during testing
+	log.SetOutput(io.Discard)
+
+	// Run tests
+	code := m.Run()
+
+	// Restore log output
+	log.SetOutput(os.Stderr)
+
+	// Exit with the same code as the tests
+	os.Exit(code)
+}
+
+// writeConfigFile writes a config file in a temp dir and points CONFIG_FILE_PATH to it
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "server.env")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("CONFIG_FILE_PATH", path)
+	return path
+}
+
+// TestParse_Defaults verifies the defaults used when no variable is set
+func TestParse_Defaults(t *testing.T) {
+	cfg, err := parse(func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Port != "8080" {
+		t.Errorf("Expected default port 8080, got %s", cfg.Port)
+	}
+	if cfg.PollingInterval != 30*time.Second {
+		t.Errorf("Expected default polling interval 30s, got %s", cfg.PollingInterval)
+	}
+	if cfg.AppID != "447188370" {
+		t.Errorf("Expected default app ID 447188370, got %s", cfg.AppID)
+	}
+	if cfg.StorageFilePath != "data/reviews-447188370.json" {
+		t.Errorf("Expected default storage file path, got %s", cfg.StorageFilePath)
+	}
+	if len(cfg.ReviewSources) != 1 || cfg.ReviewSources[0] != "appstore-rss" {
+		t.Errorf("Expected the App Store feed as default review source, got %v", cfg.ReviewSources)
+	}
+}
+
+// TestParse_MultipleAppIDs verifies that APP_ID accepts a comma separated list with the first one as primary
+func TestParse_MultipleAppIDs(t *testing.T) {
+	cfg, err := parse(func(key string) string {
+		if key == "APP_ID" {
+			return "111, 222,,333"
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.AppID != "111" {
+		t.Errorf("Expected primary app ID 111, got %s", cfg.AppID)
+	}
+	if len(cfg.TrackedAppIDs()) != 3 {
+		t.Errorf("Expected 3 tracked apps, got %v", cfg.TrackedAppIDs())
+	}
+	if cfg.StorageFilePathFor("222") != filepath.Join("data", "reviews-222.json") {
+		t.Errorf("Expected secondary app storage next to the primary one, got %s", cfg.StorageFilePathFor("222"))
+	}
+}
+
+// TestStorageFilePathFor_DerivedFromAppID verifies that the storage file of every app, the primary one included,
+// is named after its ID, and that other STORAGE_FILE_PATH names are reported as legacy files
+func TestStorageFilePathFor_DerivedFromAppID(t *testing.T) {
+	cfg := &Config{AppID: "222", AppIDs: []string{"222", "111"}, StorageFilePath: filepath.Join("data", "reviews-111.json")}
+	if cfg.StorageFilePathFor("222") != filepath.Join("data", "reviews-222.json") {
+		t.Errorf("Expected the primary app storage to be named after its ID, got %s", cfg.StorageFilePathFor("222"))
+	}
+	if cfg.StorageFilePathFor("111") != cfg.StorageFilePath {
+		t.Errorf("Expected the storage of the previous primary app to stay %s, got %s", cfg.StorageFilePath, cfg.StorageFilePathFor("111"))
+	}
+	if cfg.LegacyStorageFilePath() != "" {
+		t.Errorf("Expected no legacy file for a reviews-<appId>.json path, got %s", cfg.LegacyStorageFilePath())
+	}
+
+	cfg.StorageFilePath = filepath.Join("data", "reviews.json")
+	if cfg.LegacyStorageFilePath() != cfg.StorageFilePath {
+		t.Errorf("Expected %s to be a legacy file, got %q", cfg.StorageFilePath, cfg.LegacyStorageFilePath())
+	}
+}
+
+// TestParse_InvalidPollingInterval verifies that an invalid interval is reported as an error
+func TestParse_InvalidPollingInterval(t *testing.T) {
+	for _, value := range []string{"abc", "0", "-5"} {
+		_, err := parse(func(key string) string {
+			if key == "POLLING_INTERVAL_SECONDS" {
+				return value
+			}
+			return ""
+		})
+		if err == nil {
+			t.Errorf("Expected error for polling interval %q, got nil", value)
+		}
+	}
+}
+
+// TestRead_ConfigFileOverridesEnvironment verifies that config file values take precedence over env vars
+func TestRead_ConfigFileOverridesEnvironment(t *testing.T) {
+	t.Setenv("POLLING_INTERVAL_SECONDS", "60")
+	t.Setenv("APP_ID", "111")
+	writeConfigFile(t, "# comment\nPOLLING_INTERVAL_SECONDS=10\n\nSTORAGE_FILE_PATH=\"tmp/reviews.json\"\n")
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.PollingInterval != 10*time.Second {
+		t.Errorf("Expected polling interval from config file (10s), got %s", cfg.PollingInterval)
+	}
+	if cfg.AppID != "111" {
+		t.Errorf("Expected app ID from env (111), got %s", cfg.AppID)
+	}
+	if cfg.StorageFilePath != "tmp/reviews.json" {
+		t.Errorf("Expected unquoted storage file path, got %s", cfg.StorageFilePath)
+	}
+}
+
+// TestReload_AppliesChangesAndBumpsVersion verifies that a changed config becomes active with a new version
+func TestReload_AppliesChangesAndBumpsVersion(t *testing.T) {
+	t.Setenv("APP_ID", "111")
+	path := writeConfigFile(t, "POLLING_INTERVAL_SECONDS=10\n")
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg.Version = 1
+	manager := NewManager(cfg)
+	updates := manager.Subscribe()
+
+	// nothing changed yet
+	applied, err := manager.Reload()
+	if err != nil || applied {
+		t.Fatalf("Expected no reload when nothing changed, got applied=%v err=%v", applied, err)
+	}
+
+	if err := os.WriteFile(path, []byte("POLLING_INTERVAL_SECONDS=20\nAPP_ID=111,222\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite config file: %v", err)
+	}
+
+	applied, err = manager.Reload()
+	if err != nil || !applied {
+		t.Fatalf("Expected reload to be applied, got applied=%v err=%v", applied, err)
+	}
+
+	if manager.Version() != 2 {
+		t.Errorf("Expected config version 2, got %d", manager.Version())
+	}
+
+	select {
+	case updated := <-updates:
+		if updated.PollingInterval != 20*time.Second {
+			t.Errorf("Expected polling interval 20s, got %s", updated.PollingInterval)
+		}
+		if len(updated.TrackedAppIDs()) != 2 {
+			t.Errorf("Expected 2 tracked apps, got %v", updated.TrackedAppIDs())
+		}
+	default:
+		t.Error("Expected subscriber to receive the reloaded config")
+	}
+}
+
+// TestReload_KeepsActiveConfigOnError verifies that an invalid config is rejected and the active one kept
+func TestReload_KeepsActiveConfigOnError(t *testing.T) {
+	path := writeConfigFile(t, "POLLING_INTERVAL_SECONDS=10\n")
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg.Version = 1
+	manager := NewManager(cfg)
+
+	if err := os.WriteFile(path, []byte("POLLING_INTERVAL_SECONDS=not-a-number\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite config file: %v", err)
+	}
+
+	if _, err := manager.Reload(); err == nil {
+		t.Fatal("Expected error for invalid config, got nil")
+	}
+
+	if manager.Current() != cfg || manager.Version() != 1 {
+		t.Errorf("Expected active config to be kept, got version %d", manager.Version())
+	}
+}
+
+// TestReload_KeepsPort verifies that a port change is not applied since it requires a restart
+func TestReload_KeepsPort(t *testing.T) {
+	path := writeConfigFile(t, "PORT=8080\n")
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	manager := NewManager(cfg)
+
+	if err := os.WriteFile(path, []byte("PORT=9090\nPOLLING_INTERVAL_SECONDS=5\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite config file: %v", err)
+	}
+
+	if _, err := manager.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if manager.Current().Port != "8080" {
+		t.Errorf("Expected port to stay 8080, got %s", manager.Current().Port)
+	}
+}
+
+// TestReload_KeepsReviewSources verifies that REVIEW_SOURCES changes wait for a restart, the poller builds its sources once
+func TestReload_KeepsReviewSources(t *testing.T) {
+	path := writeConfigFile(t, "REVIEW_SOURCES=appstore-rss\n")
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	manager := NewManager(cfg)
+
+	if err := os.WriteFile(path, []byte("REVIEW_SOURCES=appstore-rss,csv-folder:/srv/reviews\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite config file: %v", err)
+	}
+
+	if _, err := manager.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if sources := manager.Current().ReviewSources; len(sources) != 1 || sources[0] != "appstore-rss" {
+		t.Errorf("Expected review sources to stay appstore-rss, got %v", sources)
+	}
+}
+
+// TestReload_KeepsTrustedProxies verifies that TRUSTED_PROXIES changes wait for a restart, the router sets them once
+func TestReload_KeepsTrustedProxies(t *testing.T) {
+	path := writeConfigFile(t, "TRUSTED_PROXIES=10.0.0.1\n")
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	manager := NewManager(cfg)
+
+	if err := os.WriteFile(path, []byte("TRUSTED_PROXIES=10.0.0.0/8\nPOLLING_INTERVAL_SECONDS=5\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite config file: %v", err)
+	}
+
+	if _, err := manager.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if proxies := manager.Current().TrustedProxies; len(proxies) != 1 || proxies[0] != "10.0.0.1" {
+		t.Errorf("Expected trusted proxies to stay 10.0.0.1, got %v", proxies)
+	}
+}
+
+// TestReload_IgnoresRestartOnlyChanges verifies that a reload changing only restart-only keys applies no new version
+func TestReload_IgnoresRestartOnlyChanges(t *testing.T) {
+	path := writeConfigFile(t, "PORT=8080\nREVIEW_SOURCES=appstore-rss\n")
+
+	cfg, err := read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	manager := NewManager(cfg)
+	updates := manager.Subscribe()
+
+	if err := os.WriteFile(path, []byte("PORT=9090\nREVIEW_SOURCES=appstore-rss,csv-folder:/srv/reviews\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite config file: %v", err)
+	}
+
+	applied, err := manager.Reload()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if applied || manager.Current() != cfg || manager.Version() != cfg.Version {
+		t.Errorf("Expected the active config to be kept, got applied %t with version %d", applied, manager.Version())
+	}
+	select {
+	case <-updates:
+		t.Error("Expected subscribers not to be notified")
+	default:
+	}
+}
+
+// TestParse_SlackRoutes verifies parsing of Slack alert ratings and per-rating routes
+func TestParse_SlackRoutes(t *testing.T) {
+	values := map[string]string{
+		"SLACK_WEBHOOK_URL":   "https://hooks.example.com/default",
+		"SLACK_ALERT_RATINGS": "1, 2, 3",
+		"SLACK_ROUTES":        "1=https://hooks.example.com/one-star",
+	}
+	cfg, err := parse(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cfg.SlackAlertRatings) != 3 {
+		t.Errorf("Expected 3 alert ratings, got %v", cfg.SlackAlertRatings)
+	}
+	if cfg.SlackRoutes[1] != "https://hooks.example.com/one-star" {
+		t.Errorf("Expected 1-star route, got %v", cfg.SlackRoutes)
+	}
+
+	for _, invalid := range []map[string]string{
+		{"SLACK_ALERT_RATINGS": "1,6"},
+		{"SLACK_ROUTES": "1"},
+		{"SLACK_ROUTES": "x=https://hooks.example.com"},
+	} {
+		if _, err := parse(func(key string) string { return invalid[key] }); err == nil {
+			t.Errorf("Expected error for %v, got nil", invalid)
+		}
+	}
+}
+
+// TestParse_DigestSettings verifies parsing of the SMTP and digest settings
+func TestParse_DigestSettings(t *testing.T) {
+	values := map[string]string{
+		"SMTP_HOST":         "smtp.example.com",
+		"DIGEST_RECIPIENTS": "pm@example.com, lead@example.com",
+		"DIGEST_SCHEDULES":  "daily,weekly",
+		"DIGEST_HOUR":       "9",
+	}
+	cfg, err := parse(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.SMTPPort != 587 {
+		t.Errorf("Expected default SMTP port 587, got %d", cfg.SMTPPort)
+	}
+	if len(cfg.DigestRecipients) != 2 || cfg.SMTPFrom != "pm@example.com" {
+		t.Errorf("Expected 2 recipients with the first one as sender, got %v from %s", cfg.DigestRecipients, cfg.SMTPFrom)
+	}
+	if len(cfg.DigestSchedules) != 2 || cfg.DigestHour != 9 {
+		t.Errorf("Expected daily and weekly digests at 9, got %v at %d", cfg.DigestSchedules, cfg.DigestHour)
+	}
+
+	for _, invalid := range []map[string]string{
+		{"SMTP_PORT": "smtp"},
+		{"DIGEST_SCHEDULES": "monthly"},
+		{"DIGEST_HOUR": "24"},
+	} {
+		if _, err := parse(func(key string) string { return invalid[key] }); err == nil {
+			t.Errorf("Expected error for %v, got nil", invalid)
+		}
+	}
+}
+
+// TestParse_TagRulesFile verifies that config tag rules get stable IDs and the config source
+func TestParse_TagRulesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tag-rules.json")
+	rules := `[{"tag": "billing", "type": "keyword", "pattern": "refund"}, {"id": "custom", "tag": "login", "type": "regex", "pattern": "log ?in", "source": "api"}]`
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := parse(func(key string) string {
+		if key == "TAG_RULES_FILE_PATH" {
+			return path
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cfg.TagRules) != 2 || cfg.TagRules[0].ID != "config-1" || cfg.TagRules[1].ID != "config-2" {
+		t.Fatalf("Expected 2 rules with config IDs, got %+v", cfg.TagRules)
+	}
+	if cfg.TagRules[1].Source != "config" || cfg.TagRules[1].Pattern != "log ?in" {
+		t.Errorf("Expected config source and the file pattern, got %+v", cfg.TagRules[1])
+	}
+
+	os.WriteFile(path, []byte(`{"tag": "billing"}`), 0644)
+	if _, err := parse(func(key string) string {
+		if key == "TAG_RULES_FILE_PATH" {
+			return path
+		}
+		return ""
+	}); err == nil {
+		t.Error("Expected error for a file that is not a JSON array, got nil")
+	}
+}
+
+// TestParse_AuthSettings verifies that auth is enabled by default and CORS origins are read as a list
+func TestParse_AuthSettings(t *testing.T) {
+	cfg, err := parse(func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cfg.AuthEnabled || len(cfg.CORSAllowedOrigins) != 0 {
+		t.Errorf("Expected auth enabled and no CORS origins by default, got %t %v", cfg.AuthEnabled, cfg.CORSAllowedOrigins)
+	}
+
+	values := map[string]string{
+		"AUTH_ENABLED":         "false",
+		"CORS_ALLOWED_ORIGINS": "https://reviews.example.com, http://localhost:5173",
+	}
+	cfg, err = parse(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.AuthEnabled || len(cfg.CORSAllowedOrigins) != 2 || cfg.CORSAllowedOrigins[1] != "http://localhost:5173" {
+		t.Errorf("Unexpected auth settings: %t %v", cfg.AuthEnabled, cfg.CORSAllowedOrigins)
+	}
+
+	values["AUTH_ENABLED"] = "sometimes"
+	if _, err := parse(func(key string) string { return values[key] }); err == nil {
+		t.Error("Expected error for an invalid AUTH_ENABLED, got nil")
+	}
+}
+
+// TestParse_RateLimits verifies the rate limit defaults, overrides and validation
+func TestParse_RateLimits(t *testing.T) {
+	cfg, err := parse(func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.RateLimit != (RateLimit{PerSecond: 10, Burst: 20}) || cfg.ExpensiveRateLimit != (RateLimit{PerSecond: 0.5, Burst: 5}) {
+		t.Errorf("Unexpected default rate limits: %+v %+v", cfg.RateLimit, cfg.ExpensiveRateLimit)
+	}
+
+	values := map[string]string{
+		"RATE_LIMIT_PER_SECOND":           "0",
+		"RATE_LIMIT_EXPENSIVE_PER_SECOND": "0.2",
+		"RATE_LIMIT_EXPENSIVE_BURST":      "2",
+		"TRUSTED_PROXIES":                 "10.0.0.1, 172.16.0.0/12",
+	}
+	cfg, err = parse(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.RateLimit.PerSecond != 0 || cfg.ExpensiveRateLimit != (RateLimit{PerSecond: 0.2, Burst: 2}) || len(cfg.TrustedProxies) != 2 {
+		t.Errorf("Unexpected settings: %+v %+v %v", cfg.RateLimit, cfg.ExpensiveRateLimit, cfg.TrustedProxies)
+	}
+
+	for key, value := range map[string]string{"RATE_LIMIT_BURST": "0", "RATE_LIMIT_PER_SECOND": "fast", "TRUSTED_PROXIES": "proxy.local"} {
+		if _, err := parse(func(k string) string {
+			if k == key {
+				return value
+			}
+			return ""
+		}); err == nil {
+			t.Errorf("Expected error for %s=%s, got nil", key, value)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Manager holds the active config and reloads it on SIGHUP or when the config file changes
+type Manager struct {
+	mu          sync.RWMutex
+	current     *Config
+	subscribers []chan *Config
+	fileModTime time.Time
+}
+
+// NewManager creates a Manager with cfg as the active config
+func NewManager(cfg *Config) *Manager {
+	m := &Manager{current: cfg}
+	m.fileModTime = m.configFileModTime()
+	return m
+}
+
+// Current returns the active config
+func (m *Manager) Current() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Version returns the version of the active config
+func (m *Manager) Version() int {
+	return m.Current().Version
+}
+
+// Subscribe returns a channel that receives every new config applied by Reload.
+// Slow subscribers only get the latest config, older pending ones are dropped.
+func (m *Manager) Subscribe() <-chan *Config {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch := make(chan *Config, 1)
+	m.subscribers = append(m.subscribers, ch)
+	return ch
+}
+
+// Reload re-reads the config and, if something changed, makes it the active one.
+// Returns true when a new config version was applied.
+func (m *Manager) Reload() (bool, error) {
+	cfg, err := read()
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg.Version = m.current.Version
+	if cfg.Port != m.current.Port {
+		log.Printf("⚠️ CONFIG: PORT changed from %s to %s, it only takes effect after a restart", m.current.Port, cfg.Port)
+		cfg.Port = m.current.Port
+	}
+	if !slices.Equal(cfg.ReviewSources, m.current.ReviewSources) {
+		log.Printf("⚠️ CONFIG: REVIEW_SOURCES changed from %s to %s, it only takes effect after a restart", strings.Join(m.current.ReviewSources, ","), strings.Join(cfg.ReviewSources, ","))
+		cfg.ReviewSources = m.current.ReviewSources
+	}
+	if !slices.Equal(cfg.TrustedProxies, m.current.TrustedProxies) {
+		log.Printf("⚠️ CONFIG: TRUSTED_PROXIES changed from %s to %s, it only takes effect after a restart", strings.Join(m.current.TrustedProxies, ","), strings.Join(cfg.TrustedProxies, ","))
+		cfg.TrustedProxies = m.current.TrustedProxies
+	}
+
+	// compared once the restart-only keys are reverted, changing only them applies nothing
+	if reflect.DeepEqual(cfg, m.current) {
+		return false, nil
+	}
+
+	cfg.Version = m.current.Version + 1
+	m.current = cfg
+	logConfig(cfg)
+
+	for _, ch := range m.subscribers {
+		// drop a pending config nobody read yet, the new one supersedes it
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- cfg
+	}
+
+	return true, nil
+}
+
+// Watch reloads the config on SIGHUP and whenever the config file changes.
+// The file is checked every checkInterval. Blocks until ctx is done.
+func (m *Manager) Watch(ctx context.Context, checkInterval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Printf("CONFIG: SIGHUP received, reloading config")
+			m.reload()
+		case <-ticker.C:
+			modTime := m.configFileModTime()
+			if modTime.Equal(m.fileModTime) {
+				continue
+			}
+			m.fileModTime = modTime
+			log.Printf("CONFIG: config files changed, reloading config")
+			m.reload()
+		}
+	}
+}
+
+func (m *Manager) reload() {
+	applied, err := m.Reload()
+	if err != nil {
+		// keep running with the active config, the next reload may fix it
+		log.Printf("❌ CONFIG: error reloading config, keeping v%d: %v", m.Version(), err)
+		return
+	}
+	if !applied {
+		log.Printf("CONFIG: nothing changed, keeping v%d", m.Version())
+	}
+}
+
+// configFileModTime returns the latest modification time of the config file and the files it points to
+func (m *Manager) configFileModTime() time.Time {
+	current := m.Current()
+
+	var latest time.Time
+	for _, path := range []string{current.ConfigFilePath, current.TagRulesFilePath} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
@@ -0,0 +1,271 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/ids"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+const (
+	defaultBaselineWindows = 7
+	defaultCooldownMinutes = 60
+)
+
+// ErrInvalidRule is wrapped by the errors of AddRule caused by an invalid rule
+var ErrInvalidRule = errors.New("invalid rule")
+
+// ReviewSource gives the engine access to the stored reviews, implemented by the app service
+type ReviewSource interface {
+	QueryReviews(appID string, hours int, filter repositories.ReviewFilter, sortBy string) []models.AppStoreReview
+	GetLatestReview(appID string) *models.AppStoreReview
+	GetAppID() string
+}
+
+// Sender delivers triggered alerts
+type Sender interface {
+	SendAlert(ctx context.Context, rule models.AlertRule, state models.AlertRuleState) error
+}
+
+// RuleStatus is a rule with the state of its last evaluation, nil if it was never evaluated
+type RuleStatus struct {
+	Rule  models.AlertRule       `json:"rule"`
+	State *models.AlertRuleState `json:"state"`
+}
+
+// Engine evaluates alert rules over the stored reviews and sends an alert when a rule
+// fires, at most once per cooldown
+type Engine struct {
+	repo   *repositories.AlertRulesRepository
+	source ReviewSource
+	now    func() time.Time
+
+	mu     sync.RWMutex
+	sender Sender // optional, triggered alerts are always logged
+}
+
+// New creates an Engine with the rules and states stored in repo
+func New(repo *repositories.AlertRulesRepository, source ReviewSource, sender Sender) *Engine {
+	return &Engine{
+		repo:   repo,
+		source: source,
+		sender: sender,
+		now:    time.Now,
+	}
+}
+
+// SetSender replaces the sender of the next alerts, nil to only log them
+func (e *Engine) SetSender(sender Sender) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sender = sender
+}
+
+// AddRule validates the rule, fills its defaults and stores it
+func (e *Engine) AddRule(rule models.AlertRule) (models.AlertRule, error) {
+	if rule.BaselineWindows == 0 && rule.Type == models.AlertRuleOneStarSpike {
+		rule.BaselineWindows = defaultBaselineWindows
+	}
+	if rule.CooldownMinutes == 0 {
+		rule.CooldownMinutes = defaultCooldownMinutes
+	}
+	if err := Validate(rule); err != nil {
+		return models.AlertRule{}, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+	}
+
+	rule.ID = ids.New()
+	rule.CreatedAt = e.now().UTC()
+	if rule.Name == "" {
+		rule.Name = rule.Type
+	}
+
+	if err := e.repo.AddRule(rule); err != nil {
+		return models.AlertRule{}, fmt.Errorf("saving rule: %w", err)
+	}
+	return rule, nil
+}
+
+// Validate reports the first invalid field of rule
+func Validate(rule models.AlertRule) error {
+	if rule.WindowHours < 1 {
+		return errors.New("windowHours must be at least 1")
+	}
+	if rule.CooldownMinutes < 0 {
+		return errors.New("cooldownMinutes must not be negative")
+	}
+
+	switch rule.Type {
+	case models.AlertRuleAverageRatingBelow:
+		if rule.Threshold <= 1 || rule.Threshold > 5 {
+			return errors.New("threshold must be an average rating above 1 and up to 5")
+		}
+		if rule.MinReviews < 0 {
+			return errors.New("minReviews must not be negative")
+		}
+	case models.AlertRuleOneStarSpike:
+		if rule.Threshold <= 0 {
+			return errors.New("threshold must be a positive multiple of the baseline")
+		}
+		if rule.BaselineWindows < 1 {
+			return errors.New("baselineWindows must be at least 1")
+		}
+	case models.AlertRuleNoReviews:
+	default:
+		return fmt.Errorf("unknown rule type %q", rule.Type)
+	}
+	return nil
+}
+
+// DeleteRule removes a rule and its state. Returns false if it doesn't exist
+func (e *Engine) DeleteRule(id string) (bool, error) {
+	return e.repo.DeleteRule(id)
+}
+
+// Rules returns every rule with its current state
+func (e *Engine) Rules() []RuleStatus {
+	rules := e.repo.ListRules()
+
+	statuses := make([]RuleStatus, 0, len(rules))
+	for _, rule := range rules {
+		status := RuleStatus{Rule: rule}
+		if state, ok := e.repo.GetState(rule.ID); ok {
+			status.State = &state
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Evaluate evaluates every rule, sends the alerts of the firing rules out of their cooldown
+// and stores the new states
+func (e *Engine) Evaluate(ctx context.Context) {
+	rules := e.repo.ListRules()
+	if len(rules) == 0 {
+		return
+	}
+
+	now := e.now().UTC()
+	states := make([]models.AlertRuleState, 0, len(rules))
+	for _, rule := range rules {
+		previous, _ := e.repo.GetState(rule.ID)
+		state := e.evaluateRule(rule, now)
+		state.LastTriggeredAt = previous.LastTriggeredAt
+		state.TriggerCount = previous.TriggerCount
+
+		if state.Status == models.AlertStatusFiring && e.cooldownElapsed(rule, previous, now) {
+			log.Printf("ALERTS: rule %s (%s) triggered: %s", rule.ID, rule.Name, state.Message)
+
+			if err := e.send(ctx, rule, state); err != nil {
+				// the trigger is not recorded so the alert is sent again on the next evaluation
+				log.Printf("ALERTS: error sending alert of rule %s: %v", rule.ID, err)
+			} else {
+				state.LastTriggeredAt = &now
+				state.TriggerCount++
+			}
+		}
+
+		states = append(states, state)
+	}
+
+	if err := e.repo.SaveStates(states); err != nil {
+		log.Printf("ALERTS: error saving rule states: %v", err)
+	}
+}
+
+func (e *Engine) send(ctx context.Context, rule models.AlertRule, state models.AlertRuleState) error {
+	e.mu.RLock()
+	sender := e.sender
+	e.mu.RUnlock()
+
+	if sender == nil {
+		return nil
+	}
+	return sender.SendAlert(ctx, rule, state)
+}
+
+func (e *Engine) cooldownElapsed(rule models.AlertRule, previous models.AlertRuleState, now time.Time) bool {
+	if previous.LastTriggeredAt == nil {
+		return true
+	}
+	return now.Sub(*previous.LastTriggeredAt) >= time.Duration(rule.CooldownMinutes)*time.Minute
+}
+
+// unflaggedReviews returns the reviews of the last hours with the given rating, or any if nil, spam-flagged ones left
+// out like the statistics do
+func (e *Engine) unflaggedReviews(appID string, hours int, rating *int) []models.AppStoreReview {
+	unflagged := false
+	return e.source.QueryReviews(appID, hours, repositories.ReviewFilter{Rating: rating, Flagged: &unflagged}, models.SortNewest)
+}
+
+// evaluateRule computes the status of rule at now, without its trigger fields
+func (e *Engine) evaluateRule(rule models.AlertRule, now time.Time) models.AlertRuleState {
+	appID := rule.AppID
+	if appID == "" {
+		appID = e.source.GetAppID()
+	}
+	state := models.AlertRuleState{RuleID: rule.ID, Status: models.AlertStatusOK, LastEvaluatedAt: now}
+
+	switch rule.Type {
+	case models.AlertRuleAverageRatingBelow:
+		reviews := e.unflaggedReviews(appID, rule.WindowHours, nil)
+		if len(reviews) == 0 || len(reviews) < rule.MinReviews {
+			state.Status = models.AlertStatusInsufficientData
+			state.Message = fmt.Sprintf("%d reviews in the last %dh, %d needed", len(reviews), rule.WindowHours, max(rule.MinReviews, 1))
+			return state
+		}
+
+		sum := 0
+		for _, review := range reviews {
+			sum += review.Rating
+		}
+		state.Value = float64(sum) / float64(len(reviews))
+		state.Message = fmt.Sprintf("average rating of app %s is %.2f over the last %dh (%d reviews), threshold %.2f",
+			appID, state.Value, rule.WindowHours, len(reviews), rule.Threshold)
+		if state.Value < rule.Threshold {
+			state.Status = models.AlertStatusFiring
+		}
+
+	case models.AlertRuleOneStarSpike:
+		oneStar := 1
+		window := time.Duration(rule.WindowHours) * time.Hour
+		reviews := e.unflaggedReviews(appID, rule.WindowHours*(rule.BaselineWindows+1), &oneStar)
+
+		current := 0
+		for _, review := range reviews {
+			if review.UpdatedAt.After(now.Add(-window)) {
+				current++
+			}
+		}
+		baseline := float64(len(reviews)-current) / float64(rule.BaselineWindows)
+
+		state.Value = float64(current)
+		state.Message = fmt.Sprintf("%d 1-star reviews of app %s in the last %dh, baseline %.2f per window",
+			current, appID, rule.WindowHours, baseline)
+		// a baseline under one review would fire on a single review after quiet periods
+		if float64(current) > rule.Threshold*max(baseline, 1) {
+			state.Status = models.AlertStatusFiring
+		}
+
+	case models.AlertRuleNoReviews:
+		latest := e.source.GetLatestReview(appID)
+		if latest == nil {
+			state.Status = models.AlertStatusInsufficientData
+			state.Message = fmt.Sprintf("no reviews stored for app %s", appID)
+			return state
+		}
+
+		state.Value = now.Sub(latest.UpdatedAt).Hours()
+		state.Message = fmt.Sprintf("last review of app %s was %.1fh ago, threshold %dh", appID, state.Value, rule.WindowHours)
+		if state.Value >= float64(rule.WindowHours) {
+			state.Status = models.AlertStatusFiring
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,282 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	// Suppress logs during testing
+	log.SetOutput(io.Discard)
+
+	// Run tests
+	code := m.Run()
+
+	// Restore log output
+	log.SetOutput(os.Stderr)
+
+	// Exit with the same code as the tests
+	os.Exit(code)
+}
+
+// fakeSource serves reviews relative to the real clock, like the app service does
+type fakeSource struct {
+	reviews []models.AppStoreReview // newest first
+}
+
+func (s *fakeSource) QueryReviews(appID string, hours int, filter repositories.ReviewFilter, sortBy string) []models.AppStoreReview {
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	var result []models.AppStoreReview
+	for _, review := range s.reviews {
+		if review.UpdatedAt.After(since) && filter.Matches(review) {
+			result = append(result, review)
+		}
+	}
+	return result
+}
+
+func (s *fakeSource) GetLatestReview(appID string) *models.AppStoreReview {
+	if len(s.reviews) == 0 {
+		return nil
+	}
+	return &s.reviews[0]
+}
+
+func (s *fakeSource) GetAppID() string { return "test-app" }
+
+// fakeSender records sent alerts, failing while err is set
+type fakeSender struct {
+	sent []models.AlertRuleState
+	err  error
+}
+
+func (s *fakeSender) SendAlert(ctx context.Context, rule models.AlertRule, state models.AlertRuleState) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, state)
+	return nil
+}
+
+func review(rating int, age time.Duration) models.AppStoreReview {
+	return models.AppStoreReview{ID: "review", Rating: rating, UpdatedAt: time.Now().Add(-age)}
+}
+
+func createTestEngine(t *testing.T, source *fakeSource) (*Engine, *fakeSender, string) {
+	filePath := filepath.Join(t.TempDir(), "alert-rules.json")
+	sender := &fakeSender{}
+	return New(repositories.LoadAlertRules(filePath), source, sender), sender, filePath
+}
+
+func stateOf(t *testing.T, engine *Engine, ruleID string) models.AlertRuleState {
+	for _, status := range engine.Rules() {
+		if status.Rule.ID == ruleID {
+			if status.State == nil {
+				t.Fatalf("Expected rule %s to have a state", ruleID)
+			}
+			return *status.State
+		}
+	}
+	t.Fatalf("Rule %s not found", ruleID)
+	return models.AlertRuleState{}
+}
+
+// TestEvaluate_AverageRatingBelow verifies that the rule fires when the average of the window drops under the threshold
+func TestEvaluate_AverageRatingBelow(t *testing.T) {
+	source := &fakeSource{reviews: []models.AppStoreReview{
+		review(1, time.Hour), review(2, 2*time.Hour), review(5, 30*time.Hour),
+	}}
+	engine, sender, _ := createTestEngine(t, source)
+	rule, err := engine.AddRule(models.AlertRule{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 3, MinReviews: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	engine.Evaluate(context.Background())
+
+	state := stateOf(t, engine, rule.ID)
+	if state.Status != models.AlertStatusFiring || state.Value != 1.5 {
+		t.Errorf("Expected firing with average 1.5, got %s with %v", state.Status, state.Value)
+	}
+	if len(sender.sent) != 1 {
+		t.Errorf("Expected 1 alert, got %d", len(sender.sent))
+	}
+}
+
+// TestEvaluate_AverageRatingInsufficientData verifies that the rule does not fire without enough reviews
+func TestEvaluate_AverageRatingInsufficientData(t *testing.T) {
+	engine, sender, _ := createTestEngine(t, &fakeSource{reviews: []models.AppStoreReview{review(1, time.Hour)}})
+	rule, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 3, MinReviews: 5})
+
+	engine.Evaluate(context.Background())
+
+	if state := stateOf(t, engine, rule.ID); state.Status != models.AlertStatusInsufficientData {
+		t.Errorf("Expected insufficient data, got %s", state.Status)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no alert, got %d", len(sender.sent))
+	}
+}
+
+// TestEvaluate_OneStarSpike verifies that the current window is compared to the average of the previous windows
+func TestEvaluate_OneStarSpike(t *testing.T) {
+	reviews := []models.AppStoreReview{review(1, 10*time.Minute), review(1, 20*time.Minute), review(1, 30*time.Minute), review(5, 40*time.Minute)}
+	// two 1-star reviews spread over the 4 baseline windows, baseline 0.5 per window
+	reviews = append(reviews, review(1, 90*time.Minute), review(1, 4*time.Hour+30*time.Minute))
+	engine, sender, _ := createTestEngine(t, &fakeSource{reviews: reviews})
+
+	rule, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleOneStarSpike, WindowHours: 1, Threshold: 2, BaselineWindows: 4})
+	calm, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleOneStarSpike, WindowHours: 1, Threshold: 3, BaselineWindows: 4})
+
+	engine.Evaluate(context.Background())
+
+	if state := stateOf(t, engine, rule.ID); state.Status != models.AlertStatusFiring || state.Value != 3 {
+		t.Errorf("Expected firing with 3 1-star reviews, got %s with %v", state.Status, state.Value)
+	}
+	if state := stateOf(t, engine, calm.ID); state.Status != models.AlertStatusOK {
+		t.Errorf("Expected 3 reviews not to exceed 3 times the minimum baseline, got %s", state.Status)
+	}
+	if len(sender.sent) != 1 {
+		t.Errorf("Expected 1 alert, got %d", len(sender.sent))
+	}
+}
+
+// TestEvaluate_IgnoresFlaggedReviews verifies that spam-flagged reviews count neither toward the average rating
+// nor toward a 1-star spike
+func TestEvaluate_IgnoresFlaggedReviews(t *testing.T) {
+	reviews := []models.AppStoreReview{review(5, 5*time.Minute)}
+	for i := 0; i < 5; i++ {
+		spam := review(1, time.Duration(i+10)*time.Minute)
+		spam.Flags = []models.ReviewFlag{{Reason: models.FlagRepeatedAuthor}}
+		reviews = append(reviews, spam)
+	}
+	engine, sender, _ := createTestEngine(t, &fakeSource{reviews: reviews})
+
+	average, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 3})
+	spike, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleOneStarSpike, WindowHours: 1, Threshold: 2, BaselineWindows: 4})
+
+	engine.Evaluate(context.Background())
+
+	if state := stateOf(t, engine, average.ID); state.Status != models.AlertStatusOK || state.Value != 5 {
+		t.Errorf("Expected an average of 5 without flagged reviews, got %s with %v", state.Status, state.Value)
+	}
+	if state := stateOf(t, engine, spike.ID); state.Status != models.AlertStatusOK || state.Value != 0 {
+		t.Errorf("Expected no 1-star spike without flagged reviews, got %s with %v", state.Status, state.Value)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no alert, got %d", len(sender.sent))
+	}
+}
+
+// TestEvaluate_NoReviews verifies that the rule fires when the latest review is older than the window
+func TestEvaluate_NoReviews(t *testing.T) {
+	source := &fakeSource{reviews: []models.AppStoreReview{review(4, 7*time.Hour)}}
+	engine, _, _ := createTestEngine(t, source)
+	stale, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleNoReviews, WindowHours: 6})
+	fresh, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleNoReviews, WindowHours: 12})
+
+	engine.Evaluate(context.Background())
+
+	if state := stateOf(t, engine, stale.ID); state.Status != models.AlertStatusFiring {
+		t.Errorf("Expected firing after 7h without reviews, got %s", state.Status)
+	}
+	if state := stateOf(t, engine, fresh.ID); state.Status != models.AlertStatusOK {
+		t.Errorf("Expected ok within 12h, got %s", state.Status)
+	}
+}
+
+// TestEvaluate_RespectsCooldown verifies that a firing rule alerts again only once its cooldown elapsed
+func TestEvaluate_RespectsCooldown(t *testing.T) {
+	engine, sender, _ := createTestEngine(t, &fakeSource{reviews: []models.AppStoreReview{review(1, time.Hour)}})
+	now := time.Now()
+	engine.now = func() time.Time { return now }
+	rule, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 3, CooldownMinutes: 30})
+
+	engine.Evaluate(context.Background())
+	now = now.Add(10 * time.Minute)
+	engine.Evaluate(context.Background())
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("Expected 1 alert within the cooldown, got %d", len(sender.sent))
+	}
+	if state := stateOf(t, engine, rule.ID); state.Status != models.AlertStatusFiring || state.TriggerCount != 1 {
+		t.Errorf("Expected rule still firing with 1 trigger, got %s with %d", state.Status, state.TriggerCount)
+	}
+
+	now = now.Add(30 * time.Minute)
+	engine.Evaluate(context.Background())
+	if len(sender.sent) != 2 {
+		t.Errorf("Expected a second alert after the cooldown, got %d", len(sender.sent))
+	}
+}
+
+// TestEvaluate_RetriesFailedAlerts verifies that a failed alert is not recorded as triggered
+func TestEvaluate_RetriesFailedAlerts(t *testing.T) {
+	engine, sender, _ := createTestEngine(t, &fakeSource{reviews: []models.AppStoreReview{review(1, time.Hour)}})
+	rule, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 3})
+
+	sender.err = errors.New("slack is down")
+	engine.Evaluate(context.Background())
+	if state := stateOf(t, engine, rule.ID); state.LastTriggeredAt != nil {
+		t.Fatal("Expected failed alert not to be recorded")
+	}
+
+	sender.err = nil
+	engine.Evaluate(context.Background())
+	if len(sender.sent) != 1 {
+		t.Errorf("Expected alert to be sent on the next evaluation, got %d", len(sender.sent))
+	}
+}
+
+// TestEvaluate_PersistsStateAcrossRestarts verifies that the cooldown survives a restart
+func TestEvaluate_PersistsStateAcrossRestarts(t *testing.T) {
+	source := &fakeSource{reviews: []models.AppStoreReview{review(1, time.Hour)}}
+	engine, _, filePath := createTestEngine(t, source)
+	rule, _ := engine.AddRule(models.AlertRule{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 3})
+	engine.Evaluate(context.Background())
+
+	sender := &fakeSender{}
+	restarted := New(repositories.LoadAlertRules(filePath), source, sender)
+	restarted.Evaluate(context.Background())
+
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no alert within the cooldown after restart, got %d", len(sender.sent))
+	}
+	if state := stateOf(t, restarted, rule.ID); state.TriggerCount != 1 {
+		t.Errorf("Expected trigger count to be loaded, got %d", state.TriggerCount)
+	}
+}
+
+// TestAddRule_RejectsInvalidRules verifies rule validation
+func TestAddRule_RejectsInvalidRules(t *testing.T) {
+	engine, _, _ := createTestEngine(t, &fakeSource{})
+
+	for _, rule := range []models.AlertRule{
+		{Type: "unknown", WindowHours: 1},
+		{Type: models.AlertRuleNoReviews},
+		{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 6},
+		{Type: models.AlertRuleOneStarSpike, WindowHours: 24},
+	} {
+		if _, err := engine.AddRule(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Expected ErrInvalidRule for %+v, got %v", rule, err)
+		}
+	}
+
+	rule, err := engine.AddRule(models.AlertRule{Type: models.AlertRuleOneStarSpike, WindowHours: 24, Threshold: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rule.BaselineWindows != defaultBaselineWindows || rule.CooldownMinutes != defaultCooldownMinutes || rule.ID == "" {
+		t.Errorf("Expected defaults to be filled, got %+v", rule)
+	}
+}
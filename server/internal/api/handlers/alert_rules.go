@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/alerts"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type createAlertRuleRequest struct {
+	Name            string  `json:"name"`
+	Type            string  `json:"type"`
+	AppID           string  `json:"appId"`
+	WindowHours     int     `json:"windowHours"`
+	Threshold       float64 `json:"threshold"`
+	BaselineWindows int     `json:"baselineWindows"`
+	MinReviews      int     `json:"minReviews"`
+	CooldownMinutes int     `json:"cooldownMinutes"`
+}
+
+// ListAlertRules returns every alert rule with the state of its last evaluation
+func ListAlertRules(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, AlertRuleListResponse{Rules: appService.Alerts().Rules()})
+	}
+}
+
+func CreateAlertRule(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request createAlertRuleRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			problem.InvalidBody(c)
+			return
+		}
+		if request.AppID != "" && !appService.IsTracked(request.AppID) {
+			problem.Invalid(c, problem.Body("appId", problem.FieldNotTracked, "App is not tracked"))
+			return
+		}
+
+		rule, err := appService.Alerts().AddRule(models.AlertRule{
+			Name:            request.Name,
+			Type:            request.Type,
+			AppID:           request.AppID,
+			WindowHours:     request.WindowHours,
+			Threshold:       request.Threshold,
+			BaselineWindows: request.BaselineWindows,
+			MinReviews:      request.MinReviews,
+			CooldownMinutes: request.CooldownMinutes,
+		})
+		if errors.Is(err, alerts.ErrInvalidRule) {
+			problem.Invalid(c, err)
+			return
+		}
+		if err != nil {
+			problem.Internal(c, "Error saving alert rule")
+			return
+		}
+
+		c.JSON(http.StatusCreated, rule)
+	}
+}
+
+func DeleteAlertRule(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		found, err := appService.Alerts().DeleteRule(c.Param("id"))
+		if err != nil {
+			problem.Internal(c, "Error deleting alert rule")
+			return
+		}
+		if !found {
+			problem.NotFound(c, "Alert rule not found")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type createAPIKeyRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// ListAPIKeys returns every API key, revoked ones included, without the keys themselves
+func ListAPIKeys(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, APIKeyListResponse{Keys: appService.APIKeys().List()})
+	}
+}
+
+// CreateAPIKey creates an API key, the response is the only time the key is shown
+func CreateAPIKey(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request createAPIKeyRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			problem.InvalidBody(c)
+			return
+		}
+
+		request.Name = strings.TrimSpace(request.Name)
+		if request.Name == "" {
+			problem.Invalid(c, problem.Body("name", problem.FieldRequired, "Name is required"))
+			return
+		}
+		if !slices.Contains(models.Roles, request.Role) {
+			problem.Invalid(c, problem.Body("role", problem.FieldInvalid, "Invalid role, must be one of "+strings.Join(models.Roles, ", ")))
+			return
+		}
+
+		key, secret, err := appService.APIKeys().Create(request.Name, request.Role)
+		if err != nil {
+			problem.Internal(c, "Error saving API key")
+			return
+		}
+
+		c.JSON(http.StatusCreated, CreatedAPIKeyResponse{APIKey: key, Key: secret})
+	}
+}
+
+// RevokeAPIKey revokes an API key, requests using it are rejected from then on
+func RevokeAPIKey(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		found, err := appService.APIKeys().Revoke(c.Param("id"))
+		if err != nil {
+			problem.Internal(c, "Error revoking API key")
+			return
+		}
+		if !found {
+			problem.NotFound(c, "API key not found")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// notModified sets the validators of a response: a weak ETag hashed from key, which must identify the
+// response body, and Last-Modified. When the request shows the client has that body already, it answers
+// 304 Not Modified and returns true. If-None-Match takes precedence over If-Modified-Since.
+func notModified(c *gin.Context, key string, modified time.Time) bool {
+	hash := sha256.Sum256([]byte(key))
+	etag := `W/"` + hex.EncodeToString(hash[:12]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache") // caches may store responses, but revalidate them on every use
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if !etagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		// Last-Modified has a one second resolution
+		if err != nil || modified.IsZero() || modified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey identifies the reviews matching query
+func (q reviewsQuery) cacheKey() string {
+	rating := 0
+	if q.filter.Rating != nil {
+		rating = *q.filter.Rating
+	}
+	f := q.filter
+	return fmt.Sprintf("%q %d %q %d %q %q %q %q %q %q", q.appID, q.hours, q.sort, rating, f.Sentiment, f.Tag, f.Query, f.Language, f.Status, f.Assignee)
+}
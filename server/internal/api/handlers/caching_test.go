@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// createCachingTestRouter serves reviews and stats from a repository that can be added to
+func createCachingTestRouter(t *testing.T) (*gin.Engine, *repositories.AppReviewsRepository) {
+	repo := repositories.Load(filepath.Join(t.TempDir(), "reviews.json"))
+	if _, err := repo.AddBatch(createSentimentTestReviews()); err != nil {
+		t.Fatalf("Failed to add reviews: %v", err)
+	}
+	appService := app.New(repo, &config.Config{AppID: "test-app-id"})
+
+	r := gin.New()
+	r.GET("/reviews", ListReviews(appService))
+	r.GET("/reviews/stats", ReviewsStats(appService))
+	return r, repo
+}
+
+// TestListReviews_ConditionalRequests verifies the validators of listings and the 304 answers to conditional requests
+func TestListReviews_ConditionalRequests(t *testing.T) {
+	r, repo := createCachingTestRouter(t)
+
+	w := doRequest(r, "/reviews?hours=48", nil)
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+	if w.Code != http.StatusOK || etag == "" || lastModified == "" {
+		t.Fatalf("Expected status 200 with ETag and Last-Modified, got %d %q %q", w.Code, etag, lastModified)
+	}
+
+	w = doRequest(r, "/reviews?hours=48", map[string]string{"If-None-Match": etag})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected status 304 with no body for a matching If-None-Match, got %d (%d bytes)", w.Code, w.Body.Len())
+	}
+	if w.Header().Get("ETag") != etag {
+		t.Errorf("Expected the 304 to carry ETag %s, got %s", etag, w.Header().Get("ETag"))
+	}
+	if w := doRequest(r, "/reviews?hours=48", map[string]string{"If-Modified-Since": lastModified}); w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304 for If-Modified-Since at Last-Modified, got %d", w.Code)
+	}
+
+	// another filter or format is another body
+	if w := doRequest(r, "/reviews?hours=48&rating=5", nil); w.Header().Get("ETag") == etag {
+		t.Errorf("Expected another ETag for another filter")
+	}
+	if w := doRequest(r, "/reviews?hours=48&format=csv", map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for the same filter in CSV, got %d", w.Code)
+	}
+
+	if _, err := repo.AddBatch(models.AppStoreReviews{{ID: "review-5", Title: "New", Rating: 5, UpdatedAt: time.Now().UTC()}}); err != nil {
+		t.Fatalf("Failed to add a review: %v", err)
+	}
+	w = doRequest(r, "/reviews?hours=48", map[string]string{"If-None-Match": etag})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected status 200 with a new ETag once a review is added, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+	if reviews := decodeReviews(t, w.Body.Bytes()); len(reviews) != 5 {
+		t.Errorf("Expected the 5 reviews, got %d", len(reviews))
+	}
+}
+
+// TestReviewsStats_ConditionalRequests verifies that stats answer 304 until reviews change
+func TestReviewsStats_ConditionalRequests(t *testing.T) {
+	r, repo := createCachingTestRouter(t)
+
+	w := doRequest(r, "/reviews/stats", nil)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected status 200 with an ETag, got %d %q", w.Code, etag)
+	}
+	if w := doRequest(r, "/reviews/stats", map[string]string{"If-None-Match": `"other", ` + etag}); w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304 for an If-None-Match listing the ETag, got %d", w.Code)
+	}
+	if w := doRequest(r, "/reviews/stats?includeFlagged=true", map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for other parameters, got %d", w.Code)
+	}
+
+	if _, err := repo.AddBatch(models.AppStoreReviews{{ID: "review-5", Rating: 1, UpdatedAt: time.Now().UTC()}}); err != nil {
+		t.Fatalf("Failed to add a review: %v", err)
+	}
+	if w := doRequest(r, "/reviews/stats", map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 once a review is added, got %d", w.Code)
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mimeAtom = "application/atom+xml"
+	mimeRSS  = "application/rss+xml"
+
+	// feedEntryIDPrefix starts the IDs of feed entries, which must never change for the same review
+	feedEntryIDPrefix = "urn:appstore-reviews:review:"
+)
+
+// Atom 1.0 (RFC 4287) documents
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Author     atomPerson     `xml:"author"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// RSS 2.0 documents
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	SelfLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        rssGUID  `xml:"guid"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Author      string   `xml:"dc:creator"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// ReviewsAtomFeed publishes the reviews matching the GET /reviews parameters as an Atom feed
+func ReviewsAtomFeed(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, reviews, updated, ok := feedReviews(c, appService, "atom")
+		if !ok {
+			return
+		}
+
+		self := requestURL(c)
+		feed := atomFeed{
+			ID:      self,
+			Title:   feedTitle(query),
+			Updated: updated.Format(time.RFC3339),
+			Links: []atomLink{
+				{Rel: "self", Type: mimeAtom, Href: self},
+				{Rel: "alternate", Type: "text/html", Href: appStoreURL(query.appID)},
+			},
+			Entries: make([]atomEntry, 0, len(reviews)),
+		}
+		for _, review := range reviews {
+			feed.Entries = append(feed.Entries, atomEntry{
+				ID:         reviewEntryID(query.appID, review),
+				Title:      entryTitle(review),
+				Updated:    review.UpdatedAt.UTC().Format(time.RFC3339),
+				Author:     atomPerson{Name: review.Author},
+				Link:       atomLink{Rel: "alternate", Type: "text/html", Href: appStoreURL(query.appID)},
+				Categories: entryCategories(review, func(term string) atomCategory { return atomCategory{Term: term} }),
+				Content:    atomContent{Type: "text", Text: review.Content},
+			})
+		}
+		writeFeed(c, mimeAtom, feed)
+	}
+}
+
+// ReviewsRSSFeed publishes the reviews matching the GET /reviews parameters as an RSS 2.0 feed
+func ReviewsRSSFeed(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, reviews, updated, ok := feedReviews(c, appService, "rss")
+		if !ok {
+			return
+		}
+
+		feed := rssFeed{
+			Version: "2.0",
+			AtomNS:  "http://www.w3.org/2005/Atom",
+			DCNS:    "http://purl.org/dc/elements/1.1/",
+			Channel: rssChannel{
+				Title:         feedTitle(query),
+				Link:          appStoreURL(query.appID),
+				Description:   "Reviews of the App Store app " + query.appID,
+				LastBuildDate: updated.Format(time.RFC1123Z),
+				SelfLink:      atomLink{Rel: "self", Type: mimeRSS, Href: requestURL(c)},
+				Items:         make([]rssItem, 0, len(reviews)),
+			},
+		}
+		for _, review := range reviews {
+			feed.Channel.Items = append(feed.Channel.Items, rssItem{
+				GUID:        rssGUID{Value: reviewEntryID(query.appID, review)},
+				Title:       entryTitle(review),
+				Link:        appStoreURL(query.appID),
+				Description: review.Content,
+				Author:      review.Author,
+				PubDate:     review.UpdatedAt.UTC().Format(time.RFC1123Z),
+				Categories:  entryCategories(review, func(term string) string { return term }),
+			})
+		}
+		writeFeed(c, mimeRSS, feed)
+	}
+}
+
+// feedReviews reads the reviews filters and returns the matching reviews with when the feed last changed.
+// It writes a 400 response for invalid filters and a 304 when the client has the feed already, returning false.
+func feedReviews(c *gin.Context, appService *app.App, format string) (reviewsQuery, []models.AppStoreReview, time.Time, bool) {
+	query, ok := parseReviewsQuery(c, appService)
+	if !ok {
+		return query, nil, time.Time{}, false
+	}
+
+	version, modified, _ := appService.ReviewsVersion(query.appID, query.hours)
+	if notModified(c, version+" "+format+" "+query.cacheKey(), modified) {
+		return query, nil, time.Time{}, false
+	}
+
+	reviews := appService.QueryReviews(query.appID, query.hours, query.filter, query.sort)
+	updated := modified
+	for _, review := range reviews {
+		if review.UpdatedAt.After(updated) {
+			updated = review.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	return query, reviews, updated.UTC(), true
+}
+
+func writeFeed(c *gin.Context, contentType string, feed any) {
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+// reviewEntryID identifies a review in every feed it shows up in, so readers don't show it twice
+func reviewEntryID(appID string, review models.AppStoreReview) string {
+	return feedEntryIDPrefix + appID + ":" + review.ID
+}
+
+func feedTitle(query reviewsQuery) string {
+	title := "App Store reviews of app " + query.appID
+	var filters []string
+	if query.filter.Rating != nil {
+		filters = append(filters, "rating "+strconv.Itoa(*query.filter.Rating))
+	}
+	for _, filter := range []string{query.filter.Sentiment, query.filter.Tag, query.filter.Language, query.filter.Status, query.filter.Assignee} {
+		if filter != "" {
+			filters = append(filters, filter)
+		}
+	}
+	if query.filter.Query != "" {
+		filters = append(filters, strconv.Quote(query.filter.Query))
+	}
+	if len(filters) > 0 {
+		title += " (" + strings.Join(filters, ", ") + ")"
+	}
+	return title
+}
+
+// entryTitle shows the rating as stars before the review title, e.g. ★★☆☆☆ Crashes on login
+func entryTitle(review models.AppStoreReview) string {
+	rating := min(max(review.Rating, 0), 5)
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating) + " " + review.Title
+}
+
+// entryCategories lists the rating, sentiment and tags of a review as feed categories
+func entryCategories[T any](review models.AppStoreReview, category func(term string) T) []T {
+	categories := []T{category("rating:" + strconv.Itoa(review.Rating))}
+	if review.SentimentLabel != "" {
+		categories = append(categories, category("sentiment:"+review.SentimentLabel))
+	}
+	for _, tag := range review.Tags {
+		categories = append(categories, category("tag:"+tag))
+	}
+	return categories
+}
+
+func appStoreURL(appID string) string {
+	return "https://apps.apple.com/app/id" + appID + "?see-all=reviews"
+}
+
+// requestURL rebuilds the absolute URL of the request, for the self link of feeds
+func requestURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.RequestURI()
+}
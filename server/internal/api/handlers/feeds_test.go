@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+func createFeedTestRouter() *gin.Engine {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "review-1", Title: "Crashes <always> & forever", Content: "Lost my data, <script>alert(1)</script> \x01 \"twice\"", Author: "Ana & Bo", Rating: 1, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Slow", Content: "Takes ages", Author: "User2", Rating: 2, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-3", Title: "Great", Content: "Love it", Author: "User3", Rating: 5, UpdatedAt: now.Add(-3 * time.Hour)},
+	}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: reviews}, &config.Config{AppID: "test-app-id"})
+
+	r := gin.New()
+	r.GET("/feeds/reviews.atom", ReviewsAtomFeed(appService))
+	r.GET("/feeds/reviews.rss", ReviewsRSSFeed(appService))
+	return r
+}
+
+// TestReviewsAtomFeed verifies that the Atom feed is valid XML with stable entry IDs, timestamps and escaped content
+func TestReviewsAtomFeed(t *testing.T) {
+	r := createFeedTestRouter()
+
+	w := doRequest(r, "/feeds/reviews.atom?rating=1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/atom+xml") {
+		t.Errorf("Expected an Atom content type, got %s", w.Header().Get("Content-Type"))
+	}
+	if body := w.Body.String(); strings.Contains(body, "<script>") || !strings.Contains(body, "&lt;script&gt;") {
+		t.Errorf("Expected the content to be escaped, got %s", body)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("Expected valid XML, got %v", err)
+	}
+	if feed.XMLName.Space != "http://www.w3.org/2005/Atom" || feed.ID == "" || !strings.Contains(feed.Title, "rating 1") {
+		t.Errorf("Unexpected feed: %s %q %q", feed.XMLName.Space, feed.ID, feed.Title)
+	}
+	if _, err := time.Parse(time.RFC3339, feed.Updated); err != nil {
+		t.Errorf("Expected an RFC 3339 updated timestamp, got %q", feed.Updated)
+	}
+	if len(feed.Entries) != 1 {
+		t.Fatalf("Expected the 1 star review only, got %d entries", len(feed.Entries))
+	}
+
+	entry := feed.Entries[0]
+	if entry.ID != "urn:appstore-reviews:review:test-app-id:review-1" {
+		t.Errorf("Unexpected entry ID %s", entry.ID)
+	}
+	if entry.Title != "★☆☆☆☆ Crashes <always> & forever" || entry.Author.Name != "Ana & Bo" {
+		t.Errorf("Expected the title and author back unescaped, got %q by %q", entry.Title, entry.Author.Name)
+	}
+	if entry.Content.Type != "text" || !strings.HasPrefix(entry.Content.Text, "Lost my data, <script>alert(1)</script>") {
+		t.Errorf("Expected the content back as text, got %s %q", entry.Content.Type, entry.Content.Text)
+	}
+	if updated, err := time.Parse(time.RFC3339, entry.Updated); err != nil || time.Since(updated) > 2*time.Hour {
+		t.Errorf("Expected the review time as updated, got %q", entry.Updated)
+	}
+	if len(entry.Categories) == 0 || entry.Categories[0].Term != "rating:1" {
+		t.Errorf("Expected the rating as a category, got %+v", entry.Categories)
+	}
+
+	if w := doRequest(r, "/feeds/reviews.atom?rating=9", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid rating, got %d", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if w := doRequest(r, "/feeds/reviews.atom?rating=1", map[string]string{"If-None-Match": etag}); w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304 for the same feed, got %d", w.Code)
+	}
+	if w := doRequest(r, "/feeds/reviews.rss?rating=1", map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for the RSS feed with the Atom ETag, got %d", w.Code)
+	}
+}
+
+// TestReviewsRSSFeed verifies that the RSS feed is valid XML with the same entry IDs as the Atom one
+func TestReviewsRSSFeed(t *testing.T) {
+	r := createFeedTestRouter()
+
+	w := doRequest(r, "/feeds/reviews.rss?rating=1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/rss+xml") {
+		t.Errorf("Expected an RSS content type, got %s", w.Header().Get("Content-Type"))
+	}
+
+	var feed struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Channel struct {
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				GUID struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				Title       string `xml:"title"`
+				Description string `xml:"description"`
+				Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				PubDate     string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("Expected valid XML, got %v", err)
+	}
+	if feed.Version != "2.0" {
+		t.Errorf("Expected RSS 2.0, got %q", feed.Version)
+	}
+	if _, err := time.Parse(time.RFC1123Z, feed.Channel.LastBuildDate); err != nil {
+		t.Errorf("Expected an RFC 1123 lastBuildDate, got %q", feed.Channel.LastBuildDate)
+	}
+	if len(feed.Channel.Items) != 1 {
+		t.Fatalf("Expected the 1 star review only, got %d items", len(feed.Channel.Items))
+	}
+
+	item := feed.Channel.Items[0]
+	if item.GUID.Value != "urn:appstore-reviews:review:test-app-id:review-1" || item.GUID.IsPermaLink != "false" {
+		t.Errorf("Expected the entry ID as a guid that is not a link, got %+v", item.GUID)
+	}
+	if item.Creator != "Ana & Bo" || !strings.Contains(item.Description, "<script>") {
+		t.Errorf("Expected the author and content back unescaped, got %q %q", item.Creator, item.Description)
+	}
+	if pubDate, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil || time.Since(pubDate) > 2*time.Hour {
+		t.Errorf("Expected the review time as pubDate, got %q", item.PubDate)
+	}
+
+	all := doRequest(r, "/feeds/reviews.rss", nil).Body.String()
+	if strings.Count(all, "<item>") != 3 {
+		t.Errorf("Expected every review without filters, got %d items", strings.Count(all, "<item>"))
+	}
+}
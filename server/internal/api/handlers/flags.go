@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListFlaggedReviews returns the stored reviews of an app flagged as spam, newest first, with the count per reason.
+// The reason parameter keeps the reviews flagged for that reason only.
+func ListFlaggedReviews(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+		reason := c.Query("reason")
+
+		reasonCounts := map[string]int{}
+		reviews := make([]models.AppStoreReview, 0)
+		for _, review := range appService.FlaggedReviews(appID) {
+			matches := reason == ""
+			for _, flag := range review.Flags {
+				reasonCounts[flag.Reason]++
+				matches = matches || flag.Reason == reason
+			}
+			if matches {
+				reviews = append(reviews, review)
+			}
+		}
+
+		c.JSON(http.StatusOK, FlaggedReviewsResponse{
+			AppID:        appID,
+			Count:        len(reviews),
+			ReasonCounts: reasonCounts,
+			Reviews:      reviews,
+		})
+	}
+}
+
+// UnflagReview clears the spam flags of a review, which then counts in the statistics and is not flagged again
+func UnflagReview(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+
+		found, err := appService.UnflagReview(appID, c.Param("id"))
+		if err != nil {
+			problem.Internal(c, "Error unflagging review")
+			return
+		}
+		if !found {
+			problem.NotFound(c, "Flagged review not found")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
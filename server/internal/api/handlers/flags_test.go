@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+const spamContent = "Download the best coins generator now, it gives you unlimited free coins every single day"
+
+// createFlagsTestRouter serves stats and flags of reviews where review-2 copies review-3 and review-1 has a link
+func createFlagsTestRouter() (*gin.Engine, *app.App) {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "review-1", Title: "Free coins", Content: "Get them at coins.xyz", Author: "a", Rating: 5, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Wow", Content: spamContent, Author: "b", Rating: 5, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-3", Title: "Wow", Content: spamContent, Author: "c", Rating: 5, UpdatedAt: now.Add(-3 * time.Hour)},
+		{ID: "review-4", Title: "Crashes", Content: "Crashes on start", Author: "d", Rating: 1, UpdatedAt: now.Add(-4 * time.Hour)},
+	}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: reviews}, &config.Config{AppID: "test-app-id"})
+
+	r := gin.New()
+	r.GET("/reviews/stats", ReviewsStats(appService))
+	r.GET("/admin/flags", ListFlaggedReviews(appService))
+	r.DELETE("/admin/flags/:id", UnflagReview(appService))
+	return r, appService
+}
+
+// TestReviewsStats_ExcludesFlaggedReviews verifies that flagged reviews only count when includeFlagged is set
+func TestReviewsStats_ExcludesFlaggedReviews(t *testing.T) {
+	r, _ := createFlagsTestRouter()
+
+	decode := func(url string) repositories.ReviewStats {
+		var response struct {
+			Stats repositories.ReviewStats `json:"stats"`
+		}
+		w := doRequest(r, url, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d", url, w.Code)
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Stats
+	}
+
+	stats := decode("/reviews/stats")
+	if stats.Count != 2 || stats.FlaggedCount != 2 || stats.AverageRating != 3 {
+		t.Errorf("Expected 2 unflagged reviews averaging 3, got %d (%d flagged) averaging %v", stats.Count, stats.FlaggedCount, stats.AverageRating)
+	}
+
+	stats = decode("/reviews/stats?includeFlagged=true")
+	if stats.Count != 4 || stats.FlaggedCount != 2 || stats.AverageRating != 4 {
+		t.Errorf("Expected all 4 reviews averaging 4, got %d averaging %v", stats.Count, stats.AverageRating)
+	}
+
+	if w := doRequest(r, "/reviews/stats?includeFlagged=maybe", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid includeFlagged, got %d", w.Code)
+	}
+}
+
+// TestListFlaggedReviews verifies the flagged reviews with their reasons, including the ones flagged when added
+func TestListFlaggedReviews(t *testing.T) {
+	r, appService := createFlagsTestRouter()
+
+	added, err := appService.AddReviews("test-app-id", []models.AppStoreReview{
+		{ID: "review-5", Title: "Wow", Content: spamContent + " again", Author: "e", Rating: 5, UpdatedAt: time.Now().UTC()},
+	})
+	if err != nil || len(added) != 1 || !added[0].IsFlagged() {
+		t.Fatalf("Expected new copy to be flagged when added, got %+v (%v)", added, err)
+	}
+
+	var response struct {
+		Count        int                     `json:"count"`
+		ReasonCounts map[string]int          `json:"reasonCounts"`
+		Reviews      []models.AppStoreReview `json:"reviews"`
+	}
+	json.Unmarshal(doRequest(r, "/admin/flags", nil).Body.Bytes(), &response)
+	if ids := reviewIDs(response.Reviews); !slices.Equal(ids, []string{"review-5", "review-1", "review-2"}) {
+		t.Errorf("Expected the flagged reviews newest first, got %v", ids)
+	}
+	if response.ReasonCounts[models.FlagDuplicate] != 2 || response.ReasonCounts[models.FlagLink] != 1 {
+		t.Errorf("Unexpected reason counts: %v", response.ReasonCounts)
+	}
+	if flag := response.Reviews[2].Flags[0]; flag.Reason != models.FlagDuplicate || flag.Detail != "100% similar to review review-3" {
+		t.Errorf("Expected review-2 to be a duplicate of review-3, got %+v", flag)
+	}
+
+	json.Unmarshal(doRequest(r, "/admin/flags?reason=link", nil).Body.Bytes(), &response)
+	if response.Count != 1 || response.Reviews[0].ID != "review-1" {
+		t.Errorf("Expected only the review with a link, got %v", reviewIDs(response.Reviews))
+	}
+}
+
+// TestUnflagReview verifies that an unflagged review counts in the statistics and stays unflagged when reloaded
+func TestUnflagReview(t *testing.T) {
+	r, appService := createFlagsTestRouter()
+
+	if w := doJSONRequest(r, http.MethodDelete, "/admin/flags/review-2", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if w := doJSONRequest(r, http.MethodDelete, "/admin/flags/review-2", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a review no longer flagged, got %d", w.Code)
+	}
+	if w := doJSONRequest(r, http.MethodDelete, "/admin/flags/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing review, got %d", w.Code)
+	}
+
+	stats, _ := appService.Stats("test-app-id", false)
+	if stats.Count != 3 || stats.FlaggedCount != 1 {
+		t.Errorf("Expected 3 unflagged reviews and 1 flagged, got %d and %d", stats.Count, stats.FlaggedCount)
+	}
+
+	var stored models.AppStoreReviews
+	for _, id := range []string{"review-1", "review-2", "review-3", "review-4"} {
+		stored = append(stored, *appService.GetReview("test-app-id", id))
+	}
+	reloaded := app.New(&repositories.AppReviewsRepository{Reviews: stored}, &config.Config{AppID: "test-app-id"})
+	if ids := reviewIDs(reloaded.FlaggedReviews("test-app-id")); !slices.Equal(ids, []string{"review-1"}) {
+		t.Errorf("Expected only review-1 to be flagged again when reloaded, got %v", ids)
+	}
+}
@@ -0,0 +1,362 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// GraphQL error codes, in the extensions of errors
+const (
+	graphQLCodeParseFailed      = "GRAPHQL_PARSE_FAILED"
+	graphQLCodeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+	graphQLCodeTooComplex       = "QUERY_TOO_COMPLEX"
+	graphQLCodeBadUserInput     = "BAD_USER_INPUT"
+)
+
+// graphQLRequest is a GraphQL request, as sent in the body of a POST
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the result of a request. Data is left out when the request failed before execution,
+// and is null when an error reached the root.
+type graphQLResponse struct {
+	Data   json.RawMessage            `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// graphQLError is an error of a resolver with a code and, for bad input, the argument it is about
+type graphQLError struct {
+	message    string
+	extensions map[string]any
+}
+
+func (e *graphQLError) Error() string {
+	return e.message
+}
+
+// Extensions are added to the error in the response
+func (e *graphQLError) Extensions() map[string]any {
+	return e.extensions
+}
+
+// newGraphQLInputError returns a BAD_USER_INPUT error on field, an argument or a field of an input object
+func newGraphQLInputError(field, message string) error {
+	return &graphQLError{message: message, extensions: map[string]any{"code": graphQLCodeBadUserInput, "field": field}}
+}
+
+// GraphQL runs GraphQL queries on the reviews, sent as the JSON body of a POST or the query, variables
+// and operationName parameters of a GET. Queries answer 200 with their data and errors, as GraphQL does;
+// requests without a query are problems like those of the other routes.
+func GraphQL(appService *app.App) gin.HandlerFunc {
+	schema := newReviewsSchema(appService)
+	return func(c *gin.Context) {
+		var request graphQLRequest
+		if c.Request.Method == http.MethodPost {
+			if err := c.ShouldBindJSON(&request); err != nil {
+				problem.InvalidBody(c)
+				return
+			}
+			if request.Query == "" {
+				problem.Invalid(c, problem.Body("query", problem.FieldRequired, "Query is required"))
+				return
+			}
+		} else {
+			request.Query = c.Query("query")
+			request.OperationName = c.Query("operationName")
+			if request.Query == "" {
+				problem.Invalid(c, problem.Query("query", problem.FieldRequired, "Query is required"))
+				return
+			}
+			if variables := c.Query("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+					problem.Invalid(c, problem.Query("variables", problem.FieldInvalid, "Invalid variables parameter, must be a JSON object"))
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusOK, executeGraphQL(c, schema, request))
+	}
+}
+
+// executeGraphQL parses a query, holds it to the depth and complexity limits, then validates and runs it.
+// Queries failing any of these steps are rejected without running any resolver.
+func executeGraphQL(c *gin.Context, schema graphql.Schema, request graphQLRequest) graphQLResponse {
+	src := source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return graphQLResponse{Errors: withCode(gqlerrors.FormatErrors(err), graphQLCodeParseFailed)}
+	}
+
+	op := graphQLOperation(doc, request.OperationName)
+	if op != nil {
+		cost := measureGraphQLOperation(schema, doc, op, request.Variables)
+		if cost.complexity > graphQLMaxComplexity {
+			e := operationError(src, op, fmt.Sprintf("Query complexity exceeds the limit of %d, ask for fewer fields or smaller pages", graphQLMaxComplexity))
+			e.Extensions = map[string]any{"code": graphQLCodeTooComplex, "maxComplexity": graphQLMaxComplexity}
+			return graphQLResponse{Errors: []gqlerrors.FormattedError{e}}
+		}
+		if cost.depth > graphQLMaxDepth {
+			e := operationError(src, op, fmt.Sprintf("Query is nested %d fields deep, more than the limit of %d", cost.depth, graphQLMaxDepth))
+			e.Extensions = map[string]any{"code": graphQLCodeValidationFailed}
+			return graphQLResponse{Errors: []gqlerrors.FormattedError{e}}
+		}
+	}
+
+	if validation := graphql.ValidateDocument(&schema, doc, nil); !validation.IsValid {
+		return graphQLResponse{Errors: withCode(validation.Errors, graphQLCodeValidationFailed)}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       c.Request.Context(),
+	})
+	response := graphQLResponse{Errors: result.Errors}
+	// errors without a path come from the variables or operation name, before any resolver ran
+	executed := result.Data != nil || slices.ContainsFunc(result.Errors, func(e gqlerrors.FormattedError) bool { return len(e.Path) > 0 })
+	if !executed {
+		response.Errors = withCode(response.Errors, graphQLCodeBadUserInput)
+		return response
+	}
+	var data any
+	if fields, ok := result.Data.(map[string]any); ok && op != nil {
+		data = inQueryOrder(fields, []*ast.SelectionSet{op.SelectionSet}, doc)
+	}
+	response.Data, _ = json.Marshal(data)
+	return response
+}
+
+// graphQLOperation returns the operation of doc named name, or its only one when name is empty.
+// Nil when there is none, execution reports it.
+func graphQLOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+func operationError(src *source.Source, op *ast.OperationDefinition, message string) gqlerrors.FormattedError {
+	e := gqlerrors.FormattedError{Message: message}
+	if op.Loc != nil {
+		e.Locations = []location.SourceLocation{location.GetLocation(src, op.Loc.Start)}
+	}
+	return e
+}
+
+// withCode sets the code of the errors that have none
+func withCode(errs []gqlerrors.FormattedError, code string) []gqlerrors.FormattedError {
+	for i := range errs {
+		if errs[i].Extensions == nil {
+			errs[i].Extensions = map[string]any{}
+		}
+		if _, ok := errs[i].Extensions["code"]; !ok {
+			errs[i].Extensions["code"] = code
+		}
+	}
+	return errs
+}
+
+// orderedFields is an object of the response, keeping the fields in the order the query asked for them,
+// as GraphQL does, where a map would sort them
+type orderedFields struct {
+	keys   []string
+	values []any
+}
+
+func (f *orderedFields) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range f.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		b.Write(k)
+		b.WriteByte(':')
+		v, err := json.Marshal(f.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// inQueryOrder orders the objects of a result value as the selection sets it was resolved from
+func inQueryOrder(value any, sets []*ast.SelectionSet, doc *ast.Document) any {
+	switch value := value.(type) {
+	case map[string]any:
+		keys, subsets := collectResponseKeys(sets, doc, nil, map[string][]*ast.SelectionSet{})
+		fields := &orderedFields{}
+		for _, key := range keys {
+			if v, ok := value[key]; ok {
+				fields.keys = append(fields.keys, key)
+				fields.values = append(fields.values, inQueryOrder(v, subsets[key], doc))
+			}
+		}
+		return fields
+	case []any:
+		items := make([]any, len(value))
+		for i, item := range value {
+			items[i] = inQueryOrder(item, sets, doc)
+		}
+		return items
+	}
+	return value
+}
+
+// collectResponseKeys lists the response keys of selection sets in order, through fragments, with the
+// selection sets of each key
+func collectResponseKeys(sets []*ast.SelectionSet, doc *ast.Document, keys []string, subsets map[string][]*ast.SelectionSet) ([]string, map[string][]*ast.SelectionSet) {
+	for _, set := range sets {
+		if set == nil {
+			continue
+		}
+		for _, selection := range set.Selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				key := s.Name.Value
+				if s.Alias != nil {
+					key = s.Alias.Value
+				}
+				if _, seen := subsets[key]; !seen {
+					keys = append(keys, key)
+				}
+				subsets[key] = append(subsets[key], s.SelectionSet)
+			case *ast.InlineFragment:
+				keys, subsets = collectResponseKeys([]*ast.SelectionSet{s.SelectionSet}, doc, keys, subsets)
+			case *ast.FragmentSpread:
+				for _, definition := range doc.Definitions {
+					if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name.Value == s.Name.Value {
+						keys, subsets = collectResponseKeys([]*ast.SelectionSet{fragment.SelectionSet}, doc, keys, subsets)
+					}
+				}
+			}
+		}
+	}
+	return keys, subsets
+}
+
+// GraphQLSchema serves the GraphQL schema in the schema definition language, clients can generate their types from it
+func GraphQLSchema(appService *app.App) gin.HandlerFunc {
+	sdl := []byte(schemaSDL(newReviewsSchema(appService)))
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", sdl)
+	}
+}
+
+// schemaSDL writes the types of schema in the schema definition language, the query type first and the
+// others, their fields and arguments in alphabetical order
+func schemaSDL(schema graphql.Schema) string {
+	var names []string
+	for name, t := range schema.TypeMap() {
+		if _, scalar := t.(*graphql.Scalar); !scalar && !strings.HasPrefix(name, "__") && name != schema.QueryType().Name() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	names = append([]string{schema.QueryType().Name()}, names...)
+
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		switch t := schema.Type(name).(type) {
+		case *graphql.Enum:
+			writeDescription(&b, t.Description(), "")
+			fmt.Fprintf(&b, "enum %s {\n", t.Name())
+			values := t.Values()
+			slices.SortFunc(values, func(a, b *graphql.EnumValueDefinition) int { return strings.Compare(a.Name, b.Name) })
+			for _, v := range values {
+				fmt.Fprintf(&b, "  %s\n", v.Name)
+			}
+			b.WriteString("}\n")
+		case *graphql.Object:
+			writeDescription(&b, t.Description(), "")
+			fmt.Fprintf(&b, "type %s {\n", t.Name())
+			fields := t.Fields()
+			for _, fieldName := range slices.Sorted(maps.Keys(fields)) {
+				f := fields[fieldName]
+				writeDescription(&b, f.Description, "  ")
+				fmt.Fprintf(&b, "  %s", f.Name)
+				if len(f.Args) > 0 {
+					args := slices.Clone(f.Args)
+					slices.SortFunc(args, func(a, b *graphql.Argument) int { return strings.Compare(a.Name(), b.Name()) })
+					sdl := make([]string, len(args))
+					for i, arg := range args {
+						sdl[i] = inputValueSDL(arg.Name(), arg.Type, arg.DefaultValue)
+					}
+					fmt.Fprintf(&b, "(%s)", strings.Join(sdl, ", "))
+				}
+				fmt.Fprintf(&b, ": %s\n", f.Type)
+			}
+			b.WriteString("}\n")
+		case *graphql.InputObject:
+			writeDescription(&b, t.Description(), "")
+			fmt.Fprintf(&b, "input %s {\n", t.Name())
+			fields := t.Fields()
+			for _, fieldName := range slices.Sorted(maps.Keys(fields)) {
+				f := fields[fieldName]
+				writeDescription(&b, f.Description(), "  ")
+				fmt.Fprintf(&b, "  %s\n", inputValueSDL(f.Name(), f.Type, f.DefaultValue))
+			}
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, description, indent string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s%s\n", indent, strconv.Quote(description))
+	}
+}
+
+func inputValueSDL(name string, t graphql.Input, defaultValue any) string {
+	s := name + ": " + t.String()
+	if defaultValue == nil {
+		return s
+	}
+	if _, isEnum := graphql.GetNullable(t).(*graphql.Enum); !isEnum {
+		if text, isString := defaultValue.(string); isString {
+			return s + " = " + strconv.Quote(text)
+		}
+	}
+	return s + " = " + fmt.Sprint(defaultValue)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// graphQLCost is the complexity of a selection and how many fields deep it goes
+type graphQLCost struct {
+	complexity int
+	depth      int
+}
+
+// graphQLLimits measures operations against graphQLMaxDepth and graphQLMaxComplexity before they are validated
+// and run. Each fragment is measured once, and complexities stop adding up past the limit, so that documents
+// spreading fragments into each other can't make measuring them slow.
+type graphQLLimits struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	defaults  map[string]ast.Value // default values of the variables of the operation
+	measured  map[string]graphQLCost
+}
+
+// measureGraphQLOperation returns the cost of op, an operation of doc run with variables
+func measureGraphQLOperation(schema graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, variables map[string]any) graphQLCost {
+	l := &graphQLLimits{
+		schema:    schema,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		defaults:  map[string]ast.Value{},
+		measured:  map[string]graphQLCost{},
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			l.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	for _, definition := range op.VariableDefinitions {
+		if definition.DefaultValue != nil {
+			l.defaults[definition.Variable.Name.Value] = definition.DefaultValue
+		}
+	}
+	return l.selections(schema.QueryType(), op.SelectionSet)
+}
+
+// selections measures a selection set on object, nil when the type is unknown. It stops at the first
+// selection bringing the complexity over the limit.
+func (l *graphQLLimits) selections(object *graphql.Object, set *ast.SelectionSet) graphQLCost {
+	total := graphQLCost{}
+	if set == nil {
+		return total
+	}
+	for _, selection := range set.Selections {
+		var cost graphQLCost
+		switch s := selection.(type) {
+		case *ast.Field:
+			cost = l.field(object, s)
+		case *ast.FragmentSpread:
+			cost = l.fragment(s.Name.Value)
+		case *ast.InlineFragment:
+			inner := object
+			if s.TypeCondition != nil {
+				inner = l.object(s.TypeCondition.Name.Value)
+			}
+			cost = l.selections(inner, s.SelectionSet)
+		}
+		total.complexity = capComplexity(total.complexity + cost.complexity)
+		total.depth = max(total.depth, cost.depth)
+		if total.complexity > graphQLMaxComplexity {
+			break
+		}
+	}
+	return total
+}
+
+// fragment measures a fragment once, spreads of it reuse the first measure. Unknown fragments and cycles
+// cost nothing here, validation rejects them.
+func (l *graphQLLimits) fragment(name string) graphQLCost {
+	if cost, ok := l.measured[name]; ok {
+		return cost
+	}
+	fragment, ok := l.fragments[name]
+	if !ok {
+		return graphQLCost{}
+	}
+	l.measured[name] = graphQLCost{}
+	cost := l.selections(l.object(fragment.TypeCondition.Name.Value), fragment.SelectionSet)
+	l.measured[name] = cost
+	return cost
+}
+
+// field measures a field of object. Fields unknown to the schema, introspection ones included, cost 1 and
+// their selections are not measured.
+func (l *graphQLLimits) field(object *graphql.Object, field *ast.Field) graphQLCost {
+	cost := graphQLCost{complexity: 1, depth: 1}
+	if object == nil {
+		return cost
+	}
+	definition, ok := object.Fields()[field.Name.Value]
+	if !ok {
+		return cost
+	}
+	key := object.Name() + "." + field.Name.Value
+	if fieldCost, ok := graphQLCosts[key]; ok {
+		cost.complexity = fieldCost
+	}
+	child, ok := graphql.GetNamed(definition.Type).(*graphql.Object)
+	if !ok {
+		return cost
+	}
+
+	inner := l.selections(child, field.SelectionSet)
+	multiplier := 1
+	if argument, ok := graphQLCostMultipliers[key]; ok {
+		multiplier = max(l.intArgument(definition, field, argument), 1)
+	}
+	cost.complexity = capComplexity(cost.complexity + multiplier*inner.complexity)
+	cost.depth += inner.depth
+	return cost
+}
+
+// intArgument reads an Int argument of field from its literal, its variable or its default, 0 if it isn't an Int
+func (l *graphQLLimits) intArgument(definition *graphql.FieldDefinition, field *ast.Field, name string) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != name {
+			continue
+		}
+		value := argument.Value
+		if variable, ok := value.(*ast.Variable); ok {
+			if given, ok := l.variables[variable.Name.Value]; ok && given != nil {
+				n, _ := given.(float64)
+				return int(max(min(n, math.MaxInt32), math.MinInt32))
+			}
+			value = l.defaults[variable.Name.Value]
+		}
+		if literal, ok := value.(*ast.IntValue); ok {
+			n, _ := strconv.ParseInt(literal.Value, 10, 32)
+			return int(n)
+		}
+	}
+	for _, argument := range definition.Args {
+		if argument.Name() == name {
+			n, _ := argument.DefaultValue.(int)
+			return n
+		}
+	}
+	return 0
+}
+
+// object returns the object type named name, nil for other types
+func (l *graphQLLimits) object(name string) *graphql.Object {
+	object, _ := l.schema.Type(name).(*graphql.Object)
+	return object
+}
+
+// capComplexity keeps complexities just over the limit once they pass it, so multiplying them can't overflow
+func capComplexity(complexity int) int {
+	return min(complexity, graphQLMaxComplexity+1)
+}
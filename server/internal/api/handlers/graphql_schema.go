@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"cmp"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sentiment"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	// graphQLMaxDepth and graphQLMaxComplexity bound the work of a query. Fields cost 1 unless graphQLCosts
+	// says otherwise, and the fields selected on a page of reviews count once per review of the page.
+	graphQLMaxDepth      = 10
+	graphQLMaxComplexity = 2000
+
+	graphQLDefaultPageSize = 20
+	graphQLMaxPageSize     = 100
+)
+
+// graphQLCosts are the fields costing more than 1, by type and field name
+var graphQLCosts = map[string]int{"Query.reviews": 5, "Query.stats": 10, "Review.triage": 2}
+
+// graphQLCostMultipliers name the Int argument multiplying the complexity of the selections of a field,
+// the size of a page
+var graphQLCostMultipliers = map[string]string{"Query.reviews": "first"}
+
+// reviewNode is a review with the app it belongs to, which its triage is looked up with
+type reviewNode struct {
+	appID  string
+	review models.AppStoreReview
+}
+
+// reviewConnection is a page of reviews, in the shape of Relay cursor connections
+type reviewConnection struct {
+	appID      string
+	page       []models.AppStoreReview
+	totalCount int
+	hasNext    bool
+	hasPrev    bool
+}
+
+// newReviewsSchema returns the GraphQL schema of the reviews, resolved by the app service.
+// It panics when the schema is invalid, a programming error.
+func newReviewsSchema(appService *app.App) graphql.Schema {
+	sentimentEnum := newGraphQLEnum("Sentiment", "", sentiment.Positive, sentiment.Neutral, sentiment.Negative)
+	statusEnum := newGraphQLEnum("TriageStatus", "", models.TriageStatuses...)
+	sortEnum := newGraphQLEnum("ReviewSort", "Order of reviews, newest first by default", validSorts...)
+
+	triageNoteType := graphql.NewObject(graphql.ObjectConfig{Name: "TriageNote", Fields: graphql.Fields{
+		"id":        {Type: nonNull(graphql.ID), Resolve: note(func(n models.TriageNote) any { return n.ID })},
+		"author":    {Type: graphql.String, Resolve: note(func(n models.TriageNote) any { return optional(n.Author) })},
+		"text":      {Type: nonNull(graphql.String), Resolve: note(func(n models.TriageNote) any { return n.Text })},
+		"createdAt": {Type: nonNull(graphql.String), Resolve: note(func(n models.TriageNote) any { return formatTime(n.CreatedAt) })},
+	}})
+	triageType := graphql.NewObject(graphql.ObjectConfig{Name: "Triage", Fields: graphql.Fields{
+		"status":   {Type: nonNull(statusEnum), Resolve: triage(func(t models.Triage) any { return strings.ToUpper(t.Status) })},
+		"assignee": {Type: graphql.String, Resolve: triage(func(t models.Triage) any { return optional(t.Assignee) })},
+		"labels":   {Type: nonNullList(graphql.String), Resolve: triage(func(t models.Triage) any { return nonNilStrings(t.Labels) })},
+		"notes":    {Type: nonNullList(triageNoteType), Resolve: triage(func(t models.Triage) any { return t.Notes })},
+		"updatedAt": {Type: graphql.String, Resolve: triage(func(t models.Triage) any {
+			if t.UpdatedAt == nil {
+				return nil
+			}
+			return formatTime(*t.UpdatedAt)
+		})},
+	}})
+	flagType := graphql.NewObject(graphql.ObjectConfig{Name: "ReviewFlag", Description: "A reason the review looks like spam", Fields: graphql.Fields{
+		"reason": {Type: nonNull(graphql.String), Resolve: flag(func(f models.ReviewFlag) any { return f.Reason })},
+		"detail": {Type: nonNull(graphql.String), Resolve: flag(func(f models.ReviewFlag) any { return f.Detail })},
+	}})
+	reviewType := graphql.NewObject(graphql.ObjectConfig{Name: "Review", Fields: graphql.Fields{
+		"id":             {Type: nonNull(graphql.ID), Resolve: review(func(n reviewNode) any { return n.review.ID })},
+		"appId":          {Type: nonNull(graphql.String), Resolve: review(func(n reviewNode) any { return n.appID })},
+		"title":          {Type: nonNull(graphql.String), Resolve: review(func(n reviewNode) any { return n.review.Title })},
+		"content":        {Type: nonNull(graphql.String), Resolve: review(func(n reviewNode) any { return n.review.Content })},
+		"author":         {Type: nonNull(graphql.String), Resolve: review(func(n reviewNode) any { return n.review.Author })},
+		"rating":         {Type: nonNull(graphql.Int), Resolve: review(func(n reviewNode) any { return n.review.Rating })},
+		"updatedAt":      {Type: nonNull(graphql.String), Resolve: review(func(n reviewNode) any { return formatTime(n.review.UpdatedAt) })},
+		"sentiment":      {Description: "Lexicon score of the title and content, from -1 to 1", Type: nonNull(graphql.Float), Resolve: review(func(n reviewNode) any { return n.review.Sentiment })},
+		"sentimentLabel": {Type: sentimentEnum, Resolve: review(func(n reviewNode) any { return optional(strings.ToUpper(n.review.SentimentLabel)) })},
+		"language":       {Description: "ISO 639-1 code, und when undetermined", Type: graphql.String, Resolve: review(func(n reviewNode) any { return optional(n.review.Language) })},
+		"tags":           {Type: nonNullList(graphql.String), Resolve: review(func(n reviewNode) any { return nonNilStrings(n.review.Tags) })},
+		"flags":          {Type: nonNullList(flagType), Resolve: review(func(n reviewNode) any { return n.review.Flags })},
+		"triage": {Type: nonNull(triageType), Resolve: func(p graphql.ResolveParams) (any, error) {
+			n := p.Source.(reviewNode)
+			t, err := appService.Triage(n.appID, n.review.ID)
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		}},
+	}})
+	edgeType := graphql.NewObject(graphql.ObjectConfig{Name: "ReviewEdge", Fields: graphql.Fields{
+		"cursor": {Type: nonNull(graphql.String), Resolve: review(func(n reviewNode) any { return encodeCursor(n.review.ID) })},
+		"node":   {Type: nonNull(reviewType), Resolve: func(p graphql.ResolveParams) (any, error) { return p.Source, nil }},
+	}})
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{Name: "PageInfo", Fields: graphql.Fields{
+		"hasNextPage":     {Type: nonNull(graphql.Boolean), Resolve: connection(func(c reviewConnection) any { return c.hasNext })},
+		"hasPreviousPage": {Type: nonNull(graphql.Boolean), Resolve: connection(func(c reviewConnection) any { return c.hasPrev })},
+		"startCursor": {Type: graphql.String, Resolve: connection(func(c reviewConnection) any {
+			if len(c.page) == 0 {
+				return nil
+			}
+			return encodeCursor(c.page[0].ID)
+		})},
+		"endCursor": {Type: graphql.String, Resolve: connection(func(c reviewConnection) any {
+			if len(c.page) == 0 {
+				return nil
+			}
+			return encodeCursor(c.page[len(c.page)-1].ID)
+		})},
+	}})
+	connectionType := graphql.NewObject(graphql.ObjectConfig{Name: "ReviewConnection", Fields: graphql.Fields{
+		"totalCount": {Description: "Reviews matching the filter, on every page", Type: nonNull(graphql.Int), Resolve: connection(func(c reviewConnection) any { return c.totalCount })},
+		"edges":      {Type: nonNullList(edgeType), Resolve: connection(reviewNodes)},
+		"nodes":      {Type: nonNullList(reviewType), Resolve: connection(reviewNodes)},
+		"pageInfo":   {Type: nonNull(pageInfoType), Resolve: func(p graphql.ResolveParams) (any, error) { return p.Source, nil }},
+	}})
+
+	countType := func(name, key string, keyType graphql.Output) *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{Name: name, Fields: graphql.Fields{
+			key:     {Type: nonNull(keyType)},
+			"count": {Type: nonNull(graphql.Int)},
+		}})
+	}
+	sentimentPointType := graphql.NewObject(graphql.ObjectConfig{Name: "SentimentPoint", Description: "The sentiment of the reviews of a day (UTC)", Fields: graphql.Fields{
+		"date":             {Type: nonNull(graphql.String)},
+		"count":            {Type: nonNull(graphql.Int)},
+		"averageSentiment": {Type: nonNull(graphql.Float)},
+	}})
+	statsType := graphql.NewObject(graphql.ObjectConfig{Name: "ReviewStats", Fields: graphql.Fields{
+		"count":            {Type: nonNull(graphql.Int)},
+		"flaggedCount":     {Type: nonNull(graphql.Int)},
+		"averageRating":    {Type: nonNull(graphql.Float)},
+		"ratingCounts":     {Type: nonNullList(countType("RatingCount", "rating", graphql.Int))},
+		"oldest":           {Type: graphql.String},
+		"newest":           {Type: graphql.String},
+		"averageSentiment": {Type: nonNull(graphql.Float)},
+		"sentimentCounts":  {Type: nonNullList(countType("SentimentCount", "sentiment", sentimentEnum))},
+		"sentimentTrend":   {Description: "One point per day of the last two weeks, oldest first", Type: nonNullList(sentimentPointType)},
+		"tagCounts":        {Type: nonNullList(countType("TagCount", "tag", graphql.String))},
+	}})
+	versionType := graphql.NewObject(graphql.ObjectConfig{Name: "DataVersion", Description: "Changes whenever the reviews or their triage change, like the ETag of GET /v1/reviews", Fields: graphql.Fields{
+		"version":    {Type: nonNull(graphql.String)},
+		"modifiedAt": {Type: graphql.String},
+	}})
+
+	filterInput := graphql.NewInputObject(graphql.InputObjectConfig{Name: "ReviewFilter", Description: "The filters of GET /v1/reviews", Fields: graphql.InputObjectConfigFieldMap{
+		"rating":    {Type: graphql.Int},
+		"sentiment": {Type: sentimentEnum},
+		"tag":       {Type: graphql.String},
+		"q":         {Description: "Words the title or content must all contain, accents ignored", Type: graphql.String},
+		"lang":      {Type: graphql.String},
+		"status":    {Type: statusEnum},
+		"assignee":  {Type: graphql.String},
+	}})
+	appIDArg := &graphql.ArgumentConfig{Description: "A tracked app, the primary one by default", Type: graphql.String}
+
+	query := graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+		"reviews": {
+			Description: "A page of the reviews of the last hours matching a filter",
+			Type:        nonNull(connectionType),
+			Args: graphql.FieldConfigArgument{
+				"appId":  appIDArg,
+				"hours":  {Type: graphql.Int, DefaultValue: 48},
+				"filter": {Type: filterInput},
+				"sort":   {Type: sortEnum, DefaultValue: strings.ToUpper(models.SortNewest)},
+				"first":  {Description: "Page size, at most 100", Type: graphql.Int, DefaultValue: graphQLDefaultPageSize},
+				"after":  {Description: "endCursor of the previous page", Type: graphql.String},
+			},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return resolveReviews(appService, p.Args)
+			},
+		},
+		"review": {
+			Type: reviewType,
+			Args: graphql.FieldConfigArgument{"id": {Type: nonNull(graphql.ID)}, "appId": appIDArg},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				appID, err := graphQLAppID(appService, p.Args)
+				if err != nil {
+					return nil, err
+				}
+				stored := appService.GetReview(appID, p.Args["id"].(string))
+				if stored == nil {
+					return nil, nil
+				}
+				return reviewNode{appID: appID, review: *stored}, nil
+			},
+		},
+		"stats": {
+			Description: "Statistics of every stored review, flagged ones left out unless included",
+			Type:        nonNull(statsType),
+			Args:        graphql.FieldConfigArgument{"appId": appIDArg, "includeFlagged": {Type: graphql.Boolean, DefaultValue: false}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				appID, err := graphQLAppID(appService, p.Args)
+				if err != nil {
+					return nil, err
+				}
+				includeFlagged, _ := p.Args["includeFlagged"].(bool)
+				stats, _ := appService.Stats(appID, includeFlagged)
+				return statsFields(stats), nil
+			},
+		},
+		"version": {
+			Description: "The data version of the reviews of an app, of those of the last hours when given",
+			Type:        nonNull(versionType),
+			Args:        graphql.FieldConfigArgument{"appId": appIDArg, "hours": {Type: graphql.Int}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				appID, err := graphQLAppID(appService, p.Args)
+				if err != nil {
+					return nil, err
+				}
+				hours, _ := p.Args["hours"].(int)
+				version, modified, _ := appService.ReviewsVersion(appID, max(hours, 0))
+				fields := map[string]any{"version": version}
+				if !modified.IsZero() {
+					fields["modifiedAt"] = formatTime(modified)
+				}
+				return fields, nil
+			},
+		},
+		"apps": {
+			Description: "The IDs of the tracked apps, primary first",
+			Type:        nonNullList(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return appService.TrackedAppIDs(), nil
+			},
+		},
+	}})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		panic("invalid GraphQL schema: " + err.Error())
+	}
+	return schema
+}
+
+// resolveReviews lists reviews with the same parameters and validation as GET /reviews, then pages them
+func resolveReviews(appService *app.App, args map[string]any) (any, error) {
+	values := url.Values{}
+	filter, _ := args["filter"].(map[string]any)
+	for name, value := range filter {
+		switch value := value.(type) {
+		case int:
+			values.Set(name, strconv.Itoa(value))
+		case string:
+			if name == "sentiment" || name == "status" {
+				value = strings.ToLower(value) // enum values
+			}
+			values.Set(name, value)
+		}
+	}
+	if appID, ok := args["appId"].(string); ok {
+		values.Set("appId", appID)
+	}
+	if hours, ok := args["hours"].(int); ok {
+		values.Set("hours", strconv.Itoa(hours))
+	}
+	if sort, ok := args["sort"].(string); ok {
+		values.Set("sort", strings.ToLower(sort))
+	}
+
+	query, err := parseReviewsValues(values, appService)
+	if err != nil {
+		return nil, graphQLInputError(err, "filter")
+	}
+	first, ok := args["first"].(int)
+	if !ok {
+		first = graphQLDefaultPageSize // null
+	}
+	if first < 0 || first > graphQLMaxPageSize {
+		return nil, newGraphQLInputError("first", "first must be between 0 and 100")
+	}
+
+	reviews := appService.QueryReviews(query.appID, query.hours, query.filter, query.sort)
+	start := 0
+	if after, ok := args["after"].(string); ok {
+		id, decoded := decodeCursor(after)
+		index := slices.IndexFunc(reviews, func(r models.AppStoreReview) bool { return r.ID == id })
+		if !decoded || index < 0 {
+			return nil, newGraphQLInputError("after", "after is not a cursor of a review of this listing, it may have left the hours window")
+		}
+		start = index + 1
+	}
+	end := min(start+first, len(reviews))
+	return reviewConnection{
+		appID:      query.appID,
+		page:       reviews[start:end],
+		totalCount: len(reviews),
+		hasNext:    end < len(reviews),
+		hasPrev:    start > 0,
+	}, nil
+}
+
+// graphQLAppID reads the appId argument, defaulting to the primary app
+func graphQLAppID(appService *app.App, args map[string]any) (string, error) {
+	appID, _ := args["appId"].(string)
+	appID, err := parseAppID(appID, appService)
+	if err != nil {
+		return "", graphQLInputError(err, "")
+	}
+	return appID, nil
+}
+
+// graphQLInputError turns the problem.FieldError of a query parameter into a GraphQL error on the argument
+// of the same name. Parameters other than appId, hours and sort are fields of the input object named inputObject.
+func graphQLInputError(err error, inputObject string) error {
+	var fieldError *problem.FieldError
+	if !errors.As(err, &fieldError) {
+		return err
+	}
+	field := fieldError.Field
+	if inputObject != "" && field != "appId" && field != "hours" && field != "sort" {
+		field = inputObject + "." + field
+	}
+	return newGraphQLInputError(field, fieldError.Message)
+}
+
+func reviewNodes(c reviewConnection) any {
+	nodes := make([]reviewNode, len(c.page))
+	for i, r := range c.page {
+		nodes[i] = reviewNode{appID: c.appID, review: r}
+	}
+	return nodes
+}
+
+// statsFields converts stats to the fields of ReviewStats, with the counts as lists in a stable order
+func statsFields(stats repositories.ReviewStats) map[string]any {
+	ratingCounts := []map[string]any{}
+	for _, rating := range validRatings {
+		ratingCounts = append(ratingCounts, map[string]any{"rating": rating, "count": stats.RatingCounts[rating]})
+	}
+	sentimentCounts := []map[string]any{}
+	for _, label := range []string{sentiment.Positive, sentiment.Neutral, sentiment.Negative} {
+		sentimentCounts = append(sentimentCounts, map[string]any{"sentiment": strings.ToUpper(label), "count": stats.SentimentCounts[label]})
+	}
+	tags := make([]string, 0, len(stats.TagCounts))
+	for tag := range stats.TagCounts {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, func(a, b string) int {
+		return cmp.Or(stats.TagCounts[b]-stats.TagCounts[a], strings.Compare(a, b))
+	})
+	tagCounts := []map[string]any{}
+	for _, tag := range tags {
+		tagCounts = append(tagCounts, map[string]any{"tag": tag, "count": stats.TagCounts[tag]})
+	}
+	trend := []map[string]any{}
+	for _, point := range stats.SentimentTrend {
+		trend = append(trend, map[string]any{"date": point.Date, "count": point.Count, "averageSentiment": point.AverageSentiment})
+	}
+
+	fields := map[string]any{
+		"count":            stats.Count,
+		"flaggedCount":     stats.FlaggedCount,
+		"averageRating":    stats.AverageRating,
+		"ratingCounts":     ratingCounts,
+		"averageSentiment": stats.AverageSentiment,
+		"sentimentCounts":  sentimentCounts,
+		"sentimentTrend":   trend,
+		"tagCounts":        tagCounts,
+	}
+	if stats.Oldest != nil {
+		fields["oldest"] = formatTime(*stats.Oldest)
+	}
+	if stats.Newest != nil {
+		fields["newest"] = formatTime(*stats.Newest)
+	}
+	return fields
+}
+
+// Cursors are opaque to clients, they hold the ID of the last review of a page
+func encodeCursor(reviewID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("review:" + reviewID))
+}
+
+func decodeCursor(cursor string) (string, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", false
+	}
+	return strings.CutPrefix(string(decoded), "review:")
+}
+
+// Resolvers of fields read from the source of their object
+func review(get func(n reviewNode) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(reviewNode)), nil }
+}
+
+func connection(get func(c reviewConnection) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(reviewConnection)), nil }
+}
+
+func triage(get func(t models.Triage) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(models.Triage)), nil }
+}
+
+func note(get func(n models.TriageNote) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(models.TriageNote)), nil }
+}
+
+func flag(get func(f models.ReviewFlag) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(models.ReviewFlag)), nil }
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// optional returns nil for empty strings, which are null in the schema
+func optional(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// newGraphQLEnum returns an enum of values in upper case, which resolvers receive and return as they are
+func newGraphQLEnum(name, description string, values ...string) *graphql.Enum {
+	config := graphql.EnumValueConfigMap{}
+	for _, v := range values {
+		config[strings.ToUpper(v)] = &graphql.EnumValueConfig{Value: strings.ToUpper(v)}
+	}
+	return graphql.NewEnum(graphql.EnumConfig{Name: name, Description: description, Values: config})
+}
+
+func nonNull(t graphql.Type) *graphql.NonNull {
+	return graphql.NewNonNull(t)
+}
+
+// nonNullList returns a list that is never null of values that are never null
+func nonNullList(t graphql.Type) *graphql.NonNull {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
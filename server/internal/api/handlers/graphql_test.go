@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+func createGraphQLTestRouter() *gin.Engine {
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: createSentimentTestReviews()}, &config.Config{AppID: "test-app-id"})
+
+	r := gin.New()
+	r.GET("/graphql", GraphQL(appService))
+	r.POST("/graphql", GraphQL(appService))
+	r.GET("/graphql/schema", GraphQLSchema(appService))
+	return r
+}
+
+type graphQLTestResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, r *gin.Engine, query string, variables map[string]any) graphQLTestResponse {
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response graphQLTestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response
+}
+
+// TestGraphQL_ReviewsPagination verifies that cursors walk through the pages of the filtered reviews
+func TestGraphQL_ReviewsPagination(t *testing.T) {
+	r := createGraphQLTestRouter()
+	query := `query Page($after: String) {
+		reviews(first: 2, after: $after, sort: OLDEST) {
+			totalCount
+			edges { cursor node { id rating } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	var ids []string
+	var after any
+	for page := 0; page < 3; page++ {
+		response := postGraphQL(t, r, query, map[string]any{"after": after})
+		if len(response.Errors) > 0 {
+			t.Fatalf("Expected no errors, got %+v", response.Errors)
+		}
+		var data struct {
+			TotalCount int `json:"totalCount"`
+			Edges      []struct {
+				Cursor string `json:"cursor"`
+				Node   struct {
+					ID string `json:"id"`
+				} `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		}
+		if err := json.Unmarshal(response.Data["reviews"], &data); err != nil {
+			t.Fatalf("Failed to decode reviews: %v", err)
+		}
+		if data.TotalCount != 4 {
+			t.Errorf("Expected a total count of 4, got %d", data.TotalCount)
+		}
+		for _, edge := range data.Edges {
+			ids = append(ids, edge.Node.ID)
+		}
+		if !data.PageInfo.HasNextPage {
+			if data.PageInfo.EndCursor != data.Edges[len(data.Edges)-1].Cursor {
+				t.Errorf("Expected the end cursor to be the cursor of the last edge")
+			}
+			break
+		}
+		after = data.PageInfo.EndCursor
+	}
+
+	if strings.Join(ids, ",") != "review-4,review-3,review-2,review-1" {
+		t.Errorf("Expected every review once, oldest first, got %v", ids)
+	}
+}
+
+// TestGraphQL_FieldSelection verifies that only the selected fields are returned, in the order asked, with the triage of reviews
+func TestGraphQL_FieldSelection(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	w := doRequest(r, "/graphql?query="+url.QueryEscape(`{ review(id: "review-2") { title id sentimentLabel triage { status labels } } }`), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	expected := `{"data":{"review":{"title":"Buggy","id":"review-2","sentimentLabel":"NEGATIVE","triage":{"status":"NEW","labels":[]}}}}`
+	if w.Body.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, w.Body.String())
+	}
+
+	response := postGraphQL(t, r, `{ review(id: "missing") { id } apps }`, nil)
+	if string(response.Data["review"]) != "null" || string(response.Data["apps"]) != `["test-app-id"]` {
+		t.Errorf("Expected a null review and the tracked apps, got %s %s", response.Data["review"], response.Data["apps"])
+	}
+}
+
+// TestGraphQL_Stats verifies the statistics and the data version of the reviews
+func TestGraphQL_Stats(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	response := postGraphQL(t, r, `{ stats { count ratingCounts { rating count } sentimentCounts { sentiment count } } version { version } }`, nil)
+	if len(response.Errors) > 0 {
+		t.Fatalf("Expected no errors, got %+v", response.Errors)
+	}
+	expected := `{"count":4,"ratingCounts":[{"rating":1,"count":0},{"rating":2,"count":1},{"rating":3,"count":1},{"rating":4,"count":1},{"rating":5,"count":1}],"sentimentCounts":[{"sentiment":"POSITIVE","count":1},{"sentiment":"NEUTRAL","count":1},{"sentiment":"NEGATIVE","count":2}]}`
+	if string(response.Data["stats"]) != expected {
+		t.Errorf("Expected %s, got %s", expected, response.Data["stats"])
+	}
+	if !strings.Contains(string(response.Data["version"]), `"version":"`) {
+		t.Errorf("Expected a data version, got %s", response.Data["version"])
+	}
+}
+
+// TestGraphQL_InvalidInput verifies that invalid filters and cursors are user input errors naming the argument
+func TestGraphQL_InvalidInput(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	tests := []struct {
+		query string
+		field string
+	}{
+		{`{ reviews(filter: {rating: 7}) { totalCount } }`, "filter.rating"},
+		{`{ reviews(hours: 500) { totalCount } }`, "hours"},
+		{`{ reviews(appId: "other") { totalCount } }`, "appId"},
+		{`{ reviews(after: "bm9wZQ") { totalCount } }`, "after"},
+		{`{ reviews(first: 101) { totalCount } }`, "first"},
+	}
+	for _, tt := range tests {
+		response := postGraphQL(t, r, tt.query, nil)
+		if len(response.Errors) != 1 {
+			t.Errorf("Expected 1 error for %s, got %+v", tt.query, response.Errors)
+			continue
+		}
+		extensions := response.Errors[0].Extensions
+		if extensions["code"] != "BAD_USER_INPUT" || extensions["field"] != tt.field {
+			t.Errorf("Expected a BAD_USER_INPUT error on %s for %s, got %v", tt.field, tt.query, extensions)
+		}
+		if response.Data != nil {
+			t.Errorf("Expected data to be null for %s, got %v", tt.query, response.Data)
+		}
+	}
+}
+
+// TestGraphQL_ComplexityLimit verifies that large pages of costly fields are rejected before running
+func TestGraphQL_ComplexityLimit(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	response := postGraphQL(t, r, `{
+		newest: reviews(first: 100) { ...page }
+		oldest: reviews(first: 100, sort: OLDEST) { ...page }
+	}
+	fragment page on ReviewConnection { nodes { id title content rating triage { status assignee labels notes { text } } } }`, nil)
+	if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != "QUERY_TOO_COMPLEX" {
+		t.Fatalf("Expected a QUERY_TOO_COMPLEX error, got %+v", response.Errors)
+	}
+	if response.Data != nil {
+		t.Errorf("Expected no data, got %v", response.Data)
+	}
+
+	response = postGraphQL(t, r, `{ reviews(first: 10) { nodes { id title content rating triage { status assignee labels notes { text } } } } }`, nil)
+	if len(response.Errors) > 0 {
+		t.Errorf("Expected a smaller page to be accepted, got %+v", response.Errors)
+	}
+}
+
+// TestGraphQL_NestedFragmentsRejectedQuickly verifies that fragments spreading each other are measured once,
+// so documents whose expansion doubles with each fragment are rejected without walking it
+func TestGraphQL_NestedFragmentsRejectedQuickly(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	var query strings.Builder
+	query.WriteString("{ review(id: \"review-1\") { ...f0 } }\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&query, "fragment f%d on Review { a: triage { status } ...f%d ...f%d }\n", i, i+1, i+1)
+	}
+	query.WriteString("fragment f40 on Review { id }\n")
+
+	start := time.Now()
+	response := postGraphQL(t, r, query.String(), nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the query to be rejected quickly, took %s", elapsed)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != "QUERY_TOO_COMPLEX" {
+		t.Errorf("Expected a QUERY_TOO_COMPLEX error, got %+v", response.Errors)
+	}
+}
+
+// TestGraphQL_ErrorCodes verifies the codes of queries rejected before running
+func TestGraphQL_ErrorCodes(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	tests := []struct {
+		query     string
+		variables map[string]any
+		code      string
+	}{
+		{`{ reviews { totalCount }`, nil, "GRAPHQL_PARSE_FAILED"},
+		{`{ reviews { unknown } }`, nil, "GRAPHQL_VALIDATION_FAILED"},
+		{`{ apps } fragment unused on Query { apps }`, nil, "GRAPHQL_VALIDATION_FAILED"},
+		{`query Page($first: Int) { reviews(first: $first) { totalCount } }`, map[string]any{"first": "ten"}, "BAD_USER_INPUT"},
+	}
+	for _, tt := range tests {
+		response := postGraphQL(t, r, tt.query, tt.variables)
+		if len(response.Errors) == 0 || response.Errors[0].Extensions["code"] != tt.code {
+			t.Errorf("Expected a %s error for %s, got %+v", tt.code, tt.query, response.Errors)
+		}
+		if response.Data != nil {
+			t.Errorf("Expected no data for %s, got %v", tt.query, response.Data)
+		}
+	}
+
+	response := postGraphQL(t, r, `{ ...a } fragment a on Query { reviews { edges { node { ...b } } } }
+	fragment b on Review { flags { reason } triage { ...c } } fragment c on Triage { notes { id } }`, nil)
+	if len(response.Errors) > 0 {
+		t.Errorf("Expected nested fragments within the limits to be accepted, got %+v", response.Errors)
+	}
+}
+
+// TestGraphQL_Introspection verifies that clients can introspect the schema
+func TestGraphQL_Introspection(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	response := postGraphQL(t, r, `{ __type(name: "Review") { name fields { name } } __schema { queryType { name } } }`, nil)
+	if len(response.Errors) > 0 {
+		t.Fatalf("Expected no errors, got %+v", response.Errors)
+	}
+	if !strings.Contains(string(response.Data["__type"]), `{"name":"triage"}`) {
+		t.Errorf("Expected the fields of Review, got %s", response.Data["__type"])
+	}
+	if string(response.Data["__schema"]) != `{"queryType":{"name":"Query"}}` {
+		t.Errorf("Expected the query type, got %s", response.Data["__schema"])
+	}
+}
+
+// TestGraphQL_BadRequests verifies that requests without a query are problems, not GraphQL responses
+func TestGraphQL_BadRequests(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	tests := []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{http.MethodGet, "/graphql", ""},
+		{http.MethodGet, "/graphql?query=%7Bapps%7D&variables=nope", ""},
+		{http.MethodPost, "/graphql", "{"},
+		{http.MethodPost, "/graphql", `{"variables":{}}`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/problem+json") {
+			t.Errorf("Expected a 400 problem for %s %s %s, got %d %s", tt.method, tt.url, tt.body, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+// TestGraphQLSchema verifies that the schema is served in the schema definition language
+func TestGraphQLSchema(t *testing.T) {
+	r := createGraphQLTestRouter()
+
+	w := doRequest(r, "/graphql/schema", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	for _, expected := range []string{
+		"type Query {",
+		"reviews(after: String, appId: String, filter: ReviewFilter, first: Int = 20, hours: Int = 48, sort: ReviewSort = NEWEST): ReviewConnection!",
+		"input ReviewFilter {",
+		"enum TriageStatus {",
+	} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("Expected the schema to contain %q, got %s", expected, w.Body.String())
+		}
+	}
+}
@@ -3,12 +3,25 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
 	"github.com/gin-gonic/gin"
 )
 
-func Health(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
-		"uptime": "running",
-	})
+func Health(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, HealthResponse{
+			Status:        "ok",
+			Uptime:        "running",
+			ConfigVersion: appService.GetConfigVersion(),
+			TrackedAppIDs: appService.TrackedAppIDs(),
+		})
+	}
+}
+
+// RouteNotFound answers requests matching no route
+func RouteNotFound() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		problem.NotFound(c, "Route not found")
+	}
 }
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/insights"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxInsightsRange   = 366 * 24 * time.Hour
+	maxInsightsBuckets = 500
+)
+
+// KeywordInsights returns the top and rising terms of a period compared with the previous one
+func KeywordInsights(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+		rating, ok := parseRatingQuery(c)
+		if !ok {
+			return
+		}
+		lang, ok := parseLangQuery(c)
+		if !ok {
+			return
+		}
+		includeFlagged, ok := parseIncludeFlaggedQuery(c)
+		if !ok {
+			return
+		}
+
+		now := time.Now().UTC()
+		to, ok := parseTimeQuery(c, "to", now)
+		if !ok {
+			return
+		}
+		from, ok := parseTimeQuery(c, "from", to.Add(-7*24*time.Hour))
+		if !ok {
+			return
+		}
+		if !from.Before(to) || to.Sub(from) > maxInsightsRange {
+			problem.Invalid(c, problem.Query("from", problem.FieldOutOfRange, "Invalid date range, from must be before to and cover at most 366 days"))
+			return
+		}
+
+		options := insights.KeywordOptions{From: from, To: to, Bucket: c.DefaultQuery("bucket", insights.BucketDay)}
+		bucketSize := insights.BucketSize(options.Bucket)
+		if bucketSize == 0 {
+			problem.Invalid(c, problem.Query("bucket", problem.FieldInvalid, "Invalid bucket parameter, must be hour, day or week"))
+			return
+		}
+		if to.Sub(from)/bucketSize >= maxInsightsBuckets {
+			problem.Invalid(c, problem.Query("bucket", problem.FieldOutOfRange, "Too many buckets, use a larger bucket or a shorter range"))
+			return
+		}
+
+		if options.MaxN, ok = parseIntQuery(c, "n", 2, 1, insights.MaxNGram); !ok {
+			return
+		}
+		if options.Limit, ok = parseIntQuery(c, "limit", 20, 1, 100); !ok {
+			return
+		}
+		if options.MinCount, ok = parseIntQuery(c, "minCount", 2, 1, math.MaxInt32); !ok {
+			return
+		}
+
+		// reviews are listed back from now, down to the start of the previous period
+		previousFrom := from.Add(-to.Sub(from))
+		hours := int(math.Ceil(now.Sub(previousFrom).Hours()))
+		filter := repositories.ReviewFilter{Rating: rating, Language: lang}
+		if !includeFlagged {
+			unflagged := false
+			filter.Flagged = &unflagged
+		}
+		reviews := appService.QueryReviews(appID, hours, filter, models.SortNewest)
+
+		c.JSON(http.StatusOK, KeywordInsightsResponse{
+			AppID:         appID,
+			KeywordReport: insights.Keywords(reviews, options),
+		})
+	}
+}
+
+// parseTimeQuery reads an optional RFC 3339 timestamp or YYYY-MM-DD date (UTC midnight),
+// writing a 400 response when it is invalid
+func parseTimeQuery(c *gin.Context, name string, defaultValue time.Time) (time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, true
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), true
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, true
+	}
+	problem.Invalid(c, problem.Query(name, problem.FieldInvalid, "Invalid "+name+" parameter, must be an RFC 3339 timestamp or a YYYY-MM-DD date"))
+	return time.Time{}, false
+}
+
+// parseIntQuery reads an optional integer between minValue and maxValue, writing a 400 response when it is invalid
+func parseIntQuery(c *gin.Context, name string, defaultValue, minValue, maxValue int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, true
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		problem.Invalid(c, problem.Query(name, problem.FieldInvalid, "Invalid "+name+" parameter"))
+		return 0, false
+	}
+	if parsed < minValue || parsed > maxValue {
+		problem.Invalid(c, problem.Query(name, problem.FieldOutOfRange, fmt.Sprintf("Invalid %s parameter, must be between %d and %d", name, minValue, maxValue)))
+		return 0, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/insights"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+func createInsightsTestRouter() *gin.Engine {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "1", Title: "Crash", Content: "Crash on login", Rating: 1, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "2", Title: "Crash again", Content: "Another crash", Rating: 1, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "3", Title: "Dark mode", Content: "Love the dark mode", Rating: 5, UpdatedAt: now.Add(-3 * time.Hour)},
+		{ID: "4", Title: "Dark mode", Content: "Dark mode please", Rating: 4, UpdatedAt: now.Add(-4 * time.Hour)},
+		{ID: "5", Title: "Dark mode", Content: "Dark mode is nice", Rating: 5, UpdatedAt: now.Add(-10 * 24 * time.Hour)},
+	}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: reviews}, &config.Config{AppID: "test-app-id"})
+
+	r := gin.New()
+	r.GET("/insights/keywords", KeywordInsights(appService))
+	return r
+}
+
+// TestKeywordInsights_FiltersByRating verifies the terms reported for a rating, with the previous period compared
+func TestKeywordInsights_FiltersByRating(t *testing.T) {
+	r := createInsightsTestRouter()
+
+	w := doRequest(r, "/insights/keywords?rating=1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		AppID string `json:"appId"`
+		insights.KeywordReport
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.AppID != "test-app-id" || response.ReviewCount != 2 || len(response.Buckets) != 7 {
+		t.Errorf("Unexpected report envelope: %+v", response)
+	}
+	if len(response.Top) == 0 || response.Top[0].Term != "crash" || response.Top[0].Count != 2 {
+		t.Errorf("Expected crash as top term, got %+v", response.Top)
+	}
+	for _, term := range response.Top {
+		if term.Term == "dark mode" {
+			t.Error("Expected 5-star terms to be filtered out")
+		}
+	}
+}
+
+// TestKeywordInsights_ComparesDateRanges verifies that the previous period comes from the given range
+func TestKeywordInsights_ComparesDateRanges(t *testing.T) {
+	r := createInsightsTestRouter()
+	to := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+	from := time.Now().UTC().Add(-7 * 24 * time.Hour).Format(time.RFC3339)
+
+	w := doRequest(r, "/insights/keywords?from="+from+"&to="+to+"&bucket=week", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var report insights.KeywordReport
+	json.Unmarshal(w.Body.Bytes(), &report)
+	if report.PreviousReviewCount != 1 {
+		t.Errorf("Expected 1 review in the previous period, got %d", report.PreviousReviewCount)
+	}
+	for _, term := range report.Top {
+		if term.Term == "dark mode" && (term.Count != 2 || term.PreviousCount != 1) {
+			t.Errorf("Expected dark mode in 2 reviews and 1 previous, got %+v", term)
+		}
+	}
+}
+
+// TestKeywordInsights_RejectsInvalidParameters verifies parameter validation
+func TestKeywordInsights_RejectsInvalidParameters(t *testing.T) {
+	r := createInsightsTestRouter()
+
+	for _, query := range []string{
+		"from=yesterday",
+		"from=2024-02-01&to=2024-01-01",
+		"from=2022-01-01&to=2024-01-01",
+		"bucket=month",
+		"bucket=hour&from=2024-01-01&to=2024-03-01",
+		"n=4",
+		"limit=0",
+		"rating=6",
+	} {
+		if w := doRequest(r, "/insights/keywords?"+query, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+		}
+	}
+}
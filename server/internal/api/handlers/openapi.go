@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPISpec serves the OpenAPI document of the API, clients can generate their types from it
+func OpenAPISpec() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", openapi.Spec)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/alerts"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/insights"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// Response bodies of the handlers, described by the ones of the same name in the OpenAPI document
+
+type HealthResponse struct {
+	Status        string   `json:"status"`
+	Uptime        string   `json:"uptime"`
+	ConfigVersion int      `json:"configVersion"`
+	TrackedAppIDs []string `json:"trackedAppIds"`
+}
+
+type ReviewListResponse struct {
+	AppID     string                  `json:"appId"`
+	Count     int                     `json:"count"`
+	Reviews   []models.AppStoreReview `json:"reviews"`
+	LastHours int                     `json:"lastHours"`
+}
+
+type ReviewStatsResponse struct {
+	AppID string                   `json:"appId"`
+	Stats repositories.ReviewStats `json:"stats"`
+}
+
+type FlaggedReviewsResponse struct {
+	AppID        string                  `json:"appId"`
+	Count        int                     `json:"count"`
+	ReasonCounts map[string]int          `json:"reasonCounts"`
+	Reviews      []models.AppStoreReview `json:"reviews"`
+}
+
+type KeywordInsightsResponse struct {
+	AppID string `json:"appId"`
+	insights.KeywordReport
+}
+
+type ViewListResponse struct {
+	Views []models.View `json:"views"`
+}
+
+type TagRuleListResponse struct {
+	Rules []models.TagRule `json:"rules"`
+}
+
+type AlertRuleListResponse struct {
+	Rules []alerts.RuleStatus `json:"rules"`
+}
+
+type WebhookListResponse struct {
+	Webhooks []webhookResponse `json:"webhooks"`
+}
+
+type DeliveryListResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+}
+
+type APIKeyListResponse struct {
+	Keys []models.APIKey `json:"keys"`
+}
+
+// CreatedAPIKeyResponse is the only response showing the key itself
+type CreatedAPIKeyResponse struct {
+	models.APIKey
+	Key string `json:"key"`
+}
@@ -1,57 +1,257 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/language"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sentiment"
 	"github.com/gin-gonic/gin"
 )
 
 var validRatings = []int{1, 2, 3, 4, 5}
 
+var validSorts = []string{models.SortNewest, models.SortOldest, models.SortSentimentAsc, models.SortSentimentDesc}
+
+// reviewsQuery holds the filters accepted by the reviews endpoints
+type reviewsQuery struct {
+	appID  string
+	hours  int
+	filter repositories.ReviewFilter
+	sort   string
+}
+
+// parseReviewsQuery reads the reviews filters from the query string.
+// On invalid input it writes a 400 response and returns false.
+func parseReviewsQuery(c *gin.Context, appService *app.App) (reviewsQuery, bool) {
+	query, err := parseReviewsValues(c.Request.URL.Query(), appService)
+	if err != nil {
+		problem.Invalid(c, err)
+		return query, false
+	}
+	return query, true
+}
+
+// parseReviewsValues reads the reviews filters from query parameters, returning a *problem.FieldError when they are invalid
+func parseReviewsValues(values url.Values, appService *app.App) (reviewsQuery, error) {
+	query := reviewsQuery{
+		hours: 48, // default value
+		sort:  models.SortNewest,
+	}
+
+	var err error
+	if query.filter.Rating, err = parseRating(values.Get("rating")); err != nil {
+		return query, err
+	}
+
+	if sentimentQuery := values.Get("sentiment"); sentimentQuery != "" {
+		if !sentiment.IsLabel(sentimentQuery) {
+			return query, problem.Query("sentiment", problem.FieldInvalid, "Invalid sentiment parameter, must be positive, neutral or negative")
+		}
+		query.filter.Sentiment = sentimentQuery
+	}
+
+	query.filter.Tag = strings.TrimSpace(values.Get("tag"))
+	query.filter.Query = strings.TrimSpace(values.Get("q"))
+	if query.filter.Language, err = parseLang(values.Get("lang")); err != nil {
+		return query, err
+	}
+
+	if statusQuery := values.Get("status"); statusQuery != "" {
+		if !slices.Contains(models.TriageStatuses, statusQuery) {
+			return query, problem.Query("status", problem.FieldInvalid, "Invalid status parameter, must be one of "+strings.Join(models.TriageStatuses, ", "))
+		}
+		query.filter.Status = statusQuery
+	}
+	query.filter.Assignee = strings.TrimSpace(values.Get("assignee"))
+
+	if sortQuery := values.Get("sort"); sortQuery != "" {
+		if !slices.Contains(validSorts, sortQuery) {
+			return query, problem.Query("sort", problem.FieldInvalid, "Invalid sort parameter")
+		}
+		query.sort = sortQuery
+	}
+
+	hoursQuery := values.Get("hours")
+	if hoursQuery != "" {
+		parsedHours, err := strconv.Atoi(hoursQuery)
+		if err != nil {
+			return query, problem.Query("hours", problem.FieldInvalid, "Invalid hours parameter")
+		}
+		if parsedHours < 1 || parsedHours > 96 {
+			return query, problem.Query("hours", problem.FieldOutOfRange, "Hours parameter must be between 1 and 96")
+		}
+		query.hours = parsedHours
+	}
+
+	if query.appID, err = parseAppID(values.Get("appId"), appService); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+// parseRatingQuery reads the optional rating parameter, writing a 400 response when it is invalid
+func parseRatingQuery(c *gin.Context) (*int, bool) {
+	rating, err := parseRating(c.Query("rating"))
+	if err != nil {
+		problem.Invalid(c, err)
+		return nil, false
+	}
+	return rating, true
+}
+
+func parseRating(ratingQuery string) (*int, error) {
+	if ratingQuery == "" {
+		return nil, nil
+	}
+
+	parsedrating, err := strconv.Atoi(ratingQuery)
+	if err != nil || !slices.Contains(validRatings, parsedrating) {
+		return nil, problem.Query("rating", problem.FieldInvalid, "Invalid rating parameter")
+	}
+	return &parsedrating, nil
+}
+
+// parseLangQuery reads the optional lang parameter, writing a 400 response when it is not a detected language
+func parseLangQuery(c *gin.Context) (string, bool) {
+	lang, err := parseLang(c.Query("lang"))
+	if err != nil {
+		problem.Invalid(c, err)
+		return "", false
+	}
+	return lang, true
+}
+
+func parseLang(langQuery string) (string, error) {
+	langQuery = strings.ToLower(langQuery)
+	if langQuery == "" {
+		return "", nil
+	}
+
+	if !language.IsCode(langQuery) {
+		return "", problem.Query("lang", problem.FieldInvalid, "Invalid lang parameter, must be one of "+strings.Join(language.Supported, ", ")+" or "+language.Undetermined)
+	}
+	return langQuery, nil
+}
+
+// parseIncludeFlaggedQuery reads the optional includeFlagged parameter, false by default,
+// writing a 400 response when it is not a boolean
+func parseIncludeFlaggedQuery(c *gin.Context) (bool, bool) {
+	includeFlaggedQuery := c.Query("includeFlagged")
+	if includeFlaggedQuery == "" {
+		return false, true
+	}
+
+	includeFlagged, err := strconv.ParseBool(includeFlaggedQuery)
+	if err != nil {
+		problem.Invalid(c, problem.Query("includeFlagged", problem.FieldInvalid, "Invalid includeFlagged parameter, must be true or false"))
+		return false, false
+	}
+	return includeFlagged, true
+}
+
+// parseAppIDQuery reads the optional appId parameter, defaulting to the primary app.
+// Writes a 400 response when the app is not tracked.
+func parseAppIDQuery(c *gin.Context, appService *app.App) (string, bool) {
+	appID, err := parseAppID(c.Query("appId"), appService)
+	if err != nil {
+		problem.Invalid(c, err)
+		return "", false
+	}
+	return appID, true
+}
+
+func parseAppID(appIDQuery string, appService *app.App) (string, error) {
+	if appIDQuery == "" {
+		return appService.GetAppID(), nil
+	}
+
+	if !appService.IsTracked(appIDQuery) {
+		return "", problem.Query("appId", problem.FieldNotTracked, "App is not tracked")
+	}
+	return appIDQuery, nil
+}
+
 func ListReviews(appService *app.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var rating *int
-		hours := 48 // default value
-
-		ratingQuery := c.Query("rating")
-		if ratingQuery != "" {
-			parsedrating, err := strconv.Atoi(ratingQuery)
-			if err != nil || !slices.Contains(validRatings, parsedrating) {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rating parameter"})
-				return
-			}
-			rating = &parsedrating
-		}
-
-		hoursQuery := c.Query("hours")
-		if hoursQuery != "" {
-			parsedHours, err := strconv.Atoi(hoursQuery)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hours parameter"})
-				return
-			}
-			if parsedHours < 1 || parsedHours > 96 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Hours parameter must be between 1 and 96"})
-				return
-			}
-			hours = parsedHours
-		}
-
-		reviews := appService.ListLatestReviews(hours, rating)
-		c.JSON(http.StatusOK, struct {
-			AppID     string                  `json:"appId"`
-			Count     int                     `json:"count"`
-			Reviews   []models.AppStoreReview `json:"reviews"`
-			LastHours int                     `json:"lastHours"`
-		}{
-			AppID:     appService.GetAppID(),
-			Count:     len(reviews),
-			Reviews:   reviews,
-			LastHours: hours,
-		})
+		query, ok := parseReviewsQuery(c, appService)
+		if !ok {
+			return
+		}
+
+		writeReviews(c, appService, query, time.Time{})
+	}
+}
+
+// writeReviews answers with the reviews matching query, in the format negotiated by the request,
+// or 304 when the client has them already. changed is when query itself last changed, if it is stored.
+func writeReviews(c *gin.Context, appService *app.App, query reviewsQuery, changed time.Time) {
+	format, ok := negotiateReviewsFormat(c)
+	if !ok {
+		problem.Invalid(c, problem.Query("format", problem.FieldInvalid, "Invalid format parameter"))
+		return
+	}
+
+	c.Header("Vary", "Accept")
+	version, modified, _ := appService.ReviewsVersion(query.appID, query.hours)
+	if changed.After(modified) {
+		modified = changed
+	}
+	if notModified(c, version+" "+format+" "+query.cacheKey(), modified) {
+		return
+	}
+	if format != formatJSON {
+		streamReviews(c, appService, query, format)
+		return
+	}
+
+	reviews := appService.QueryReviews(query.appID, query.hours, query.filter, query.sort)
+	c.JSON(http.StatusOK, ReviewListResponse{
+		AppID:     query.appID,
+		Count:     len(reviews),
+		Reviews:   reviews,
+		LastHours: query.hours,
+	})
+}
+
+// ReviewsStats summarizes the stored reviews of an app, including the sentiment trend of the last days.
+// Reviews flagged as spam are left out unless includeFlagged is true.
+func ReviewsStats(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+		includeFlagged, ok := parseIncludeFlaggedQuery(c)
+		if !ok {
+			return
+		}
+
+		// the sentiment trend moves on at midnight UTC
+		version, modified, _ := appService.ReviewsVersion(appID, 0)
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		if today.After(modified) {
+			modified = today
+		}
+		if notModified(c, fmt.Sprint(version, appID, includeFlagged, today.Unix()), modified) {
+			return
+		}
+
+		stats, ok := appService.Stats(appID, includeFlagged)
+		if !ok {
+			problem.Invalid(c, problem.Query("appId", problem.FieldNotTracked, "App is not tracked"))
+			return
+		}
+		c.JSON(http.StatusOK, ReviewStatsResponse{AppID: appID, Stats: stats})
 	}
 }
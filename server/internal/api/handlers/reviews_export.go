@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+
+	// rows written between flushes, so clients receive data while the export is still running
+	exportFlushEvery = 100
+)
+
+var csvHeader = []string{"id", "title", "content", "author", "rating", "updatedAt", "sentiment"}
+
+// negotiateReviewsFormat picks the response format from the format parameter,
+// falling back to the Accept header. Returns false for an unknown format parameter.
+func negotiateReviewsFormat(c *gin.Context) (string, bool) {
+	switch c.Query("format") {
+	case formatJSON:
+		return formatJSON, true
+	case formatCSV:
+		return formatCSV, true
+	case formatNDJSON:
+		return formatNDJSON, true
+	case "":
+	default:
+		return "", false
+	}
+
+	if c.GetHeader("Accept") == "" {
+		return formatJSON, true
+	}
+	switch c.NegotiateFormat(gin.MIMEJSON, mimeCSV, mimeNDJSON) {
+	case mimeCSV:
+		return formatCSV, true
+	case mimeNDJSON:
+		return formatNDJSON, true
+	default:
+		return formatJSON, true
+	}
+}
+
+// IsSearchOrExport reports whether a reviews listing searches the review texts or exports them as CSV or NDJSON,
+// which is costlier than the default JSON listing
+func IsSearchOrExport(c *gin.Context) bool {
+	format, ok := negotiateReviewsFormat(c)
+	return c.Query("q") != "" || (ok && format != formatJSON)
+}
+
+// streamReviews writes the matching reviews as CSV or NDJSON straight from the repository, which is locked only
+// while the next chunk of reviews is read, so a slow client never blocks the poller
+func streamReviews(c *gin.Context, appService *app.App, query reviewsQuery, format string) {
+	// large exports outlive the server WriteTimeout, so its deadline is lifted for this response only
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("export: could not lift write deadline: %v", err)
+	}
+
+	var writer reviewWriter
+	switch format {
+	case formatCSV:
+		c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="reviews-`+query.appID+`.csv"`)
+		writer = newCSVReviewWriter(c.Writer)
+	default:
+		c.Header("Content-Type", mimeNDJSON)
+		writer = newNDJSONReviewWriter(c.Writer)
+	}
+	c.Status(http.StatusOK)
+
+	written := 0
+	err := eachReview(appService, query, func(review models.AppStoreReview) error {
+		if err := writer.Write(review); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushEvery == 0 {
+			return writer.Flush(c.Writer)
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush(c.Writer)
+	}
+	if err != nil {
+		// headers are already sent, the client sees a truncated body
+		log.Printf("error streaming reviews as %s: %v", format, err)
+	}
+}
+
+// eachReview calls fn for every review matching query in its sort order.
+// Only the default newest first order streams straight from the repository.
+func eachReview(appService *app.App, query reviewsQuery, fn func(review models.AppStoreReview) error) error {
+	if query.sort == models.SortNewest {
+		return appService.StreamLatestReviews(query.appID, query.hours, query.filter, fn)
+	}
+
+	for _, review := range appService.QueryReviews(query.appID, query.hours, query.filter, query.sort) {
+		if err := fn(review); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviewWriter encodes reviews one at a time
+type reviewWriter interface {
+	Write(review models.AppStoreReview) error
+	Flush(flusher http.Flusher) error
+}
+
+// csvReviewWriter writes reviews as RFC 4180 CSV, quoting fields with commas, quotes or line breaks
+type csvReviewWriter struct {
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+func newCSVReviewWriter(w io.Writer) *csvReviewWriter {
+	return &csvReviewWriter{csv: csv.NewWriter(w)}
+}
+
+func (w *csvReviewWriter) Write(review models.AppStoreReview) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.csv.Write([]string{
+		review.ID,
+		review.Title,
+		review.Content,
+		review.Author,
+		strconv.Itoa(review.Rating),
+		review.UpdatedAt.Format(time.RFC3339),
+		strconv.FormatFloat(review.Sentiment, 'f', -1, 64),
+	})
+}
+
+func (w *csvReviewWriter) Flush(flusher http.Flusher) error {
+	// the header is written even when there are no reviews
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+func (w *csvReviewWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.csv.Write(csvHeader)
+}
+
+// ndjsonReviewWriter writes one JSON encoded review per line, line breaks in text fields are escaped by the encoder
+type ndjsonReviewWriter struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONReviewWriter(w io.Writer) *ndjsonReviewWriter {
+	return &ndjsonReviewWriter{encoder: json.NewEncoder(w)}
+}
+
+func (w *ndjsonReviewWriter) Write(review models.AppStoreReview) error {
+	return w.encoder.Encode(review)
+}
+
+func (w *ndjsonReviewWriter) Flush(flusher http.Flusher) error {
+	flusher.Flush()
+	return nil
+}
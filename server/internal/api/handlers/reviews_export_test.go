@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	// Suppress logs during testing
+	log.SetOutput(io.Discard)
+	gin.SetMode(gin.TestMode)
+
+	// Run tests
+	code := m.Run()
+
+	// Restore log output
+	log.SetOutput(os.Stderr)
+
+	// Exit with the same code as the tests
+	os.Exit(code)
+}
+
+// createTestRouter creates a router serving the given reviews of app "test-app-id"
+func createTestRouter(reviews models.AppStoreReviews) *gin.Engine {
+	cfg := &config.Config{AppID: "test-app-id"}
+	repo := &repositories.AppReviewsRepository{Reviews: reviews}
+	appService := app.New(repo, cfg)
+
+	r := gin.New()
+	r.GET("/reviews", ListReviews(appService))
+	return r
+}
+
+func createExportTestReviews() models.AppStoreReviews {
+	now := time.Now().UTC()
+	return models.AppStoreReviews{
+		{
+			ID:        "review-1",
+			Title:     `Crashes, "a lot"`,
+			Content:   "First line\nSecond line, with comma",
+			Author:    "User1",
+			Rating:    1,
+			UpdatedAt: now.Add(-1 * time.Hour),
+		},
+		{
+			ID:        "review-2",
+			Title:     "Good App",
+			Content:   "Pretty good app overall",
+			Author:    "User2",
+			Rating:    4,
+			UpdatedAt: now.Add(-2 * time.Hour),
+		},
+	}
+}
+
+func doRequest(r *gin.Engine, url string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestListReviews_CSVFormat verifies that CSV output escapes multiline and quoted fields and round-trips through a CSV reader
+func TestListReviews_CSVFormat(t *testing.T) {
+	reviews := createExportTestReviews()
+	r := createTestRouter(reviews)
+
+	w := doRequest(r, "/reviews?format=csv", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Errorf("Expected text/csv content type, got %s", w.Header().Get("Content-Type"))
+	}
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header + 2 rows, got %d records", len(records))
+	}
+	if strings.Join(records[0], ",") != "id,title,content,author,rating,updatedAt,sentiment" {
+		t.Errorf("Unexpected header: %v", records[0])
+	}
+	if records[1][1] != reviews[0].Title {
+		t.Errorf("Expected title %q, got %q", reviews[0].Title, records[1][1])
+	}
+	if records[1][2] != reviews[0].Content {
+		t.Errorf("Expected multiline content %q, got %q", reviews[0].Content, records[1][2])
+	}
+}
+
+// TestListReviews_NDJSONFormat verifies that each review is written as a single JSON line
+func TestListReviews_NDJSONFormat(t *testing.T) {
+	reviews := createExportTestReviews()
+	r := createTestRouter(reviews)
+
+	w := doRequest(r, "/reviews?format=ndjson", nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("Expected application/x-ndjson content type, got %s", w.Header().Get("Content-Type"))
+	}
+
+	var lines []models.AppStoreReview
+	scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+	for scanner.Scan() {
+		var review models.AppStoreReview
+		if err := json.Unmarshal(scanner.Bytes(), &review); err != nil {
+			t.Fatalf("Failed to parse line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, review)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if lines[0].Content != reviews[0].Content {
+		t.Errorf("Expected content %q, got %q", reviews[0].Content, lines[0].Content)
+	}
+}
+
+// TestListReviews_ExportAppliesFilters verifies that streamed formats honor the rating filter
+func TestListReviews_ExportAppliesFilters(t *testing.T) {
+	r := createTestRouter(createExportTestReviews())
+
+	w := doRequest(r, "/reviews?format=ndjson&rating=4", nil)
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"id":"review-2"`) {
+		t.Errorf("Expected only review-2, got %v", lines)
+	}
+}
+
+// TestListReviews_AcceptHeaderNegotiation verifies that the format is negotiated from the Accept header
+func TestListReviews_AcceptHeaderNegotiation(t *testing.T) {
+	r := createTestRouter(createExportTestReviews())
+
+	tests := map[string]string{
+		"text/csv":             "text/csv; charset=utf-8",
+		"application/x-ndjson": "application/x-ndjson",
+		"application/json":     "application/json; charset=utf-8",
+		"*/*":                  "application/json; charset=utf-8",
+	}
+	for accept, expectedContentType := range tests {
+		w := doRequest(r, "/reviews", map[string]string{"Accept": accept})
+		if w.Header().Get("Content-Type") != expectedContentType {
+			t.Errorf("Accept %s: expected content type %s, got %s", accept, expectedContentType, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+// TestListReviews_InvalidFormat verifies that an unknown format is rejected
+func TestListReviews_InvalidFormat(t *testing.T) {
+	r := createTestRouter(createExportTestReviews())
+
+	w := doRequest(r, "/reviews?format=xml", nil)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+// TestListReviews_CSVWithoutReviews verifies that the header is written even when no review matches
+func TestListReviews_CSVWithoutReviews(t *testing.T) {
+	r := createTestRouter(models.AppStoreReviews{})
+
+	w := doRequest(r, "/reviews?format=csv", nil)
+
+	if strings.TrimSpace(w.Body.String()) != "id,title,content,author,rating,updatedAt,sentiment" {
+		t.Errorf("Expected only the CSV header, got %q", w.Body.String())
+	}
+}
+
+// blockingWriter is a response writer whose writes wait until release is closed, like a stalled client
+type blockingWriter struct {
+	*httptest.ResponseRecorder
+	writing chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (w *blockingWriter) Write(data []byte) (int, error) {
+	w.once.Do(func() { close(w.writing) })
+	<-w.release
+	return w.ResponseRecorder.Write(data)
+}
+
+// TestListReviews_ExportDoesNotBlockWrites verifies that a client stalled during an export doesn't keep the poller
+// from adding reviews
+func TestListReviews_ExportDoesNotBlockWrites(t *testing.T) {
+	cfg := &config.Config{AppID: "test-app-id"}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: createExportTestReviews()}, cfg)
+	r := gin.New()
+	r.GET("/reviews", ListReviews(appService))
+
+	w := &blockingWriter{ResponseRecorder: httptest.NewRecorder(), writing: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reviews?format=ndjson", nil))
+		close(done)
+	}()
+	<-w.writing
+
+	added := make(chan struct{})
+	go func() {
+		appService.AddReviews("test-app-id", []models.AppStoreReview{{ID: "review-3", Rating: 5, UpdatedAt: time.Now().UTC()}})
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected reviews to be added while the export is stalled")
+	}
+
+	close(w.release)
+	<-done
+	if lines := strings.Count(w.Body.String(), "\n"); lines != 2 {
+		t.Errorf("Expected the 2 reviews stored when the export started, got %d lines", lines)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/events"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeatInterval is how often a comment is sent to keep idle connections and proxies alive
+var streamHeartbeatInterval = 15 * time.Second
+
+// streamReviewEvent is the data of each review event sent to clients
+type streamReviewEvent struct {
+	AppID  string                `json:"appId"`
+	Review models.AppStoreReview `json:"review"`
+}
+
+// StreamReviews pushes every newly stored review as a Server-Sent Event.
+// Clients resuming with the Last-Event-ID header first receive the events they missed that are still in history,
+// or a reset event when some are no longer there.
+func StreamReviews(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rating, ok := parseRatingQuery(c)
+		if !ok {
+			return
+		}
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+
+		var lastEventID uint64
+		if header := c.GetHeader("Last-Event-ID"); header != "" {
+			parsedID, err := strconv.ParseUint(header, 10, 64)
+			if err != nil {
+				problem.Invalid(c, &problem.FieldError{Field: "Last-Event-ID", In: problem.InHeader, Code: problem.FieldInvalid, Message: "Invalid Last-Event-ID header"})
+				return
+			}
+			lastEventID = parsedID
+		}
+
+		hub := appService.Events()
+		sub, missed, resetID := hub.Subscribe(events.Filter{AppID: appID, Rating: rating}, lastEventID)
+		defer hub.Unsubscribe(sub)
+
+		// the stream outlives the server WriteTimeout, so its deadline is lifted for this response only
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			log.Printf("SSE: could not lift write deadline: %v", err)
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		if resetID != 0 {
+			// the id moves the client past the events it can't get anymore
+			if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: reset\ndata: {}\n\n", resetID); err != nil {
+				return
+			}
+		}
+		for _, event := range missed {
+			if err := writeReviewEvent(c.Writer, event); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-heartbeat.C:
+				if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			case event, ok := <-sub.C:
+				if !ok {
+					// dropped by the hub for being too slow, the client reconnects with Last-Event-ID
+					return
+				}
+				if err := writeReviewEvent(c.Writer, event); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// writeReviewEvent writes a single SSE message, JSON data never contains raw line breaks
+func writeReviewEvent(w io.Writer, event events.ReviewEvent) error {
+	data, err := json.Marshal(streamReviewEvent{AppID: event.AppID, Review: event.Review})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: review\ndata: %s\n\n", event.ID, data)
+	return err
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// startStreamServer starts a real HTTP server, SSE needs streaming responses that a recorder can't provide
+func startStreamServer(t *testing.T) (*httptest.Server, *app.App) {
+	cfg := &config.Config{AppID: "test-app-id"}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: models.AppStoreReviews{}}, cfg)
+
+	r := gin.New()
+	r.GET("/reviews/stream", StreamReviews(appService))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return server, appService
+}
+
+// openStream connects to the stream and returns a reader of its lines
+func openStream(t *testing.T, url string, lastEventID string) *bufio.Scanner {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream content type, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	return bufio.NewScanner(resp.Body)
+}
+
+// readEvent reads lines until the end of the next SSE message, returning its non empty lines
+func readEvent(t *testing.T, scanner *bufio.Scanner) []string {
+	t.Helper()
+	lines := make(chan []string, 1)
+	go func() {
+		var message []string
+		for scanner.Scan() {
+			if scanner.Text() == "" {
+				if len(message) > 0 {
+					break
+				}
+				continue
+			}
+			message = append(message, scanner.Text())
+		}
+		lines <- message
+	}()
+
+	select {
+	case message := <-lines:
+		return message
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for an event")
+	}
+	return nil
+}
+
+func streamTestReview(id string, rating int) models.AppStoreReview {
+	return models.AppStoreReview{ID: id, Title: "Title", Content: "line 1\nline 2", Rating: rating, UpdatedAt: time.Now().UTC()}
+}
+
+// TestStreamReviews_PushesNewReviews verifies that reviews added through the app service are pushed with the rating filter applied
+func TestStreamReviews_PushesNewReviews(t *testing.T) {
+	server, appService := startStreamServer(t)
+	scanner := openStream(t, server.URL+"/reviews/stream?rating=1", "")
+
+	// wait for the handler to subscribe before publishing
+	time.Sleep(50 * time.Millisecond)
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		streamTestReview("review-5-stars", 5),
+		streamTestReview("review-1-star", 1),
+	})
+
+	message := readEvent(t, scanner)
+	if len(message) != 3 {
+		t.Fatalf("Expected id, event and data lines, got %v", message)
+	}
+	if !strings.HasPrefix(message[0], "id: ") {
+		t.Errorf("Expected event id line, got %s", message[0])
+	}
+	if message[1] != "event: review" {
+		t.Errorf("Expected review event, got %s", message[1])
+	}
+	if !strings.Contains(message[2], `"id":"review-1-star"`) || !strings.Contains(message[2], `line 1\nline 2`) {
+		t.Errorf("Expected escaped 1-star review data, got %s", message[2])
+	}
+}
+
+// TestStreamReviews_ResumesFromLastEventID verifies that missed events are replayed on reconnect
+func TestStreamReviews_ResumesFromLastEventID(t *testing.T) {
+	server, appService := startStreamServer(t)
+	start := appService.Events().LastID()
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		streamTestReview("review-1", 5),
+		streamTestReview("review-2", 4),
+		streamTestReview("review-3", 3),
+	})
+
+	scanner := openStream(t, server.URL+"/reviews/stream", strconv.FormatUint(start+1, 10))
+
+	first := readEvent(t, scanner)
+	second := readEvent(t, scanner)
+	if first[0] != fmt.Sprintf("id: %d", start+2) || second[0] != fmt.Sprintf("id: %d", start+3) {
+		t.Errorf("Expected events 2 and 3 to be replayed, got %v and %v", first, second)
+	}
+}
+
+// TestStreamReviews_ResetsUnknownLastEventID verifies that clients resuming from an event of a previous run
+// get a reset event moving them to the last event
+func TestStreamReviews_ResetsUnknownLastEventID(t *testing.T) {
+	server, appService := startStreamServer(t)
+	appService.AddReviews("test-app-id", []models.AppStoreReview{streamTestReview("review-1", 5)})
+
+	scanner := openStream(t, server.URL+"/reviews/stream", "1")
+
+	message := readEvent(t, scanner)
+	expectedID := fmt.Sprintf("id: %d", appService.Events().LastID())
+	if len(message) != 3 || message[0] != expectedID || message[1] != "event: reset" {
+		t.Errorf("Expected a reset event with %s, got %v", expectedID, message)
+	}
+}
+
+// TestStreamReviews_SendsHeartbeats verifies that idle streams receive heartbeat comments
+func TestStreamReviews_SendsHeartbeats(t *testing.T) {
+	previousInterval := streamHeartbeatInterval
+	streamHeartbeatInterval = 20 * time.Millisecond
+	t.Cleanup(func() { streamHeartbeatInterval = previousInterval })
+
+	server, _ := startStreamServer(t)
+	scanner := openStream(t, server.URL+"/reviews/stream", "")
+
+	message := readEvent(t, scanner)
+	if len(message) != 1 || message[0] != ": heartbeat" {
+		t.Errorf("Expected heartbeat comment, got %v", message)
+	}
+}
+
+// TestStreamReviews_InvalidLastEventID verifies that a malformed Last-Event-ID is rejected
+func TestStreamReviews_InvalidLastEventID(t *testing.T) {
+	server, _ := startStreamServer(t)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/reviews/stream", nil)
+	req.Header.Set("Last-Event-ID", "not-a-number")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+func createSentimentTestReviews() models.AppStoreReviews {
+	now := time.Now().UTC()
+	return models.AppStoreReviews{
+		{ID: "review-1", Title: "Love it", Content: "Amazing and fast", Rating: 5, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Buggy", Content: "Crashes every time, terrible", Rating: 4, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-3", Title: "Ok", Content: "It opens", Rating: 3, UpdatedAt: now.Add(-3 * time.Hour)},
+		{ID: "review-4", Title: "Not good", Content: "Slow", Rating: 2, UpdatedAt: now.Add(-4 * time.Hour)},
+	}
+}
+
+func decodeReviews(t *testing.T, body []byte) []models.AppStoreReview {
+	var response struct {
+		Reviews []models.AppStoreReview `json:"reviews"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response.Reviews
+}
+
+// TestListReviews_SentimentFilter verifies filtering on the sentiment scored when reviews are loaded
+func TestListReviews_SentimentFilter(t *testing.T) {
+	r := createTestRouter(createSentimentTestReviews())
+
+	w := doRequest(r, "/reviews?sentiment=negative", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	reviews := decodeReviews(t, w.Body.Bytes())
+	if len(reviews) != 2 || reviews[0].ID != "review-2" || reviews[1].ID != "review-4" {
+		t.Errorf("Expected the 2 negative reviews, got %+v", reviews)
+	}
+	if reviews[0].SentimentLabel != "negative" || reviews[0].Sentiment >= 0 {
+		t.Errorf("Expected sentiment in the response, got %s (%v)", reviews[0].SentimentLabel, reviews[0].Sentiment)
+	}
+
+	if w := doRequest(r, "/reviews?sentiment=angry", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid sentiment, got %d", w.Code)
+	}
+}
+
+// TestListReviews_SortBySentiment verifies the sentiment sort orders
+func TestListReviews_SortBySentiment(t *testing.T) {
+	r := createTestRouter(createSentimentTestReviews())
+
+	ascending := decodeReviews(t, doRequest(r, "/reviews?sort=sentiment_asc", nil).Body.Bytes())
+	if ascending[0].ID != "review-2" || ascending[len(ascending)-1].ID != "review-1" {
+		t.Errorf("Expected most negative first, got %s ... %s", ascending[0].ID, ascending[len(ascending)-1].ID)
+	}
+	for i := 1; i < len(ascending); i++ {
+		if ascending[i].Sentiment < ascending[i-1].Sentiment {
+			t.Errorf("Expected ascending sentiment, got %v after %v", ascending[i].Sentiment, ascending[i-1].Sentiment)
+		}
+	}
+
+	descending := decodeReviews(t, doRequest(r, "/reviews?sort=sentiment_desc&format=json", nil).Body.Bytes())
+	if descending[0].ID != "review-1" {
+		t.Errorf("Expected most positive first, got %s", descending[0].ID)
+	}
+
+	if w := doRequest(r, "/reviews?sort=rating", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid sort, got %d", w.Code)
+	}
+}
+
+// TestReviewsStats_IncludesSentimentTrend verifies the sentiment summary of the stats endpoint
+func TestReviewsStats_IncludesSentimentTrend(t *testing.T) {
+	cfg := &config.Config{AppID: "test-app-id"}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: createSentimentTestReviews()}, cfg)
+	r := gin.New()
+	r.GET("/reviews/stats", ReviewsStats(appService))
+
+	w := doRequest(r, "/reviews/stats", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var response struct {
+		AppID string                   `json:"appId"`
+		Stats repositories.ReviewStats `json:"stats"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Stats.Count != 4 || response.Stats.SentimentCounts["negative"] != 2 || response.Stats.SentimentCounts["positive"] != 1 {
+		t.Errorf("Unexpected stats: %+v", response.Stats)
+	}
+	trend := response.Stats.SentimentTrend
+	if len(trend) != 14 {
+		t.Fatalf("Expected 14 trend points, got %d", len(trend))
+	}
+	total := 0
+	for _, point := range trend {
+		total += point.Count
+	}
+	if total != 4 {
+		t.Errorf("Expected every recent review in the trend, got %d", total)
+	}
+
+	if w := doRequest(r, "/reviews/stats?appId=unknown", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an untracked app, got %d", w.Code)
+	}
+}
+
+// TestListReviews_LanguageAndSearchFilters verifies the lang filter on the detected language and the accent insensitive q search
+func TestListReviews_LanguageAndSearchFilters(t *testing.T) {
+	now := time.Now().UTC()
+	r := createTestRouter(models.AppStoreReviews{
+		{ID: "review-1", Title: "No funciona", Content: "La aplicación se cierra cada vez que la abro", Rating: 1, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Great app", Content: "Works fine since the update", Rating: 5, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-3", Title: "Não funciona", Content: "O aplicativo fecha toda vez que eu abro", Rating: 1, UpdatedAt: now.Add(-3 * time.Hour)},
+	})
+
+	reviews := decodeReviews(t, doRequest(r, "/reviews?lang=es", nil).Body.Bytes())
+	if ids := reviewIDs(reviews); !slices.Equal(ids, []string{"review-1"}) {
+		t.Errorf("Expected the Spanish review, got %v", ids)
+	}
+	if reviews[0].Language != "es" {
+		t.Errorf("Expected language in the response, got %q", reviews[0].Language)
+	}
+
+	if ids := reviewIDs(decodeReviews(t, doRequest(r, "/reviews?q=aplicacion+cierra", nil).Body.Bytes())); !slices.Equal(ids, []string{"review-1"}) {
+		t.Errorf("Expected every search word to match, accents ignored, got %v", ids)
+	}
+	if ids := reviewIDs(decodeReviews(t, doRequest(r, "/reviews?q=FUNCIONA", nil).Body.Bytes())); !slices.Equal(ids, []string{"review-1", "review-3"}) {
+		t.Errorf("Expected a case insensitive whole word match, got %v", ids)
+	}
+	if ids := reviewIDs(decodeReviews(t, doRequest(r, "/reviews?q=funcion", nil).Body.Bytes())); len(ids) != 0 {
+		t.Errorf("Expected no partial word matches, got %v", ids)
+	}
+
+	if w := doRequest(r, "/reviews?lang=xx", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown language, got %d", w.Code)
+	}
+}
@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/events"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sentiment"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// wsPingInterval is how often connections are pinged, clients that don't answer within wsPongWait are disconnected
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	// wsWriteWait bounds every write, a client that doesn't read for that long is disconnected
+	wsWriteWait = 10 * time.Second
+	// wsStatsInterval is how often the stats of the subscribed app are checked and their changes sent
+	wsStatsInterval = 30 * time.Second
+)
+
+// wsMaxMessageSize is the size limit of client messages, subscriptions are small
+const wsMaxMessageSize = 4096
+
+// Message types of the WebSocket protocol
+const (
+	wsTypeSubscribe  = "subscribe"  // client: replaces the filters of the connection
+	wsTypeSubscribed = "subscribed" // server: the filters now applied and the stats the deltas start from
+	wsTypeReview     = "review"     // server: a newly stored review matching the filters
+	wsTypeReset      = "reset"      // server: the events after lastEventId are gone, reload and resubscribe from id
+	wsTypeStats      = "stats"      // server: how the stats of the app changed since the last stats sent
+	wsTypeError      = "error"      // server: an invalid client message, the previous filters stay
+)
+
+// wsFilters are the filters of a subscription, named like the query parameters of GET /reviews
+type wsFilters struct {
+	AppID     string `json:"appId,omitempty"`
+	Rating    *int   `json:"rating,omitempty"`
+	Q         string `json:"q,omitempty"` // keywords the title or content must all contain
+	Sentiment string `json:"sentiment,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Lang      string `json:"lang,omitempty"`
+}
+
+// wsClientMessage is a message sent by clients
+type wsClientMessage struct {
+	Type        string    `json:"type"`
+	Filters     wsFilters `json:"filters"`
+	LastEventID uint64    `json:"lastEventId"` // replays the events after it still in history, like Last-Event-ID
+}
+
+// wsServerMessage is a message sent to clients, with the fields of its type
+type wsServerMessage struct {
+	Type    string                    `json:"type"`
+	ID      uint64                    `json:"id,omitempty"` // event ID of reviews and resets, to resubscribe from
+	AppID   string                    `json:"appId,omitempty"`
+	Filters *wsFilters                `json:"filters,omitempty"`
+	Review  *models.AppStoreReview    `json:"review,omitempty"`
+	Stats   *repositories.ReviewStats `json:"stats,omitempty"`
+	Delta   *wsStatsDelta             `json:"delta,omitempty"`
+	Error   *problem.FieldError       `json:"error,omitempty"`
+}
+
+// wsStatsDelta is the change of the stats of an app, counts only list the keys that changed
+type wsStatsDelta struct {
+	Count            int            `json:"count"`
+	FlaggedCount     int            `json:"flaggedCount"`
+	AverageRating    float64        `json:"averageRating"`
+	AverageSentiment float64        `json:"averageSentiment"`
+	RatingCounts     map[int]int    `json:"ratingCounts,omitempty"`
+	SentimentCounts  map[string]int `json:"sentimentCounts,omitempty"`
+	TagCounts        map[string]int `json:"tagCounts,omitempty"`
+}
+
+// wsSubscription is a validated set of filters
+type wsSubscription struct {
+	filters wsFilters
+	appID   string
+	filter  repositories.ReviewFilter
+}
+
+// ReviewsWebSocket pushes newly stored reviews matching the filters of the client over a WebSocket,
+// with the changes of the app stats every wsStatsInterval. Clients set the initial filters with query
+// parameters and replace them with subscribe messages. Clients too slow to keep up are disconnected
+// with close code 1013 and resubscribe from the last event they received.
+func ReviewsWebSocket(appService *app.App) gin.HandlerFunc {
+	upgrader := websocket.Upgrader{
+		// native clients send no Origin, browsers must come from an allowed one
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || appService.AllowsOrigin(origin)
+		},
+	}
+
+	return func(c *gin.Context) {
+		rating, ok := parseRatingQuery(c)
+		if !ok {
+			return
+		}
+		filters := wsFilters{AppID: c.Query("appId"), Rating: rating, Q: c.Query("q"), Sentiment: c.Query("sentiment"), Tag: c.Query("tag"), Lang: c.Query("lang")}
+		sub, err := filters.subscription(appService)
+		if err != nil {
+			problem.Invalid(c, err)
+			return
+		}
+
+		var lastEventID uint64
+		if lastEventIDQuery := c.Query("lastEventId"); lastEventIDQuery != "" {
+			lastEventID, err = strconv.ParseUint(lastEventIDQuery, 10, 64)
+			if err != nil {
+				problem.Invalid(c, problem.Query("lastEventId", problem.FieldInvalid, "Invalid lastEventId parameter"))
+				return
+			}
+		}
+
+		// Upgrade answers the requests that are not WebSocket handshakes itself
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		session := &wsSession{
+			conn:          conn,
+			appService:    appService,
+			pingInterval:  wsPingInterval,
+			pongWait:      wsPongWait,
+			writeWait:     wsWriteWait,
+			statsInterval: wsStatsInterval,
+		}
+		session.run(sub, lastEventID)
+	}
+}
+
+// subscription validates the filters, returning a *problem.FieldError naming the query parameter when they are invalid
+func (f wsFilters) subscription(appService *app.App) (wsSubscription, error) {
+	f.Q = strings.TrimSpace(f.Q)
+	f.Tag = strings.TrimSpace(f.Tag)
+	sub := wsSubscription{filters: f, filter: repositories.ReviewFilter{Rating: f.Rating, Query: f.Q, Tag: f.Tag}}
+
+	if f.Rating != nil && !slices.Contains(validRatings, *f.Rating) {
+		return sub, problem.Query("rating", problem.FieldInvalid, "Invalid rating parameter")
+	}
+	if f.Sentiment != "" && !sentiment.IsLabel(f.Sentiment) {
+		return sub, problem.Query("sentiment", problem.FieldInvalid, "Invalid sentiment parameter, must be positive, neutral or negative")
+	}
+	sub.filter.Sentiment = f.Sentiment
+
+	var err error
+	if sub.filter.Language, err = parseLang(f.Lang); err != nil {
+		return sub, err
+	}
+	if sub.appID, err = parseAppID(f.AppID, appService); err != nil {
+		return sub, err
+	}
+	return sub, nil
+}
+
+// wsSession is a WebSocket connection. Only run writes to it, the reader goroutine hands it the client messages.
+type wsSession struct {
+	conn       *websocket.Conn
+	appService *app.App
+
+	pingInterval  time.Duration
+	pongWait      time.Duration
+	writeWait     time.Duration
+	statsInterval time.Duration
+
+	stats repositories.ReviewStats // last stats sent, the next delta starts from them
+}
+
+// run serves the connection until the client leaves, stops answering pings or falls too far behind
+func (s *wsSession) run(sub wsSubscription, lastEventID uint64) {
+	stop := make(chan struct{})
+	defer close(stop)
+	messages := make(chan wsClientMessage)
+	readDone := make(chan struct{})
+	go s.read(messages, readDone, stop)
+
+	hub := s.appService.Events()
+	hubSub, ok := s.subscribe(sub, lastEventID)
+	// hubSub changes with every subscribe message
+	defer func() { hub.Unsubscribe(hubSub) }()
+	if !ok {
+		return
+	}
+
+	ping := time.NewTicker(s.pingInterval)
+	defer ping.Stop()
+	stats := time.NewTicker(s.statsInterval)
+	defer stats.Stop()
+
+	for {
+		select {
+		case <-readDone:
+			return
+		case message := <-messages:
+			if message.Type != wsTypeSubscribe {
+				if !s.writeError(problem.Body("type", problem.FieldInvalid, "Invalid message type, must be "+wsTypeSubscribe)) {
+					return
+				}
+				continue
+			}
+			newSub, err := message.Filters.subscription(s.appService)
+			if err != nil {
+				var fieldError *problem.FieldError
+				errors.As(err, &fieldError)
+				if !s.writeError(problem.Body("filters."+fieldError.Field, fieldError.Code, fieldError.Message)) {
+					return
+				}
+				continue
+			}
+			hub.Unsubscribe(hubSub)
+			sub = newSub
+			if hubSub, ok = s.subscribe(sub, message.LastEventID); !ok {
+				return
+			}
+		case event, ok := <-hubSub.C:
+			if !ok {
+				// dropped by the hub for being too slow
+				s.close(websocket.CloseTryAgainLater, "Too slow, resubscribe with the lastEventId of the last review received")
+				return
+			}
+			if !s.writeEvent(sub, event) {
+				return
+			}
+		case <-stats.C:
+			if !s.writeStatsDelta(sub.appID) {
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// read hands the client messages to run until the connection fails or run stops.
+// Messages that are not JSON are handed as an empty type, which run answers with an error.
+func (s *wsSession) read(messages chan<- wsClientMessage, done, stop chan struct{}) {
+	defer close(done)
+
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var message wsClientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			message = wsClientMessage{}
+		}
+
+		select {
+		case messages <- message:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// subscribe subscribes to the events of sub and sends the subscribed message, then the events after lastEventID,
+// or a reset when some are no longer in history. Returns false when the connection failed.
+func (s *wsSession) subscribe(sub wsSubscription, lastEventID uint64) (*events.Subscription, bool) {
+	hubSub, missed, resetID := s.appService.Events().Subscribe(events.Filter{AppID: sub.appID, Rating: sub.filter.Rating}, lastEventID)
+
+	s.stats, _ = s.appService.Stats(sub.appID, false)
+	if !s.write(wsServerMessage{Type: wsTypeSubscribed, AppID: sub.appID, Filters: &sub.filters, Stats: &s.stats}) {
+		return hubSub, false
+	}
+	if resetID != 0 && !s.write(wsServerMessage{Type: wsTypeReset, ID: resetID}) {
+		return hubSub, false
+	}
+	for _, event := range missed {
+		if !s.writeEvent(sub, event) {
+			return hubSub, false
+		}
+	}
+	return hubSub, true
+}
+
+// writeEvent sends event if it passes the filters the hub doesn't apply
+func (s *wsSession) writeEvent(sub wsSubscription, event events.ReviewEvent) bool {
+	if !sub.filter.Matches(event.Review) {
+		return true
+	}
+	return s.write(wsServerMessage{Type: wsTypeReview, ID: event.ID, AppID: event.AppID, Review: &event.Review})
+}
+
+// writeStatsDelta sends how the stats of appID changed since the last ones sent, nothing when they didn't
+func (s *wsSession) writeStatsDelta(appID string) bool {
+	stats, ok := s.appService.Stats(appID, false)
+	if !ok {
+		return true
+	}
+	delta, changed := statsDelta(s.stats, stats)
+	if !changed {
+		return true
+	}
+	s.stats = stats
+	return s.write(wsServerMessage{Type: wsTypeStats, AppID: appID, Delta: &delta})
+}
+
+func (s *wsSession) writeError(fieldError *problem.FieldError) bool {
+	return s.write(wsServerMessage{Type: wsTypeError, Error: fieldError})
+}
+
+// write sends message as JSON, returning false when the connection failed or the client didn't read in time
+func (s *wsSession) write(message wsServerMessage) bool {
+	s.conn.SetWriteDeadline(time.Now().Add(s.writeWait))
+	return s.conn.WriteJSON(message) == nil
+}
+
+func (s *wsSession) close(code int, text string) {
+	s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(s.writeWait))
+}
+
+// statsDelta returns the change from previous to current, false when nothing changed
+func statsDelta(previous, current repositories.ReviewStats) (wsStatsDelta, bool) {
+	delta := wsStatsDelta{
+		Count:            current.Count - previous.Count,
+		FlaggedCount:     current.FlaggedCount - previous.FlaggedCount,
+		AverageRating:    roundDelta(current.AverageRating - previous.AverageRating),
+		AverageSentiment: roundDelta(current.AverageSentiment - previous.AverageSentiment),
+		RatingCounts:     countsDelta(previous.RatingCounts, current.RatingCounts),
+		SentimentCounts:  countsDelta(previous.SentimentCounts, current.SentimentCounts),
+		TagCounts:        countsDelta(previous.TagCounts, current.TagCounts),
+	}
+	changed := delta.Count != 0 || delta.FlaggedCount != 0 || delta.AverageRating != 0 || delta.AverageSentiment != 0 ||
+		len(delta.RatingCounts) > 0 || len(delta.SentimentCounts) > 0 || len(delta.TagCounts) > 0
+	return delta, changed
+}
+
+// countsDelta returns the change of the counts that changed, nil when none did
+func countsDelta[K comparable](previous, current map[K]int) map[K]int {
+	var delta map[K]int
+	add := func(key K, change int) {
+		if change == 0 {
+			return
+		}
+		if delta == nil {
+			delta = map[K]int{}
+		}
+		delta[key] = change
+	}
+	for key, count := range current {
+		add(key, count-previous[key])
+	}
+	for key, count := range previous {
+		if _, ok := current[key]; !ok {
+			add(key, -count)
+		}
+	}
+	return delta
+}
+
+// roundDelta rounds a change of an average to 4 decimals, so float noise doesn't count as a change
+func roundDelta(change float64) float64 {
+	return math.Round(change*10000) / 10000
+}
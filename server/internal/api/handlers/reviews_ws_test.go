@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// startWebSocketServer starts a real HTTP server, the WebSocket handshake hijacks the connection
+func startWebSocketServer(t *testing.T) (string, *app.App) {
+	cfg := &config.Config{AppID: "test-app-id"}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: models.AppStoreReviews{}}, cfg)
+
+	r := gin.New()
+	r.GET("/ws", ReviewsWebSocket(appService))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/ws", appService
+}
+
+// dialWebSocket connects to url and reads the subscribed message
+func dialWebSocket(t *testing.T, url string) (*websocket.Conn, wsServerMessage) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	subscribed := readWebSocket(t, conn)
+	if subscribed.Type != wsTypeSubscribed {
+		t.Fatalf("Expected a subscribed message first, got %+v", subscribed)
+	}
+	return conn, subscribed
+}
+
+func readWebSocket(t *testing.T, conn *websocket.Conn) wsServerMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message wsServerMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("Failed to read a message: %v", err)
+	}
+	return message
+}
+
+// TestReviewsWebSocket_PushesMatchingReviews verifies the filters of the query string and of subscribe messages
+func TestReviewsWebSocket_PushesMatchingReviews(t *testing.T) {
+	url, appService := startWebSocketServer(t)
+	conn, subscribed := dialWebSocket(t, url+"?rating=1")
+	if subscribed.AppID != "test-app-id" || subscribed.Filters.Rating == nil || *subscribed.Filters.Rating != 1 || subscribed.Stats == nil {
+		t.Errorf("Expected the rating filter and the stats of the app, got %+v", subscribed)
+	}
+
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		streamTestReview("review-5-stars", 5),
+		streamTestReview("review-1-star", 1),
+	})
+	review := readWebSocket(t, conn)
+	if review.Type != wsTypeReview || review.Review.ID != "review-1-star" || review.ID == 0 {
+		t.Errorf("Expected the 1-star review, got %+v", review)
+	}
+
+	conn.WriteJSON(wsClientMessage{Type: wsTypeSubscribe, Filters: wsFilters{Q: "refund"}})
+	if subscribed := readWebSocket(t, conn); subscribed.Type != wsTypeSubscribed || subscribed.Filters.Rating != nil || subscribed.Filters.Q != "refund" {
+		t.Errorf("Expected the keyword filter to replace the rating one, got %+v", subscribed)
+	}
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		{ID: "review-fast", Title: "Fast", Content: "Works fine", Rating: 1, UpdatedAt: time.Now().UTC()},
+		{ID: "review-refund", Title: "Charged", Content: "I want a refund", Rating: 2, UpdatedAt: time.Now().UTC()},
+	})
+	if review := readWebSocket(t, conn); review.Review == nil || review.Review.ID != "review-refund" {
+		t.Errorf("Expected the review with the keyword, got %+v", review)
+	}
+}
+
+// TestReviewsWebSocket_ResumesFromLastEventID verifies that subscriptions replay the events after lastEventId
+func TestReviewsWebSocket_ResumesFromLastEventID(t *testing.T) {
+	url, appService := startWebSocketServer(t)
+	start := appService.Events().LastID()
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		streamTestReview("review-1", 5),
+		streamTestReview("review-2", 4),
+		streamTestReview("review-3", 3),
+	})
+
+	conn, _ := dialWebSocket(t, fmt.Sprintf("%s?lastEventId=%d", url, start+1))
+	first := readWebSocket(t, conn)
+	second := readWebSocket(t, conn)
+	if first.ID != start+2 || second.ID != start+3 {
+		t.Errorf("Expected events 2 and 3 to be replayed, got %d and %d", first.ID, second.ID)
+	}
+
+	// an event of a previous run is unknown, the client reloads and resumes from the last event
+	conn.WriteJSON(wsClientMessage{Type: wsTypeSubscribe, LastEventID: 1})
+	readWebSocket(t, conn) // subscribed
+	if reset := readWebSocket(t, conn); reset.Type != wsTypeReset || reset.ID != start+3 {
+		t.Errorf("Expected a reset to event %d, got %+v", start+3, reset)
+	}
+}
+
+// TestReviewsWebSocket_InvalidFilters verifies that invalid filters fail the handshake, and invalid messages are answered with an error
+func TestReviewsWebSocket_InvalidFilters(t *testing.T) {
+	url, _ := startWebSocketServer(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?sentiment=angry", nil)
+	if !errors.Is(err, websocket.ErrBadHandshake) || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected the handshake to fail with status 400, got %v", err)
+	}
+
+	conn, _ := dialWebSocket(t, url)
+	tests := []struct {
+		message string
+		field   string
+	}{
+		{`{"type": "subscribe", "filters": {"rating": 9}}`, "filters.rating"},
+		{`{"type": "subscribe", "filters": {"appId": "other-app"}}`, "filters.appId"},
+		{`{"type": "unsubscribe"}`, "type"},
+		{`not json`, "type"},
+	}
+	for _, tt := range tests {
+		conn.WriteMessage(websocket.TextMessage, []byte(tt.message))
+		message := readWebSocket(t, conn)
+		if message.Type != wsTypeError || message.Error == nil || message.Error.Field != tt.field {
+			t.Errorf("Expected an error of %s for %s, got %+v", tt.field, tt.message, message)
+		}
+	}
+}
+
+// TestReviewsWebSocket_SendsStatsDeltas verifies that stats changes are sent as deltas from the last stats sent
+func TestReviewsWebSocket_SendsStatsDeltas(t *testing.T) {
+	previousInterval := wsStatsInterval
+	wsStatsInterval = 20 * time.Millisecond
+	t.Cleanup(func() { wsStatsInterval = previousInterval })
+
+	url, appService := startWebSocketServer(t)
+	conn, subscribed := dialWebSocket(t, url+"?rating=3")
+	if subscribed.Stats.Count != 0 {
+		t.Errorf("Expected empty stats, got %+v", subscribed.Stats)
+	}
+
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		streamTestReview("review-5-stars", 5),
+		streamTestReview("review-1-star", 1),
+	})
+	message := readWebSocket(t, conn)
+	if message.Type != wsTypeStats || message.AppID != "test-app-id" {
+		t.Fatalf("Expected a stats message, the reviews not matching the filters, got %+v", message)
+	}
+	delta := message.Delta
+	if delta.Count != 2 || delta.AverageRating != 3 || len(delta.RatingCounts) != 2 || delta.RatingCounts[1] != 1 || delta.RatingCounts[5] != 1 {
+		t.Errorf("Expected 2 more reviews, one of 1 and one of 5 stars, got %+v", delta)
+	}
+
+	appService.AddReviews("test-app-id", []models.AppStoreReview{streamTestReview("review-another-5-stars", 5)})
+	delta = readWebSocket(t, conn).Delta
+	if delta == nil || delta.Count != 1 || len(delta.RatingCounts) != 1 || delta.RatingCounts[5] != 1 {
+		t.Errorf("Expected the delta of the new review only, got %+v", delta)
+	}
+}
+
+// TestReviewsWebSocket_Keepalive verifies that connections are pinged and closed when the client doesn't answer
+func TestReviewsWebSocket_Keepalive(t *testing.T) {
+	previousInterval, previousWait := wsPingInterval, wsPongWait
+	wsPingInterval, wsPongWait = 20*time.Millisecond, 200*time.Millisecond
+	t.Cleanup(func() { wsPingInterval, wsPongWait = previousInterval, previousWait })
+
+	url, _ := startWebSocketServer(t)
+
+	// the client answers pings while it reads
+	conn, _ := dialWebSocket(t, url)
+	var pings atomic.Int32
+	conn.SetPingHandler(func(data string) error {
+		pings.Add(1)
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	if _, _, err := conn.ReadMessage(); !isTimeout(err) {
+		t.Errorf("Expected the connection to stay open, got %v", err)
+	}
+	if pings.Load() < 3 {
+		t.Errorf("Expected pings every 20ms, got %d", pings.Load())
+	}
+
+	// this one stops reading, so it doesn't answer
+	silent, _ := dialWebSocket(t, url)
+	time.Sleep(400 * time.Millisecond)
+	silent.SetPingHandler(func(string) error { return nil })
+	silent.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, _, err := silent.ReadMessage()
+		if err == nil {
+			continue
+		}
+		if isTimeout(err) {
+			t.Errorf("Expected the server to close the connection")
+		}
+		break
+	}
+}
+
+func isTimeout(err error) bool {
+	var netError interface{ Timeout() bool }
+	return errors.As(err, &netError) && netError.Timeout()
+}
+
+// TestReviewsWebSocket_DropsSlowClients verifies that a client that stops reading is closed with 1013 instead of
+// buffering its events without limit
+func TestReviewsWebSocket_DropsSlowClients(t *testing.T) {
+	url, appService := startWebSocketServer(t)
+	conn, _ := dialWebSocket(t, url)
+
+	// far more than the socket buffers and the hub buffer hold together
+	content := strings.Repeat("slow ", 64*1024/5)
+	reviews := make([]models.AppStoreReview, 500)
+	for i := range reviews {
+		reviews[i] = models.AppStoreReview{ID: fmt.Sprintf("review-%d", i), Content: content, Rating: 3, UpdatedAt: time.Now().UTC()}
+	}
+	appService.Events().Publish("test-app-id", reviews)
+
+	received := 0
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			received++
+			continue
+		}
+		if !websocket.IsCloseError(err, websocket.CloseTryAgainLater) {
+			t.Errorf("Expected a 1013 close, got %v", err)
+		}
+		break
+	}
+	if received == 0 || received >= len(reviews) {
+		t.Errorf("Expected some of the %d reviews before the close, got %d", len(reviews), received)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type createTagRuleRequest struct {
+	Tag     string `json:"tag"`
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+}
+
+// ListTagRules returns the tag rules of the config file followed by the ones created through the API
+func ListTagRules(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, TagRuleListResponse{Rules: appService.TagRules()})
+	}
+}
+
+// CreateTagRule stores a tag rule and tags the stored reviews matching it
+func CreateTagRule(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request createTagRuleRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			problem.InvalidBody(c)
+			return
+		}
+
+		rule, err := appService.AddTagRule(models.TagRule{
+			Tag:     request.Tag,
+			Type:    request.Type,
+			Pattern: request.Pattern,
+		})
+		if errors.Is(err, app.ErrInvalidTagRule) {
+			problem.Invalid(c, err)
+			return
+		}
+		if err != nil {
+			problem.Internal(c, "Error saving tag rule")
+			return
+		}
+
+		c.JSON(http.StatusCreated, rule)
+	}
+}
+
+// DeleteTagRule deletes a tag rule created through the API and removes its tag from the stored reviews
+func DeleteTagRule(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		found, err := appService.DeleteTagRule(c.Param("id"))
+		if errors.Is(err, app.ErrConfigTagRule) {
+			problem.Conflict(c, "Tag rule is defined in the config file, remove it from there")
+			return
+		}
+		if err != nil {
+			problem.Internal(c, "Error deleting tag rule")
+			return
+		}
+		if !found {
+			problem.NotFound(c, "Tag rule not found")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
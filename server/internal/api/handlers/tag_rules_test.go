@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// createTagTestRouter serves the reviews, stats and tag rules endpoints with a "billing" config rule
+func createTagTestRouter() (*gin.Engine, *app.App) {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "review-1", Title: "Refund", Content: "I was charged twice", Rating: 1, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Slow", Content: "Takes forever to load", Rating: 2, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-3", Title: "Laggy and pricey", Content: "The subscription is too expensive", Rating: 2, UpdatedAt: now.Add(-3 * time.Hour)},
+	}
+	cfg := &config.Config{
+		AppID: "test-app-id",
+		TagRules: []models.TagRule{
+			{ID: "config-1", Tag: "billing", Type: models.TagRuleKeyword, Pattern: "refund, subscription", Source: models.TagRuleSourceConfig},
+		},
+	}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: reviews}, cfg)
+
+	r := gin.New()
+	r.GET("/reviews", ListReviews(appService))
+	r.GET("/reviews/stats", ReviewsStats(appService))
+	r.GET("/admin/tag-rules", ListTagRules(appService))
+	r.POST("/admin/tag-rules", CreateTagRule(appService))
+	r.DELETE("/admin/tag-rules/:id", DeleteTagRule(appService))
+	return r, appService
+}
+
+func doJSONRequest(r *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func reviewIDs(reviews []models.AppStoreReview) []string {
+	ids := make([]string, len(reviews))
+	for i, review := range reviews {
+		ids[i] = review.ID
+	}
+	return ids
+}
+
+// TestListReviews_TagFilter verifies that stored reviews are tagged by the config rules when loaded
+func TestListReviews_TagFilter(t *testing.T) {
+	r, _ := createTagTestRouter()
+
+	reviews := decodeReviews(t, doRequest(r, "/reviews?tag=billing", nil).Body.Bytes())
+	if ids := reviewIDs(reviews); !slices.Equal(ids, []string{"review-1", "review-3"}) {
+		t.Errorf("Expected the billing reviews, got %v", ids)
+	}
+	if !slices.Equal(reviews[0].Tags, []string{"billing"}) {
+		t.Errorf("Expected tags in the response, got %v", reviews[0].Tags)
+	}
+
+	if reviews := decodeReviews(t, doRequest(r, "/reviews?tag=unknown", nil).Body.Bytes()); len(reviews) != 0 {
+		t.Errorf("Expected no reviews for an unknown tag, got %v", reviewIDs(reviews))
+	}
+}
+
+// TestCreateTagRule_TagsStoredReviews verifies that new and deleted rules apply retroactively
+func TestCreateTagRule_TagsStoredReviews(t *testing.T) {
+	r, appService := createTagTestRouter()
+
+	w := doJSONRequest(r, http.MethodPost, "/admin/tag-rules", `{"tag": "performance", "type": "boolean", "pattern": "slow OR laggy OR \"takes forever\""}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var rule models.TagRule
+	if err := json.Unmarshal(w.Body.Bytes(), &rule); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if rule.ID == "" || rule.Source != models.TagRuleSourceAPI || rule.CreatedAt == nil {
+		t.Errorf("Expected an API rule with ID and creation time, got %+v", rule)
+	}
+
+	reviews := decodeReviews(t, doRequest(r, "/reviews?tag=performance", nil).Body.Bytes())
+	if ids := reviewIDs(reviews); !slices.Equal(ids, []string{"review-2", "review-3"}) {
+		t.Errorf("Expected stored reviews to be tagged, got %v", ids)
+	}
+	if !slices.Equal(reviews[1].Tags, []string{"billing", "performance"}) {
+		t.Errorf("Expected both tags on review-3, got %v", reviews[1].Tags)
+	}
+
+	added, err := appService.AddReviews("test-app-id", []models.AppStoreReview{
+		{ID: "review-4", Title: "So slow", Rating: 1, UpdatedAt: time.Now().UTC()},
+	})
+	if err != nil || len(added) != 1 || !slices.Equal(added[0].Tags, []string{"performance"}) {
+		t.Errorf("Expected new reviews to be tagged when added, got %+v (%v)", added, err)
+	}
+
+	var stats struct {
+		Stats repositories.ReviewStats `json:"stats"`
+	}
+	json.Unmarshal(doRequest(r, "/reviews/stats", nil).Body.Bytes(), &stats)
+	if stats.Stats.TagCounts["billing"] != 2 || stats.Stats.TagCounts["performance"] != 3 {
+		t.Errorf("Unexpected tag counts: %v", stats.Stats.TagCounts)
+	}
+
+	if w := doJSONRequest(r, http.MethodDelete, "/admin/tag-rules/"+rule.ID, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if reviews := decodeReviews(t, doRequest(r, "/reviews?tag=performance", nil).Body.Bytes()); len(reviews) != 0 {
+		t.Errorf("Expected tags of the deleted rule to be removed, got %v", reviewIDs(reviews))
+	}
+}
+
+// TestTagRules_AdminErrors verifies the validation of new rules and that config rules can't be deleted
+func TestTagRules_AdminErrors(t *testing.T) {
+	r, _ := createTagTestRouter()
+
+	if w := doJSONRequest(r, http.MethodPost, "/admin/tag-rules", `{"tag": "ui", "type": "regex", "pattern": "dark("}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid regex, got %d", w.Code)
+	}
+	if w := doJSONRequest(r, http.MethodPost, "/admin/tag-rules", `{"tag": "ui", "type": "fuzzy", "pattern": "dark"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown type, got %d", w.Code)
+	}
+	if w := doJSONRequest(r, http.MethodDelete, "/admin/tag-rules/config-1", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 deleting a config rule, got %d", w.Code)
+	}
+	if w := doJSONRequest(r, http.MethodDelete, "/admin/tag-rules/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown rule, got %d", w.Code)
+	}
+
+	var response struct {
+		Rules []models.TagRule `json:"rules"`
+	}
+	json.Unmarshal(doRequest(r, "/admin/tag-rules", nil).Body.Bytes(), &response)
+	if len(response.Rules) != 1 || response.Rules[0].ID != "config-1" {
+		t.Errorf("Expected only the config rule, got %+v", response.Rules)
+	}
+}
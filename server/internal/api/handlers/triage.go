@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+type updateTriageRequest struct {
+	Status   *string   `json:"status"`
+	Assignee *string   `json:"assignee"`
+	Labels   *[]string `json:"labels"`
+}
+
+type addNoteRequest struct {
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+// GetReviewTriage returns the triage of a review, status new if it was never triaged
+func GetReviewTriage(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+
+		triage, err := appService.Triage(appID, c.Param("id"))
+		if err != nil {
+			writeTriageError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, triage)
+	}
+}
+
+// UpdateReviewTriage changes the status, assignee or labels of a review, the fields missing from the body are kept
+func UpdateReviewTriage(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+		var request updateTriageRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			problem.InvalidBody(c)
+			return
+		}
+
+		triage, err := appService.UpdateTriage(appID, c.Param("id"), app.TriageUpdate{
+			Status:   request.Status,
+			Assignee: request.Assignee,
+			Labels:   request.Labels,
+		})
+		if err != nil {
+			writeTriageError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, triage)
+	}
+}
+
+// AddReviewNote adds an internal note to the triage of a review
+func AddReviewNote(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appID, ok := parseAppIDQuery(c, appService)
+		if !ok {
+			return
+		}
+		var request addNoteRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			problem.InvalidBody(c)
+			return
+		}
+
+		note, err := appService.AddTriageNote(appID, c.Param("id"), request.Author, request.Text)
+		if err != nil {
+			writeTriageError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, note)
+	}
+}
+
+func writeTriageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, app.ErrReviewNotFound):
+		problem.NotFound(c, "Review not found")
+	case errors.Is(err, app.ErrInvalidTriage):
+		problem.Invalid(c, err)
+	default:
+		problem.Internal(c, "Error saving triage")
+	}
+}
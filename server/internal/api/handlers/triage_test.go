@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// createTriageTestRouter serves the reviews and triage endpoints with triage stored in dataDir
+func createTriageTestRouter(dataDir string) (*gin.Engine, *app.App) {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "review-1", Title: "Charged twice", Content: "Please refund me", Rating: 1, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Crash", Content: "Crashes on start", Rating: 1, UpdatedAt: now.Add(-2 * time.Hour)},
+	}
+	cfg := &config.Config{AppID: "test-app-id", StorageFilePath: filepath.Join(dataDir, "reviews.json")}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: reviews}, cfg)
+
+	r := gin.New()
+	r.GET("/reviews", ListReviews(appService))
+	r.GET("/reviews/:id/triage", GetReviewTriage(appService))
+	r.PATCH("/reviews/:id/triage", UpdateReviewTriage(appService))
+	r.POST("/reviews/:id/notes", AddReviewNote(appService))
+	return r, appService
+}
+
+func decodeTriage(t *testing.T, body []byte) models.Triage {
+	var triage models.Triage
+	if err := json.Unmarshal(body, &triage); err != nil {
+		t.Fatalf("Failed to decode triage: %v", err)
+	}
+	return triage
+}
+
+// TestUpdateReviewTriage_PartialUpdates verifies that only the fields sent are changed
+func TestUpdateReviewTriage_PartialUpdates(t *testing.T) {
+	r, _ := createTriageTestRouter(t.TempDir())
+
+	triage := decodeTriage(t, doRequest(r, "/reviews/review-1/triage", nil).Body.Bytes())
+	if triage.Status != models.TriageNew || triage.UpdatedAt != nil || len(triage.Labels) != 0 {
+		t.Errorf("Expected an untriaged review to be new, got %+v", triage)
+	}
+
+	w := doJSONRequest(r, http.MethodPatch, "/reviews/review-1/triage", `{"status": "acknowledged", "assignee": " ana ", "labels": ["refund", "billing", "refund", " "]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	triage = decodeTriage(t, w.Body.Bytes())
+	if triage.Status != models.TriageAcknowledged || triage.Assignee != "ana" || !slices.Equal(triage.Labels, []string{"billing", "refund"}) || triage.UpdatedAt == nil {
+		t.Errorf("Unexpected triage: %+v", triage)
+	}
+
+	triage = decodeTriage(t, doJSONRequest(r, http.MethodPatch, "/reviews/review-1/triage", `{"status": "resolved"}`).Body.Bytes())
+	if triage.Status != models.TriageResolved || triage.Assignee != "ana" || len(triage.Labels) != 2 {
+		t.Errorf("Expected assignee and labels to be kept, got %+v", triage)
+	}
+
+	triage = decodeTriage(t, doJSONRequest(r, http.MethodPatch, "/reviews/review-1/triage", `{"assignee": "", "labels": []}`).Body.Bytes())
+	if triage.Assignee != "" || len(triage.Labels) != 0 || triage.Status != models.TriageResolved {
+		t.Errorf("Expected assignee and labels to be cleared, got %+v", triage)
+	}
+}
+
+// TestAddReviewNote verifies that notes are added in order, with an ID and a timestamp
+func TestAddReviewNote(t *testing.T) {
+	r, _ := createTriageTestRouter(t.TempDir())
+
+	w := doJSONRequest(r, http.MethodPost, "/reviews/review-2/notes", `{"author": "ana", "text": "Reproduced on iOS 17"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var note models.TriageNote
+	json.Unmarshal(w.Body.Bytes(), &note)
+	if note.ID == "" || note.Author != "ana" || note.Text != "Reproduced on iOS 17" || note.CreatedAt.IsZero() {
+		t.Errorf("Unexpected note: %+v", note)
+	}
+	doJSONRequest(r, http.MethodPost, "/reviews/review-2/notes", `{"text": "Fixed in 2.3"}`)
+
+	triage := decodeTriage(t, doRequest(r, "/reviews/review-2/triage", nil).Body.Bytes())
+	if len(triage.Notes) != 2 || triage.Notes[0].ID != note.ID || triage.Notes[1].Text != "Fixed in 2.3" {
+		t.Errorf("Expected both notes oldest first, got %+v", triage.Notes)
+	}
+	if triage.Status != models.TriageNew {
+		t.Errorf("Expected notes to keep the status, got %s", triage.Status)
+	}
+}
+
+// TestTriage_Errors verifies the responses to unknown reviews and invalid input
+func TestTriage_Errors(t *testing.T) {
+	r, _ := createTriageTestRouter(t.TempDir())
+
+	tests := []struct {
+		method   string
+		url      string
+		body     string
+		expected int
+	}{
+		{http.MethodGet, "/reviews/missing/triage", "", http.StatusNotFound},
+		{http.MethodPatch, "/reviews/missing/triage", `{"status": "resolved"}`, http.StatusNotFound},
+		{http.MethodPost, "/reviews/missing/notes", `{"text": "hi"}`, http.StatusNotFound},
+		{http.MethodPatch, "/reviews/review-1/triage", `{"status": "done"}`, http.StatusBadRequest},
+		{http.MethodPatch, "/reviews/review-1/triage", `{"labels": "billing"}`, http.StatusBadRequest},
+		{http.MethodPost, "/reviews/review-1/notes", `{"text": "  "}`, http.StatusBadRequest},
+		{http.MethodGet, "/reviews/review-1/triage?appId=other", "", http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		if w := doJSONRequest(r, test.method, test.url, test.body); w.Code != test.expected {
+			t.Errorf("Expected status %d for %s %s, got %d", test.expected, test.method, test.url, w.Code)
+		}
+	}
+}
+
+// TestTriage_SurvivesRepollAndRestart verifies that triage is stored apart from the reviews
+func TestTriage_SurvivesRepollAndRestart(t *testing.T) {
+	dataDir := t.TempDir()
+	r, appService := createTriageTestRouter(dataDir)
+
+	doJSONRequest(r, http.MethodPatch, "/reviews/review-1/triage", `{"status": "acknowledged", "assignee": "ana"}`)
+	doJSONRequest(r, http.MethodPost, "/reviews/review-1/notes", `{"text": "Refund sent"}`)
+
+	// the feed brings the same review again
+	appService.AddReviews("test-app-id", []models.AppStoreReview{{ID: "review-1", Title: "Charged twice", Rating: 1, UpdatedAt: time.Now().UTC()}})
+
+	r, _ = createTriageTestRouter(dataDir)
+	triage := decodeTriage(t, doRequest(r, "/reviews/review-1/triage", nil).Body.Bytes())
+	if triage.Status != models.TriageAcknowledged || triage.Assignee != "ana" || len(triage.Notes) != 1 {
+		t.Errorf("Expected triage to survive, got %+v", triage)
+	}
+
+	if ids := reviewIDs(decodeReviews(t, doRequest(r, "/reviews?status=acknowledged&assignee=ana", nil).Body.Bytes())); !slices.Equal(ids, []string{"review-1"}) {
+		t.Errorf("Expected the acknowledged review, got %v", ids)
+	}
+	if ids := reviewIDs(decodeReviews(t, doRequest(r, "/reviews?status=new", nil).Body.Bytes())); !slices.Equal(ids, []string{"review-2"}) {
+		t.Errorf("Expected untriaged reviews to be new, got %v", ids)
+	}
+	if w := doRequest(r, "/reviews?status=open", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid status, got %d", w.Code)
+	}
+}
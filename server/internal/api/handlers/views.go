@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/ids"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const maxViewNameLength = 100
+
+type viewRequest struct {
+	Name   string            `json:"name"`
+	Filter models.ViewFilter `json:"filter"`
+}
+
+// bindViewRequest reads and validates a view, the filter with the same rules as GET /reviews.
+// On invalid input it writes a 400 response and returns false.
+func bindViewRequest(c *gin.Context, appService *app.App) (viewRequest, bool) {
+	var request viewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problem.InvalidBody(c)
+		return request, false
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		problem.Invalid(c, problem.Body("name", problem.FieldRequired, "Name is required and must have at most 100 characters"))
+		return request, false
+	}
+	if utf8.RuneCountInString(request.Name) > maxViewNameLength {
+		problem.Invalid(c, problem.Body("name", problem.FieldOutOfRange, "Name is required and must have at most 100 characters"))
+		return request, false
+	}
+
+	if _, err := parseReviewsValues(request.Filter.Values(), appService); err != nil {
+		// the filter fields have the names of the query parameters
+		var fieldError *problem.FieldError
+		if errors.As(err, &fieldError) {
+			err = problem.Body("filter."+fieldError.Field, fieldError.Code, fieldError.Message)
+		}
+		problem.Invalid(c, err)
+		return request, false
+	}
+	return request, true
+}
+
+// ListViews returns every saved view
+func ListViews(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, ViewListResponse{Views: appService.Views().List()})
+	}
+}
+
+// CreateView stores a named reviews filter
+func CreateView(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request, ok := bindViewRequest(c, appService)
+		if !ok {
+			return
+		}
+
+		now := time.Now().UTC()
+		view := models.View{
+			ID:        ids.New(),
+			Name:      request.Name,
+			Filter:    request.Filter,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := appService.Views().Add(view); err != nil {
+			problem.Internal(c, "Error saving view")
+			return
+		}
+
+		c.JSON(http.StatusCreated, view)
+	}
+}
+
+// GetView returns a saved view
+func GetView(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		view, found := appService.Views().Get(c.Param("id"))
+		if !found {
+			problem.NotFound(c, "View not found")
+			return
+		}
+		c.JSON(http.StatusOK, view)
+	}
+}
+
+// UpdateView replaces the name and filter of a saved view
+func UpdateView(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		view, found := appService.Views().Get(c.Param("id"))
+		if !found {
+			problem.NotFound(c, "View not found")
+			return
+		}
+
+		request, ok := bindViewRequest(c, appService)
+		if !ok {
+			return
+		}
+
+		view.Name = request.Name
+		view.Filter = request.Filter
+		view.UpdatedAt = time.Now().UTC()
+		found, err := appService.Views().Update(view)
+		if err != nil {
+			problem.Internal(c, "Error saving view")
+			return
+		}
+		if !found { // deleted while it was being updated
+			problem.NotFound(c, "View not found")
+			return
+		}
+
+		c.JSON(http.StatusOK, view)
+	}
+}
+
+// DeleteView deletes a saved view
+func DeleteView(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		found, err := appService.Views().Delete(c.Param("id"))
+		if err != nil {
+			problem.Internal(c, "Error deleting view")
+			return
+		}
+		if !found {
+			problem.NotFound(c, "View not found")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListViewReviews runs the filter of a saved view, answering like GET /reviews.
+// Only the format is taken from the request, through the format parameter or the Accept header.
+func ListViewReviews(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		view, found := appService.Views().Get(c.Param("id"))
+		if !found {
+			problem.NotFound(c, "View not found")
+			return
+		}
+
+		query, err := parseReviewsValues(view.Filter.Values(), appService)
+		if err != nil { // e.g. its app is no longer tracked
+			problem.Conflict(c, "View filter is no longer valid: "+err.Error())
+			return
+		}
+
+		writeReviews(c, appService, query, view.UpdatedAt)
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// createViewsTestRouter serves the views endpoints with views stored in dataDir and a "billing" tag rule
+func createViewsTestRouter(dataDir string) (*gin.Engine, *app.App) {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "review-1", Title: "Charged twice", Content: "Please refund me", Rating: 1, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Refund", Content: "Asked for a refund days ago", Rating: 1, UpdatedAt: now.Add(-30 * time.Hour)},
+		{ID: "review-3", Title: "Crash", Content: "Crashes on start", Rating: 1, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-4", Title: "Refund", Content: "Got my refund quickly", Rating: 5, UpdatedAt: now.Add(-3 * time.Hour)},
+	}
+	cfg := &config.Config{
+		AppID:           "test-app-id",
+		StorageFilePath: filepath.Join(dataDir, "reviews.json"),
+		TagRules: []models.TagRule{
+			{ID: "config-1", Tag: "billing", Type: models.TagRuleKeyword, Pattern: "refund, charged", Source: models.TagRuleSourceConfig},
+		},
+	}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: reviews}, cfg)
+
+	r := gin.New()
+	r.GET("/views", ListViews(appService))
+	r.POST("/views", CreateView(appService))
+	r.GET("/views/:id", GetView(appService))
+	r.PUT("/views/:id", UpdateView(appService))
+	r.DELETE("/views/:id", DeleteView(appService))
+	r.GET("/views/:id/reviews", ListViewReviews(appService))
+	return r, appService
+}
+
+func decodeView(t *testing.T, body []byte) models.View {
+	var view models.View
+	if err := json.Unmarshal(body, &view); err != nil {
+		t.Fatalf("Failed to decode view: %v", err)
+	}
+	return view
+}
+
+// TestListViewReviews_RunsStoredFilter verifies that a view returns the reviews matching its stored filter
+func TestListViewReviews_RunsStoredFilter(t *testing.T) {
+	r, _ := createViewsTestRouter(t.TempDir())
+
+	w := doJSONRequest(r, http.MethodPost, "/views", `{"name": " Last 24h 1-star billing ", "filter": {"hours": 24, "rating": 1, "tag": "billing"}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	view := decodeView(t, w.Body.Bytes())
+	if view.ID == "" || view.Name != "Last 24h 1-star billing" || view.CreatedAt.IsZero() {
+		t.Errorf("Unexpected view: %+v", view)
+	}
+
+	w = doRequest(r, "/views/"+view.ID+"/reviews", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ids := reviewIDs(decodeReviews(t, w.Body.Bytes())); !slices.Equal(ids, []string{"review-1"}) {
+		t.Errorf("Expected only review-1, got %v", ids)
+	}
+
+	w = doRequest(r, "/views/"+view.ID+"/reviews?format=csv", nil)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Errorf("Expected a CSV export, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+// TestCreateView_ValidatesFilter verifies that views are validated with the rules of GET /reviews
+func TestCreateView_ValidatesFilter(t *testing.T) {
+	r, _ := createViewsTestRouter(t.TempDir())
+
+	for _, body := range []string{
+		`{"name": "", "filter": {}}`,
+		`{"name": "Too many hours", "filter": {"hours": 200}}`,
+		`{"name": "Bad rating", "filter": {"rating": 7}}`,
+		`{"name": "Bad status", "filter": {"status": "done"}}`,
+		`{"name": "Untracked", "filter": {"appId": "other-app"}}`,
+	} {
+		if w := doJSONRequest(r, http.MethodPost, "/views", body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, w.Code)
+		}
+	}
+}
+
+// TestCreateView_NameLength verifies that the name length is counted in characters, not bytes
+func TestCreateView_NameLength(t *testing.T) {
+	r, _ := createViewsTestRouter(t.TempDir())
+
+	accented := `{"name": "` + strings.Repeat("é", maxViewNameLength) + `", "filter": {}}`
+	if w := doJSONRequest(r, http.MethodPost, "/views", accented); w.Code != http.StatusCreated {
+		t.Errorf("Expected status 201 for a name of %d two-byte characters, got %d", maxViewNameLength, w.Code)
+	}
+	tooLong := `{"name": "` + strings.Repeat("é", maxViewNameLength+1) + `", "filter": {}}`
+	if w := doJSONRequest(r, http.MethodPost, "/views", tooLong); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a name of %d characters, got %d", maxViewNameLength+1, w.Code)
+	}
+}
+
+// TestViews_CRUD verifies that views can be updated and deleted, and are kept after a restart
+func TestViews_CRUD(t *testing.T) {
+	dataDir := t.TempDir()
+	r, _ := createViewsTestRouter(dataDir)
+
+	view := decodeView(t, doJSONRequest(r, http.MethodPost, "/views", `{"name": "Crashes", "filter": {"q": "crash"}}`).Body.Bytes())
+
+	w := doJSONRequest(r, http.MethodPut, "/views/"+view.ID, `{"name": "Refunds", "filter": {"q": "refund", "sort": "oldest"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	updated := decodeView(t, w.Body.Bytes())
+	if updated.Name != "Refunds" || updated.Filter.Q != "refund" || !updated.CreatedAt.Equal(view.CreatedAt) {
+		t.Errorf("Unexpected updated view: %+v", updated)
+	}
+
+	// a restart loads the views from storage
+	r, _ = createViewsTestRouter(dataDir)
+	shared := decodeView(t, doRequest(r, "/views/"+view.ID, nil).Body.Bytes())
+	if shared.Name != "Refunds" || shared.Filter.Sort != models.SortOldest {
+		t.Errorf("Expected the updated view to be stored, got %+v", shared)
+	}
+	if ids := reviewIDs(decodeReviews(t, doRequest(r, "/views/"+view.ID+"/reviews", nil).Body.Bytes())); !slices.Equal(ids, []string{"review-2", "review-4", "review-1"}) {
+		t.Errorf("Expected refund reviews oldest first, got %v", ids)
+	}
+
+	if w := doJSONRequest(r, http.MethodDelete, "/views/"+view.ID, ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+	for _, url := range []string{"/views/" + view.ID, "/views/" + view.ID + "/reviews"} {
+		if w := doRequest(r, url, nil); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s, got %d", url, w.Code)
+		}
+	}
+	if w := doJSONRequest(r, http.MethodPut, "/views/missing", `{"name": "Missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 updating a missing view, got %d", w.Code)
+	}
+}
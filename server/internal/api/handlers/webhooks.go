@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type createWebhookRequest struct {
+	URL     string `json:"url"`
+	AppID   string `json:"appId"`
+	Ratings []int  `json:"ratings"`
+	Secret  string `json:"secret"`
+}
+
+// webhookResponse hides the secret, it is only returned once by CreateWebhook
+type webhookResponse struct {
+	models.WebhookSubscription
+	Secret string `json:"secret,omitempty"`
+}
+
+func ListWebhooks(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subscriptions := appService.Webhooks().Subscriptions()
+
+		response := make([]webhookResponse, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			response = append(response, webhookResponse{WebhookSubscription: subscription})
+		}
+		c.JSON(http.StatusOK, WebhookListResponse{Webhooks: response})
+	}
+}
+
+func CreateWebhook(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request createWebhookRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			problem.InvalidBody(c)
+			return
+		}
+
+		parsedURL, err := url.Parse(request.URL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			problem.Invalid(c, problem.Body("url", problem.FieldInvalid, "Invalid url, must be an absolute http(s) URL"))
+			return
+		}
+		for _, rating := range request.Ratings {
+			if !slices.Contains(validRatings, rating) {
+				problem.Invalid(c, problem.Body("ratings", problem.FieldOutOfRange, "Invalid ratings, must be between 1 and 5"))
+				return
+			}
+		}
+		if request.AppID != "" && !appService.IsTracked(request.AppID) {
+			problem.Invalid(c, problem.Body("appId", problem.FieldNotTracked, "App is not tracked"))
+			return
+		}
+
+		subscription, err := appService.Webhooks().Subscribe(request.URL, request.AppID, request.Ratings, request.Secret)
+		if err != nil {
+			problem.Internal(c, "Error saving webhook")
+			return
+		}
+
+		// the secret is returned only on creation, receivers need it to verify signatures
+		c.JSON(http.StatusCreated, webhookResponse{WebhookSubscription: subscription, Secret: subscription.Secret})
+	}
+}
+
+func DeleteWebhook(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		found, err := appService.Webhooks().Unsubscribe(c.Param("id"))
+		if err != nil {
+			problem.Internal(c, "Error deleting webhook")
+			return
+		}
+		if !found {
+			problem.NotFound(c, "Webhook not found")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func ListWebhookOutbox(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, DeliveryListResponse{Deliveries: appService.Webhooks().Outbox()})
+	}
+}
+
+func ListWebhookDeadLetters(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, DeliveryListResponse{Deliveries: appService.Webhooks().DeadLetters()})
+	}
+}
+
+func RetryWebhookDeadLetter(appService *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		found, err := appService.Webhooks().RetryDeadLetter(c.Param("id"))
+		if err != nil {
+			problem.Internal(c, "Error requeuing delivery")
+			return
+		}
+		if !found {
+			problem.NotFound(c, "Delivery not found")
+			return
+		}
+		c.Status(http.StatusAccepted)
+	}
+}
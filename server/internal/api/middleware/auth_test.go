@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// TestMain suppresses logs and sets Gin to test mode during all tests
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	gin.SetMode(gin.TestMode)
+
+	code := m.Run()
+
+	log.SetOutput(os.Stderr)
+	os.Exit(code)
+}
+
+// createAuthTestRouter serves a route per role, with API keys stored next to the cfg storage
+func createAuthTestRouter(cfg *config.Config) (*gin.Engine, *app.App) {
+	appService := app.New(&repositories.AppReviewsRepository{}, cfg)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	r := gin.New()
+	r.Use(CORS(appService))
+	r.GET("/reviews", RequireRole(appService, models.RoleReadOnly), ok)
+	r.PATCH("/reviews/:id/triage", RequireRole(appService, models.RoleTriage), ok)
+	r.GET("/admin/flags", RequireRole(appService, models.RoleAdmin), func(c *gin.Context) {
+		key, _ := APIKey(c)
+		c.String(http.StatusOK, key.Name)
+	})
+	return r, appService
+}
+
+func authTestConfig(dataDir string) *config.Config {
+	return &config.Config{
+		AppID:              "test-app-id",
+		StorageFilePath:    filepath.Join(dataDir, "reviews.json"),
+		AuthEnabled:        true,
+		CORSAllowedOrigins: []string{"https://reviews.example.com"},
+	}
+}
+
+func doAuthRequest(r *gin.Engine, method, url string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestRequireRole_EnforcesRoles verifies that each route group needs a key with at least its role
+func TestRequireRole_EnforcesRoles(t *testing.T) {
+	r, appService := createAuthTestRouter(authTestConfig(t.TempDir()))
+	_, reader, _ := appService.APIKeys().Create("dashboard", models.RoleReadOnly)
+	_, support, _ := appService.APIKeys().Create("support", models.RoleTriage)
+	_, admin, _ := appService.APIKeys().Create("ops", models.RoleAdmin)
+
+	tests := []struct {
+		method, url string
+		headers     map[string]string
+		expected    int
+	}{
+		{http.MethodGet, "/reviews", nil, http.StatusUnauthorized},
+		{http.MethodGet, "/reviews", map[string]string{"X-API-Key": "ark_unknown"}, http.StatusUnauthorized},
+		{http.MethodGet, "/reviews", map[string]string{"X-API-Key": reader}, http.StatusOK},
+		{http.MethodPatch, "/reviews/review-1/triage", map[string]string{"X-API-Key": reader}, http.StatusForbidden},
+		{http.MethodPatch, "/reviews/review-1/triage", map[string]string{"Authorization": "Bearer " + support}, http.StatusOK},
+		{http.MethodGet, "/admin/flags", map[string]string{"Authorization": "Bearer " + support}, http.StatusForbidden},
+		{http.MethodGet, "/admin/flags", map[string]string{"Authorization": "bearer " + admin}, http.StatusOK},
+		{http.MethodPatch, "/reviews/review-1/triage", map[string]string{"X-API-Key": admin}, http.StatusOK},
+		{http.MethodGet, "/reviews", map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("feed:"+reader))}, http.StatusOK},
+		{http.MethodGet, "/reviews", map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(reader+":"))}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		w := doAuthRequest(r, tt.method, tt.url, tt.headers)
+		if w.Code != tt.expected {
+			t.Errorf("%s %s with %v: expected status %d, got %d", tt.method, tt.url, tt.headers, tt.expected, w.Code)
+		}
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s %s: expected a WWW-Authenticate header on 401", tt.method, tt.url)
+		}
+	}
+
+	if body := doAuthRequest(r, http.MethodGet, "/admin/flags", map[string]string{"X-API-Key": admin}).Body.String(); body != "ops" {
+		t.Errorf("Expected the key to be available to handlers, got %q", body)
+	}
+}
+
+// TestRequireRole_KeysManagedElsewhere verifies that keys created and revoked by the CLI apply to a running server
+func TestRequireRole_KeysManagedElsewhere(t *testing.T) {
+	cfg := authTestConfig(t.TempDir())
+	r, _ := createAuthTestRouter(cfg)
+
+	cli := repositories.LoadAPIKeys(cfg.DataFilePath("api-keys.json"))
+	key, secret, err := cli.Create("script", models.RoleReadOnly)
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	if w := doAuthRequest(r, http.MethodGet, "/reviews", map[string]string{"X-API-Key": secret}); w.Code != http.StatusOK {
+		t.Errorf("Expected a key created by the CLI to be accepted, got %d", w.Code)
+	}
+
+	if _, err := cli.Revoke(key.ID); err != nil {
+		t.Fatalf("Failed to revoke key: %v", err)
+	}
+	if w := doAuthRequest(r, http.MethodGet, "/reviews", map[string]string{"X-API-Key": secret}); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a key revoked by the CLI to be rejected, got %d", w.Code)
+	}
+
+	data, _ := os.ReadFile(cfg.DataFilePath("api-keys.json"))
+	if len(data) == 0 || strings.Contains(string(data), secret) {
+		t.Error("Expected keys to be stored hashed")
+	}
+}
+
+// TestRequireRole_AuthDisabled verifies that every request passes when auth is disabled
+func TestRequireRole_AuthDisabled(t *testing.T) {
+	cfg := authTestConfig(t.TempDir())
+	cfg.AuthEnabled = false
+	r, _ := createAuthTestRouter(cfg)
+
+	if w := doAuthRequest(r, http.MethodGet, "/admin/flags", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with auth disabled, got %d", w.Code)
+	}
+}
+
+// TestCORS_AllowedOrigins verifies that only the configured origins get CORS headers
+func TestCORS_AllowedOrigins(t *testing.T) {
+	r, appService := createAuthTestRouter(authTestConfig(t.TempDir()))
+	_, reader, _ := appService.APIKeys().Create("dashboard", models.RoleReadOnly)
+
+	w := doAuthRequest(r, http.MethodGet, "/reviews", map[string]string{"Origin": "https://reviews.example.com", "X-API-Key": reader})
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://reviews.example.com" {
+		t.Errorf("Expected the allowed origin to be echoed, got %d %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	w = doAuthRequest(r, http.MethodOptions, "/reviews", map[string]string{
+		"Origin":                         "https://reviews.example.com",
+		"Access-Control-Request-Method":  "GET",
+		"Access-Control-Request-Headers": "X-API-Key",
+	})
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Headers") == "" {
+		t.Errorf("Expected a preflight allowing the API key header, got %d %v", w.Code, w.Header())
+	}
+
+	w = doAuthRequest(r, http.MethodGet, "/reviews", map[string]string{"Origin": "https://evil.example.com", "X-API-Key": reader})
+	if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected other origins to be rejected, got %d %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// encoder is a pooled compressor of a content coding
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var encoderPools = map[string]*sync.Pool{
+	"br":   {New: func() any { return brotli.NewWriter(nil) }},
+	"gzip": {New: func() any { return gzip.NewWriter(nil) }},
+}
+
+// Compress compresses response bodies of at least minSize bytes with brotli or gzip, the one the client
+// prefers in Accept-Encoding, brotli on ties. Smaller bodies, event streams and bodies already encoded are
+// sent as they are. Flushes go through, so streamed responses are compressed as they stream.
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize}
+		c.Writer = cw
+		defer cw.finish()
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns the coding of Accept-Encoding with the highest weight, "" for none
+func negotiateEncoding(acceptEncoding string) string {
+	weights := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		weights[strings.ToLower(strings.TrimSpace(coding))] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, coding := range []string{"br", "gzip"} {
+		weight, ok := weights[coding]
+		if !ok {
+			weight, ok = weights["*"]
+		}
+		if ok && weight > bestWeight {
+			best, bestWeight = coding, weight
+		}
+	}
+	return best
+}
+
+// compressWriter holds the body back until it reaches minSize or is flushed, then decides whether to compress it
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	buf      []byte
+	decided  bool
+	encoder  encoder // nil when the body is sent as it is
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports the body as started once any of it is held back, so handlers don't write a second header
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.encoder != nil {
+		w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection, to lift deadlines of streams
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide starts compressing unless the response can't or shouldn't be, then writes what was held back
+func (w *compressWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+	status := w.Status()
+	contentType := header.Get("Content-Type")
+	compress := status != http.StatusNoContent && status != http.StatusNotModified && status >= http.StatusOK &&
+		header.Get("Content-Encoding") == "" && !strings.HasPrefix(contentType, "text/event-stream")
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+		// the compressed body is a different byte sequence, only a weak validator still holds
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.encoder = encoderPools[w.encoding].Get().(encoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// finish writes the body held back as it is, or ends the compressed one
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decided = true
+		if len(w.buf) > 0 {
+			w.ResponseWriter.Write(w.buf)
+		}
+		return
+	}
+	if w.encoder != nil {
+		w.encoder.Close()
+		encoderPools[w.encoding].Put(w.encoder)
+		w.encoder = nil
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+func createCompressTestRouter(body string) *gin.Engine {
+	r := gin.New()
+	r.Use(Compress(1024))
+	r.GET("/json", func(c *gin.Context) {
+		c.Header("ETag", `"v1"`)
+		c.Data(http.StatusOK, "application/json", []byte(body))
+	})
+	r.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, body)
+		c.Writer.Flush()
+	})
+	r.GET("/not-modified", func(c *gin.Context) {
+		c.Status(http.StatusNotModified)
+	})
+	return r
+}
+
+func compressRequest(r *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestCompress_Gzip verifies that large bodies are gzipped for clients that accept it
+func TestCompress_Gzip(t *testing.T) {
+	body := strings.Repeat(`{"id":"1","title":"Great app"},`, 200)
+	w := compressRequest(createCompressTestRouter(body), "/json", "gzip, deflate")
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected gzip encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding, got %q", w.Header().Get("Vary"))
+	}
+	if w.Header().Get("ETag") != `W/"v1"` {
+		t.Errorf("Expected the ETag to be weakened, got %s", w.Header().Get("ETag"))
+	}
+	if w.Body.Len() >= len(body) {
+		t.Errorf("Expected less than %d bytes, got %d", len(body), w.Body.Len())
+	}
+
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Expected a gzip body, got %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil || string(decoded) != body {
+		t.Errorf("Expected the body back, got %d bytes, %v", len(decoded), err)
+	}
+}
+
+// TestCompress_PrefersBrotli verifies that brotli is chosen on ties and that weights are honored
+func TestCompress_PrefersBrotli(t *testing.T) {
+	body := strings.Repeat("a", 2048)
+	r := createCompressTestRouter(body)
+
+	w := compressRequest(r, "/json", "gzip, br")
+	if w.Header().Get("Content-Encoding") != "br" || w.Body.Len() >= 2048 {
+		t.Errorf("Expected a smaller brotli body, got %q (%d bytes)", w.Header().Get("Content-Encoding"), w.Body.Len())
+	}
+	decoded, err := io.ReadAll(brotli.NewReader(w.Body))
+	if err != nil || string(decoded) != body {
+		t.Errorf("Expected the body back, got %d bytes, %v", len(decoded), err)
+	}
+	if w := compressRequest(r, "/json", "br;q=0.5, gzip"); w.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Expected gzip for its higher weight, got %q", w.Header().Get("Content-Encoding"))
+	}
+}
+
+// TestCompress_Skipped verifies the responses sent as they are
+func TestCompress_Skipped(t *testing.T) {
+	large := strings.Repeat("data: review\n\n", 200)
+
+	cases := []struct {
+		name           string
+		body           string
+		path           string
+		acceptEncoding string
+	}{
+		{"small body", "{}", "/json", "gzip"},
+		{"no Accept-Encoding", large, "/json", ""},
+		{"identity only", large, "/json", "identity, gzip;q=0"},
+		{"event stream", large, "/stream", "gzip, br"},
+		{"not modified", "", "/not-modified", "gzip"},
+	}
+	for _, tc := range cases {
+		w := compressRequest(createCompressTestRouter(tc.body), tc.path, tc.acceptEncoding)
+		if w.Header().Get("Content-Encoding") != "" {
+			t.Errorf("%s: expected no encoding, got %q", tc.name, w.Header().Get("Content-Encoding"))
+		}
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: expected the body as it is, got %d bytes", tc.name, w.Body.Len())
+		}
+	}
+}
+
+// TestNegotiateEncoding verifies the coding picked from Accept-Encoding headers
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                       "",
+		"gzip":                   "gzip",
+		"br":                     "br",
+		"gzip, br":               "br",
+		"gzip;q=1.0, br;q=0.8":   "gzip",
+		"*":                      "br",
+		"*;q=0.5, gzip":          "gzip",
+		"br;q=0, gzip;q=0":       "",
+		"deflate, identity":      "",
+		"GZIP;q=0.9, br;q=bogus": "gzip",
+	}
+	for acceptEncoding, expected := range cases {
+		if encoding := negotiateEncoding(acceptEncoding); encoding != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, acceptEncoding, encoding)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS lets browsers call the API from the configured origins, checked on every request so reloads apply
+func CORS(appService *app.App) gin.HandlerFunc {
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOriginFunc = appService.AllowsOrigin
+	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization", "X-API-Key", "If-None-Match", "If-Modified-Since")
+	corsConfig.ExposeHeaders = []string{"Deprecation", "Link", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "ETag"}
+	return cors.New(corsConfig)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks the routes of an old API version: responses get the RFC 9745 Deprecation header with since,
+// and a Link to the same path under successorPrefix. Errors keep the {"error": message} body of that version.
+func Deprecated(since time.Time, successorPrefix string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Link", "<"+successorPrefix+c.Request.URL.Path+`>; rel="successor-version"`)
+		problem.UseLegacyFormat(c)
+		c.Next()
+	}
+}
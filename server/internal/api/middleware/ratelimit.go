@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// sweepInterval is how often buckets refilled to the full burst are dropped, they are the same as new ones
+const sweepInterval = time.Minute
+
+// RateLimiter keeps a token bucket per client
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     func() config.RateLimit // read on every request, so reloaded limits apply right away
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewRateLimiter creates a limiter allowing each client the Burst requests at once of the current limit,
+// refilled at its PerSecond
+func NewRateLimiter(limit func() config.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// take spends a token of client under limit. Returns whether there was one, the whole tokens left, how long
+// until the next one when there was none, and how long until the bucket is full again.
+func (l *RateLimiter) take(client string, limit config.RateLimit) (allowed bool, remaining int, retryAfter, reset time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now, limit)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		l.buckets[client] = b
+	}
+	b.tokens = refilled(b, now, limit)
+	b.updatedAt = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = true
+	} else {
+		retryAfter = timeToRefill(1-b.tokens, limit)
+	}
+	return allowed, int(b.tokens), retryAfter, timeToRefill(float64(limit.Burst)-b.tokens, limit)
+}
+
+// refilled returns the tokens of b at now, capped to the burst of limit when it was lowered
+func refilled(b *bucket, now time.Time, limit config.RateLimit) float64 {
+	elapsed := max(now.Sub(b.updatedAt).Seconds(), 0)
+	return math.Min(float64(limit.Burst), b.tokens+elapsed*limit.PerSecond)
+}
+
+func timeToRefill(tokens float64, limit config.RateLimit) time.Duration {
+	return time.Duration(tokens / limit.PerSecond * float64(time.Second))
+}
+
+// sweep drops the full buckets once per sweepInterval, so idle clients don't pile up. Callers must hold l.mu.
+func (l *RateLimiter) sweep(now time.Time, limit config.RateLimit) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for client, b := range l.buckets {
+		if refilled(b, now, limit) >= float64(limit.Burst) {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// RateLimit answers 429 to clients that ran out of requests, identified by their API key, or their IP
+// when auth is disabled. Only requests for which when returns true are counted, every one when it is nil.
+// Must run after RequireRole. A limiter with no PerSecond lets every request through.
+func RateLimit(limiter *RateLimiter, when func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := limiter.limit()
+		if limit.PerSecond <= 0 || (when != nil && !when(c)) {
+			c.Next()
+			return
+		}
+
+		allowed, remaining, retryAfter, reset := limiter.take(rateLimitClient(c), limit)
+		window := int(math.Ceil(float64(limit.Burst) / limit.PerSecond))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, window))
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+
+		if !allowed {
+			seconds := ceilSeconds(retryAfter)
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			problem.Write(c, http.StatusTooManyRequests, problem.CodeRateLimited, fmt.Sprintf("Too many requests, retry in %d seconds", seconds))
+			return
+		}
+		c.Next()
+	}
+}
+
+func rateLimitClient(c *gin.Context) string {
+	if key, ok := APIKey(c); ok {
+		return "key:" + key.ID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
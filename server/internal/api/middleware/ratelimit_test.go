@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// createRateLimitTestRouter limits every request with standard and searches (q set) with expensive,
+// both driven by the same fake clock
+func createRateLimitTestRouter(standard, expensive config.RateLimit) (*gin.Engine, *fakeClock, *RateLimiter) {
+	clock := &fakeClock{now: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}
+	standardLimiter := NewRateLimiter(func() config.RateLimit { return standard })
+	standardLimiter.now = clock.Now
+	expensiveLimiter := NewRateLimiter(func() config.RateLimit { return expensive })
+	expensiveLimiter.now = clock.Now
+
+	isSearch := func(c *gin.Context) bool { return c.Query("q") != "" }
+	r := gin.New()
+	r.GET("/reviews", RateLimit(standardLimiter, nil), RateLimit(expensiveLimiter, isSearch), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r, clock, standardLimiter
+}
+
+func doRateLimitRequest(r *gin.Engine, url, clientIP string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.RemoteAddr = clientIP + ":51000"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestRateLimit_BurstThenRefill verifies that a client gets its burst, then 429 until tokens are refilled
+func TestRateLimit_BurstThenRefill(t *testing.T) {
+	r, clock, _ := createRateLimitTestRouter(config.RateLimit{PerSecond: 0.5, Burst: 3}, config.RateLimit{})
+
+	for i := range 3 {
+		w := doRateLimitRequest(r, "/reviews", "10.0.0.1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200, got %d", i+1, w.Code)
+		}
+		if remaining := w.Header().Get("RateLimit-Remaining"); remaining != strconv.Itoa(2-i) {
+			t.Errorf("Request %d: expected %d remaining, got %s", i+1, 2-i, remaining)
+		}
+	}
+
+	w := doRateLimitRequest(r, "/reviews", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 after the burst, got %d", w.Code)
+	}
+	headers := map[string]string{
+		"Retry-After":         "2", // a token every 2 seconds
+		"RateLimit-Limit":     "3",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "6", // 3 tokens to refill
+		"RateLimit-Policy":    "3;w=6",
+	}
+	for header, expected := range headers {
+		if got := w.Header().Get(header); got != expected {
+			t.Errorf("Expected %s %s, got %q", header, expected, got)
+		}
+	}
+
+	clock.Advance(1 * time.Second)
+	if w := doRateLimitRequest(r, "/reviews", "10.0.0.1"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected status 429 retrying in 1 second, got %d %s", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	clock.Advance(1 * time.Second)
+	if w := doRateLimitRequest(r, "/reviews", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 once a token is refilled, got %d", w.Code)
+	}
+
+	if w := doRateLimitRequest(r, "/reviews", "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("Expected other clients to have their own bucket, got %d", w.Code)
+	}
+}
+
+// TestRateLimit_ExpensiveRequests verifies that expensive requests count against both limits
+func TestRateLimit_ExpensiveRequests(t *testing.T) {
+	r, clock, _ := createRateLimitTestRouter(config.RateLimit{PerSecond: 10, Burst: 10}, config.RateLimit{PerSecond: 1, Burst: 2})
+
+	for i := range 2 {
+		if w := doRateLimitRequest(r, "/reviews?q=crash", "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("Search %d: expected status 200, got %d", i+1, w.Code)
+		}
+	}
+	w := doRateLimitRequest(r, "/reviews?q=crash", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("RateLimit-Limit") != "2" {
+		t.Errorf("Expected the search limit to apply, got %d with limit %s", w.Code, w.Header().Get("RateLimit-Limit"))
+	}
+
+	if w := doRateLimitRequest(r, "/reviews", "10.0.0.1"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != "6" {
+		t.Errorf("Expected plain listings to be allowed, with every request counted, got %d with %s remaining", w.Code, w.Header().Get("RateLimit-Remaining"))
+	}
+
+	clock.Advance(time.Second)
+	if w := doRateLimitRequest(r, "/reviews?q=crash", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("Expected a search to be allowed after a second, got %d", w.Code)
+	}
+}
+
+// TestRateLimit_AppliesChangedLimits verifies that a changed limit applies to the next request, lowered bursts included
+func TestRateLimit_AppliesChangedLimits(t *testing.T) {
+	limit := config.RateLimit{PerSecond: 1, Burst: 5}
+	limiter := NewRateLimiter(func() config.RateLimit { return limit })
+	limiter.now = (&fakeClock{now: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}).Now
+	r := gin.New()
+	r.GET("/reviews", RateLimit(limiter, nil), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	if w := doRateLimitRequest(r, "/reviews", "10.0.0.1"); w.Header().Get("RateLimit-Remaining") != "4" {
+		t.Fatalf("Expected 4 remaining, got %s", w.Header().Get("RateLimit-Remaining"))
+	}
+
+	limit = config.RateLimit{PerSecond: 1, Burst: 2}
+	w := doRateLimitRequest(r, "/reviews", "10.0.0.1")
+	if w.Header().Get("RateLimit-Limit") != "2" || w.Header().Get("RateLimit-Remaining") != "1" {
+		t.Errorf("Expected the lowered burst to cap the bucket, got limit %s with %s remaining", w.Header().Get("RateLimit-Limit"), w.Header().Get("RateLimit-Remaining"))
+	}
+
+	limit = config.RateLimit{}
+	if w := doRateLimitRequest(r, "/reviews", "10.0.0.1"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("Expected disabling the limit to let requests through, got %d with limit %q", w.Code, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+// TestRateLimit_Disabled verifies that a limit without rate lets every request through without headers
+func TestRateLimit_Disabled(t *testing.T) {
+	r, _, _ := createRateLimitTestRouter(config.RateLimit{}, config.RateLimit{})
+
+	for range 50 {
+		if w := doRateLimitRequest(r, "/reviews?q=crash", "10.0.0.1"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("Expected no limit, got %d with limit %q", w.Code, w.Header().Get("RateLimit-Limit"))
+		}
+	}
+}
+
+// TestRateLimiter_SweepsIdleClients verifies that buckets of idle clients are dropped once refilled
+func TestRateLimiter_SweepsIdleClients(t *testing.T) {
+	r, clock, limiter := createRateLimitTestRouter(config.RateLimit{PerSecond: 1, Burst: 5}, config.RateLimit{})
+
+	doRateLimitRequest(r, "/reviews", "10.0.0.1")
+	doRateLimitRequest(r, "/reviews", "10.0.0.2")
+	if len(limiter.buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(limiter.buckets))
+	}
+
+	clock.Advance(sweepInterval)
+	doRateLimitRequest(r, "/reviews", "10.0.0.3")
+	if len(limiter.buckets) != 1 {
+		t.Errorf("Expected only the bucket of the new client to be kept, got %d", len(limiter.buckets))
+	}
+}
@@ -0,0 +1,9 @@
+// Package openapi holds the OpenAPI 3 document of the HTTP API, checked against the handlers by the api tests
+package openapi
+
+import _ "embed"
+
+// Spec is the OpenAPI document served at GET /openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
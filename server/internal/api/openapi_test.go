@@ -0,0 +1,420 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/openapi"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// TestMain suppresses logs and sets Gin to test mode during all tests
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard // the router logs every request
+
+	code := m.Run()
+
+	log.SetOutput(os.Stderr)
+	os.Exit(code)
+}
+
+// spec is the decoded OpenAPI document
+type spec map[string]any
+
+func loadSpec(t *testing.T) spec {
+	var document spec
+	if err := json.Unmarshal(openapi.Spec, &document); err != nil {
+		t.Fatalf("Failed to decode the OpenAPI document: %v", err)
+	}
+	return document
+}
+
+// operation returns the operation of a method and path template, nil if it is not documented
+func (s spec) operation(method, path string) map[string]any {
+	pathItem, _ := s["paths"].(map[string]any)[path].(map[string]any)
+	operation, _ := pathItem[strings.ToLower(method)].(map[string]any)
+	return operation
+}
+
+// resolve follows a local $ref, like #/components/responses/NotFound
+func (s spec) resolve(node map[string]any) map[string]any {
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node
+	}
+	var current any = map[string]any(s)
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		current = current.(map[string]any)[part]
+	}
+	return current.(map[string]any)
+}
+
+// validate checks value against a schema, returning the mismatches. It covers the subset of JSON Schema
+// used by the document, and objects listing properties reject unlisted ones unless additionalProperties allows them,
+// so fields added to responses must be documented.
+func (s spec) validate(schema map[string]any, value any, at string) []string {
+	schema = s.resolve(schema)
+	if value == nil {
+		if schema["nullable"] == true || schema["type"] == nil { // no type means any value
+			return nil
+		}
+		return []string{at + ": null is not allowed"}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", at, value, enum)}
+	}
+
+	var problems []string
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []string{at + ": expected an object"}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %s", at, name))
+			}
+		}
+		for name, propertyValue := range object {
+			if propertySchema, ok := properties[name].(map[string]any); ok {
+				problems = append(problems, s.validate(propertySchema, propertyValue, at+"."+name)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+				problems = append(problems, s.validate(additional, propertyValue, at+"."+name)...)
+			} else if properties != nil {
+				problems = append(problems, fmt.Sprintf("%s: undocumented property %s", at, name))
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return []string{at + ": expected an array"}
+		}
+		for i, item := range array {
+			problems = append(problems, s.validate(schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return []string{at + ": expected a string"}
+		}
+		layouts := map[string]string{"date-time": time.RFC3339, "date": time.DateOnly}
+		if layout, ok := layouts[fmt.Sprint(schema["format"])]; ok {
+			if _, err := time.Parse(layout, text); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a %s", at, text, schema["format"]))
+			}
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return []string{fmt.Sprintf("%s: expected an integer, got %v", at, value)}
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return []string{at + ": expected a number"}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{at + ": expected a boolean"}
+		}
+	}
+	return problems
+}
+
+// createSpecTestRouter serves the real router with auth and rate limits disabled,
+// over reviews covering the optional fields: tags, languages and spam flags
+func createSpecTestRouter(t *testing.T) *gin.Engine {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "review-1", Title: "Charged twice", Content: "I was charged twice for the subscription, please refund me", Author: "ana", Rating: 1, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Free coins", Content: "Get free coins at coins.example.com", Author: "bot", Rating: 5, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-3", Title: "Muy buena", Content: "La aplicación es muy buena y rápida", Author: "luis", Rating: 4, UpdatedAt: now.Add(-30 * time.Hour)},
+	}
+	cfg := &config.Config{
+		AppID:           "test-app-id",
+		StorageFilePath: filepath.Join(t.TempDir(), "reviews.json"),
+		TagRules: []models.TagRule{
+			{ID: "config-1", Tag: "billing", Type: models.TagRuleKeyword, Pattern: "refund, charged", Source: models.TagRuleSourceConfig},
+		},
+	}
+	return NewRouter(app.New(&repositories.AppReviewsRepository{Reviews: reviews}, cfg))
+}
+
+// specCase is a request to the real router and the documented operation it exercises.
+// {name} placeholders in url are replaced by the id of the response of the case with capture name.
+type specCase struct {
+	method, path, url string
+	body              string
+	headers           map[string]string
+	status            int
+	capture           string
+}
+
+// TestOpenAPI_ResponsesMatchHandlers verifies that the responses of every documented operation match the document
+func TestOpenAPI_ResponsesMatchHandlers(t *testing.T) {
+	document := loadSpec(t)
+	r := createSpecTestRouter(t)
+
+	cases := []specCase{
+		{method: "GET", path: "/health", url: "/health", status: 200},
+		{method: "GET", path: "/openapi.json", url: "/openapi.json", status: 200},
+		{method: "GET", path: "/v1/reviews", url: "/v1/reviews?sort=sentiment_asc", status: 200},
+		{method: "GET", path: "/v1/reviews", url: "/v1/reviews?format=csv", status: 200},
+		{method: "GET", path: "/v1/reviews", url: "/v1/reviews?format=ndjson", status: 200},
+		{method: "GET", path: "/v1/reviews", url: "/v1/reviews?rating=9", status: 400},
+		{method: "GET", path: "/v1/reviews", url: "/v1/reviews", headers: map[string]string{"If-Modified-Since": "Sat, 01 Jan 2100 00:00:00 GMT"}, status: 304},
+		{method: "GET", path: "/v1/reviews/stream", url: "/v1/reviews/stream?rating=1", status: 200},
+		{method: "GET", path: "/v1/ws", url: "/v1/ws?rating=9", status: 400},
+		{method: "GET", path: "/v1/reviews/stats", url: "/v1/reviews/stats?includeFlagged=true", status: 200},
+		{method: "GET", path: "/v1/reviews/stats", url: "/v1/reviews/stats?appId=other", status: 400},
+		{method: "GET", path: "/v1/reviews/stats", url: "/v1/reviews/stats", headers: map[string]string{"If-Modified-Since": "Sat, 01 Jan 2100 00:00:00 GMT"}, status: 304},
+		{method: "GET", path: "/v1/reviews/{id}/triage", url: "/v1/reviews/review-1/triage", status: 200},
+		{method: "GET", path: "/v1/reviews/{id}/triage", url: "/v1/reviews/missing/triage", status: 404},
+		{method: "PATCH", path: "/v1/reviews/{id}/triage", url: "/v1/reviews/review-1/triage", body: `{"status": "acknowledged", "assignee": "ana", "labels": ["billing"]}`, status: 200},
+		{method: "PATCH", path: "/v1/reviews/{id}/triage", url: "/v1/reviews/review-1/triage", body: `{"status": "done"}`, status: 400},
+		{method: "POST", path: "/v1/reviews/{id}/notes", url: "/v1/reviews/review-1/notes", body: `{"author": "ana", "text": "Refund sent"}`, status: 201},
+		{method: "POST", path: "/v1/views", url: "/v1/views", body: `{"name": "1-star billing", "filter": {"hours": 24, "rating": 1, "tag": "billing"}}`, status: 201, capture: "view"},
+		{method: "POST", path: "/v1/views", url: "/v1/views", body: `{"name": "Too long", "filter": {"hours": 200}}`, status: 400},
+		{method: "GET", path: "/v1/views", url: "/v1/views", status: 200},
+		{method: "GET", path: "/v1/views/{id}", url: "/v1/views/{view}", status: 200},
+		{method: "GET", path: "/v1/views/{id}", url: "/v1/views/missing", status: 404},
+		{method: "PUT", path: "/v1/views/{id}", url: "/v1/views/{view}", body: `{"name": "Billing", "filter": {"tag": "billing", "sort": "oldest"}}`, status: 200},
+		{method: "GET", path: "/v1/views/{id}/reviews", url: "/v1/views/{view}/reviews", status: 200},
+		{method: "GET", path: "/v1/views/{id}/reviews", url: "/v1/views/{view}/reviews", headers: map[string]string{"If-Modified-Since": "Sat, 01 Jan 2100 00:00:00 GMT"}, status: 304},
+		{method: "DELETE", path: "/v1/views/{id}", url: "/v1/views/{view}", status: 204},
+		{method: "GET", path: "/v1/insights/keywords", url: "/v1/insights/keywords?n=1&minCount=1", status: 200},
+		{method: "GET", path: "/v1/insights/keywords", url: "/v1/insights/keywords?bucket=month", status: 400},
+		{method: "GET", path: "/v1/feeds/reviews.atom", url: "/v1/feeds/reviews.atom?rating=1", status: 200},
+		{method: "GET", path: "/v1/feeds/reviews.atom", url: "/v1/feeds/reviews.atom", headers: map[string]string{"If-Modified-Since": "Sat, 01 Jan 2100 00:00:00 GMT"}, status: 304},
+		{method: "GET", path: "/v1/feeds/reviews.atom", url: "/v1/feeds/reviews.atom?sort=rating", status: 400},
+		{method: "GET", path: "/v1/feeds/reviews.rss", url: "/v1/feeds/reviews.rss?sentiment=negative", status: 200},
+		{method: "GET", path: "/v1/feeds/reviews.rss", url: "/v1/feeds/reviews.rss", headers: map[string]string{"If-Modified-Since": "Sat, 01 Jan 2100 00:00:00 GMT"}, status: 304},
+		{method: "GET", path: "/v1/feeds/reviews.rss", url: "/v1/feeds/reviews.rss?hours=0", status: 400},
+		{method: "GET", path: "/v1/graphql", url: "/v1/graphql?query=%7B%20apps%20review(id%3A%20%22review-2%22)%20%7B%20id%20flags%20%7B%20reason%20%7D%20%7D%20%7D", status: 200},
+		{method: "GET", path: "/v1/graphql", url: "/v1/graphql", status: 400},
+		{method: "POST", path: "/v1/graphql", url: "/v1/graphql", body: `{"query":"query Q($n: Int) { reviews(first: $n) { totalCount nodes { id } } }","variables":{"n":1}}`, status: 200},
+		{method: "POST", path: "/v1/graphql", url: "/v1/graphql", body: `{"query":"{ reviews(filter: {rating: 9}) { totalCount } }"}`, status: 200},
+		{method: "POST", path: "/v1/graphql", url: "/v1/graphql", body: `{"variables":{}}`, status: 400},
+		{method: "GET", path: "/v1/graphql/schema", url: "/v1/graphql/schema", status: 200},
+		{method: "POST", path: "/v1/admin/webhooks", url: "/v1/admin/webhooks", body: `{"url": "https://hooks.example.com/reviews", "ratings": [1, 2]}`, status: 201, capture: "webhook"},
+		{method: "GET", path: "/v1/admin/webhooks", url: "/v1/admin/webhooks", status: 200},
+		{method: "GET", path: "/v1/admin/webhooks/outbox", url: "/v1/admin/webhooks/outbox", status: 200},
+		{method: "GET", path: "/v1/admin/webhooks/dead-letters", url: "/v1/admin/webhooks/dead-letters", status: 200},
+		{method: "POST", path: "/v1/admin/webhooks/dead-letters/{id}/retry", url: "/v1/admin/webhooks/dead-letters/missing/retry", status: 404},
+		{method: "DELETE", path: "/v1/admin/webhooks/{id}", url: "/v1/admin/webhooks/{webhook}", status: 204},
+		{method: "POST", path: "/v1/admin/alert-rules", url: "/v1/admin/alert-rules", body: `{"name": "Low rating", "type": "average_rating_below", "windowHours": 24, "threshold": 3}`, status: 201, capture: "alert"},
+		{method: "GET", path: "/v1/admin/alert-rules", url: "/v1/admin/alert-rules", status: 200},
+		{method: "DELETE", path: "/v1/admin/alert-rules/{id}", url: "/v1/admin/alert-rules/{alert}", status: 204},
+		{method: "POST", path: "/v1/admin/tag-rules", url: "/v1/admin/tag-rules", body: `{"tag": "speed", "type": "keyword", "pattern": "rápida, fast"}`, status: 201, capture: "tag"},
+		{method: "GET", path: "/v1/admin/tag-rules", url: "/v1/admin/tag-rules", status: 200},
+		{method: "DELETE", path: "/v1/admin/tag-rules/{id}", url: "/v1/admin/tag-rules/config-1", status: 409},
+		{method: "DELETE", path: "/v1/admin/tag-rules/{id}", url: "/v1/admin/tag-rules/{tag}", status: 204},
+		{method: "GET", path: "/v1/admin/flags", url: "/v1/admin/flags", status: 200},
+		{method: "DELETE", path: "/v1/admin/flags/{id}", url: "/v1/admin/flags/review-2", status: 204},
+		{method: "DELETE", path: "/v1/admin/flags/{id}", url: "/v1/admin/flags/review-2", status: 404},
+		{method: "POST", path: "/v1/admin/api-keys", url: "/v1/admin/api-keys", body: `{"name": "dashboard", "role": "read-only"}`, status: 201, capture: "key"},
+		{method: "POST", path: "/v1/admin/api-keys", url: "/v1/admin/api-keys", body: `{"name": "dashboard", "role": "owner"}`, status: 400},
+		{method: "GET", path: "/v1/admin/api-keys", url: "/v1/admin/api-keys", status: 200},
+		{method: "DELETE", path: "/v1/admin/api-keys/{id}", url: "/v1/admin/api-keys/{key}", status: 204},
+	}
+
+	captured := map[string]string{}
+	exercised := map[string]bool{}
+	for _, tc := range cases {
+		url := tc.url
+		for name, id := range captured {
+			url = strings.ReplaceAll(url, "{"+name+"}", id)
+		}
+		name := tc.method + " " + url
+
+		operation := document.operation(tc.method, tc.path)
+		if operation == nil {
+			t.Errorf("%s: %s %s is not documented", name, tc.method, tc.path)
+			continue
+		}
+		if tc.body != "" && tc.status < 300 { // invalid bodies are sent on purpose by the error cases
+			requestSchema := operation["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+			var requestBody any
+			json.Unmarshal([]byte(tc.body), &requestBody)
+			for _, problem := range document.validate(requestSchema, requestBody, "request") {
+				t.Errorf("%s: %s", name, problem)
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		if tc.path == "/v1/reviews/stream" {
+			cancel() // the stream answers, then ends with the request
+		}
+		req := httptest.NewRequest(tc.method, url, strings.NewReader(tc.body)).WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range tc.headers {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		cancel()
+
+		if w.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d: %s", name, tc.status, w.Code, w.Body.String())
+			continue
+		}
+		response, ok := operation["responses"].(map[string]any)[fmt.Sprint(w.Code)].(map[string]any)
+		if !ok {
+			t.Errorf("%s: status %d is not documented", name, w.Code)
+			continue
+		}
+		exercised[tc.method+" "+tc.path] = true
+
+		content, _ := document.resolve(response)["content"].(map[string]any)
+		if len(content) == 0 {
+			if w.Body.Len() > 0 {
+				t.Errorf("%s: expected no body, got %s", name, w.Body.String())
+			}
+			continue
+		}
+		mediaType, _, _ := strings.Cut(w.Header().Get("Content-Type"), ";")
+		media, ok := content[mediaType].(map[string]any)
+		if !ok {
+			t.Errorf("%s: content type %s is not documented", name, mediaType)
+			continue
+		}
+		if mediaType != "application/json" && mediaType != "application/problem+json" {
+			continue
+		}
+
+		var body any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: invalid JSON body: %v", name, err)
+			continue
+		}
+		for _, problem := range document.validate(media["schema"].(map[string]any), body, "response") {
+			t.Errorf("%s: %s", name, problem)
+		}
+		if tc.capture != "" {
+			captured[tc.capture], _ = body.(map[string]any)["id"].(string)
+		}
+	}
+
+	for path, pathItem := range document["paths"].(map[string]any) {
+		for method := range pathItem.(map[string]any) {
+			if operation := strings.ToUpper(method) + " " + path; !exercised[operation] {
+				t.Errorf("%s has no case, add one to keep it checked", operation)
+			}
+		}
+	}
+}
+
+// TestOpenAPI_DocumentsEveryRoute verifies that the routes of the router and the paths of the document are the same,
+// the deprecated unversioned routes being documented by their /v1 counterpart
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	document := loadSpec(t)
+	r := createSpecTestRouter(t)
+
+	pathParam := regexp.MustCompile(`:(\w+)`)
+	routes := map[string]bool{}
+	for _, route := range r.Routes() {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		routes[route.Method+" "+path] = true
+		if !strings.HasPrefix(path, "/v1/") && document.operation(route.Method, path) == nil {
+			path = "/v1" + path
+		}
+		if document.operation(route.Method, path) == nil {
+			t.Errorf("%s %s is not documented", route.Method, path)
+		}
+	}
+
+	for path, pathItem := range document["paths"].(map[string]any) {
+		for method := range pathItem.(map[string]any) {
+			if !routes[strings.ToUpper(method)+" "+path] {
+				t.Errorf("%s %s is documented but not routed", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// TestOpenAPI_ProtectedErrorResponses verifies the documented auth and rate limit errors of protected routes
+func TestOpenAPI_ProtectedErrorResponses(t *testing.T) {
+	document := loadSpec(t)
+	cfg := &config.Config{
+		AppID:           "test-app-id",
+		StorageFilePath: filepath.Join(t.TempDir(), "reviews.json"),
+		AuthEnabled:     true,
+		RateLimit:       config.RateLimit{PerSecond: 1, Burst: 1},
+	}
+	appService := app.New(&repositories.AppReviewsRepository{}, cfg)
+	_, key, _ := appService.APIKeys().Create("dashboard", models.RoleReadOnly)
+	r := NewRouter(appService)
+
+	cases := []struct {
+		url     string
+		headers map[string]string
+		status  int
+		header  string // documented header the response must carry
+	}{
+		{"/v1/admin/flags", nil, http.StatusUnauthorized, "WWW-Authenticate"},
+		{"/v1/admin/flags", map[string]string{"X-API-Key": key}, http.StatusForbidden, ""},
+		{"/v1/reviews", map[string]string{"X-API-Key": key}, http.StatusOK, ""},
+		{"/v1/reviews", map[string]string{"X-API-Key": key}, http.StatusTooManyRequests, "Retry-After"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+		for name, value := range tc.headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.url, tc.status, w.Code)
+			continue
+		}
+		if w.Code == http.StatusOK {
+			continue
+		}
+
+		operation := document.operation(http.MethodGet, tc.url)
+		if operation == nil {
+			t.Errorf("GET %s is not documented", tc.url)
+			continue
+		}
+		response, ok := operation["responses"].(map[string]any)[fmt.Sprint(w.Code)].(map[string]any)
+		if !ok {
+			t.Errorf("%s: status %d is not documented", tc.url, w.Code)
+			continue
+		}
+		response = document.resolve(response)
+		if tc.header != "" {
+			if _, documented := response["headers"].(map[string]any)[tc.header]; !documented || w.Header().Get(tc.header) == "" {
+				t.Errorf("%s: expected the documented %s header", tc.url, tc.header)
+			}
+		}
+		var body any
+		json.Unmarshal(w.Body.Bytes(), &body)
+		schema := response["content"].(map[string]any)["application/problem+json"].(map[string]any)["schema"].(map[string]any)
+		for _, problem := range document.validate(schema, body, "response") {
+			t.Errorf("%s: %s", tc.url, problem)
+		}
+	}
+}
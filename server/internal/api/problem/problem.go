@@ -0,0 +1,138 @@
+// Package problem writes API errors as RFC 7807 problem details, with a machine-readable code
+// and the invalid fields of validation errors
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem details
+const ContentType = "application/problem+json"
+
+// typePrefix makes the problem type URI of a code
+const typePrefix = "urn:appstore-reviews:problem:"
+
+// Problem codes, one per kind of error
+const (
+	CodeInvalidBody  = "invalid_body"      // the body is not the expected JSON
+	CodeValidation   = "validation_failed" // parameters or body fields are invalid, listed in Errors when known
+	CodeUnauthorized = "unauthorized"      // missing, unknown or revoked API key
+	CodeForbidden    = "forbidden"         // API key role too low for the route
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"     // the request conflicts with the current state
+	CodeRateLimited  = "rate_limited" // retry after the Retry-After header
+	CodeInternal     = "internal_error"
+)
+
+// Field error codes
+const (
+	FieldInvalid    = "invalid"      // not a valid value, like a rating of 7
+	FieldOutOfRange = "out_of_range" // valid but outside the allowed range
+	FieldRequired   = "required"
+	FieldNotTracked = "not_tracked" // the app is not tracked
+)
+
+// Field locations
+const (
+	InQuery  = "query"
+	InHeader = "header"
+	InBody   = "body"
+)
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"` // path of the request
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is an invalid query parameter, header or body field
+type FieldError struct {
+	Field   string `json:"field"` // dotted path for nested body fields, like filter.rating
+	In      string `json:"in"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Query returns the error of an invalid query parameter
+func Query(field, code, message string) *FieldError {
+	return &FieldError{Field: field, In: InQuery, Code: code, Message: message}
+}
+
+// Body returns the error of an invalid body field
+func Body(field, code, message string) *FieldError {
+	return &FieldError{Field: field, In: InBody, Code: code, Message: message}
+}
+
+const legacyContextKey = "problem.legacy"
+
+// UseLegacyFormat makes the errors of the request be written as {"error": detail}, the format of the unversioned routes
+func UseLegacyFormat(c *gin.Context) {
+	c.Set(legacyContextKey, true)
+}
+
+// Write aborts the request with a problem
+func Write(c *gin.Context, status int, code, detail string, fieldErrors ...FieldError) {
+	if c.GetBool(legacyContextKey) {
+		c.AbortWithStatusJSON(status, gin.H{"error": detail})
+		return
+	}
+
+	body, err := json.Marshal(Problem{
+		Type:     typePrefix + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+		Errors:   fieldErrors,
+	})
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, ContentType, body)
+	c.Abort()
+}
+
+// Invalid aborts with a 400 validation problem, listing err when it is a *FieldError
+func Invalid(c *gin.Context, err error) {
+	var fieldError *FieldError
+	if errors.As(err, &fieldError) {
+		Write(c, http.StatusBadRequest, CodeValidation, fieldError.Message, *fieldError)
+		return
+	}
+	Write(c, http.StatusBadRequest, CodeValidation, err.Error())
+}
+
+// InvalidBody aborts with a 400 problem for a body that could not be decoded
+func InvalidBody(c *gin.Context) {
+	Write(c, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+}
+
+// NotFound aborts with a 404 problem
+func NotFound(c *gin.Context, detail string) {
+	Write(c, http.StatusNotFound, CodeNotFound, detail)
+}
+
+// Conflict aborts with a 409 problem
+func Conflict(c *gin.Context, detail string) {
+	Write(c, http.StatusConflict, CodeConflict, detail)
+}
+
+// Internal aborts with a 500 problem
+func Internal(c *gin.Context, detail string) {
+	Write(c, http.StatusInternalServerError, CodeInternal, detail)
+}
@@ -1,22 +1,108 @@
 package api
 
 import (
+	"log"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/handlers"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/middleware"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
-	"github.com/gin-contrib/cors"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
 	"github.com/gin-gonic/gin"
 )
 
 func NewRouter(appService *app.App) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger(), gin.Recovery())
-	// cors config
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowAllOrigins = true
-	r.Use(cors.New(corsConfig))
+	r.Use(middleware.CORS(appService))
+	r.Use(middleware.Compress(compressMinSize))
+	if err := r.SetTrustedProxies(appService.TrustedProxies()); err != nil {
+		log.Printf("API: invalid trusted proxies, trusting none: %v", err)
+		r.SetTrustedProxies(nil)
+	}
+
+	// every request counts against the standard limit, expensive ones against both limits,
+	// read on each request so reloads apply
+	limited := middleware.RateLimit(middleware.NewRateLimiter(func() config.RateLimit {
+		standard, _ := appService.RateLimits()
+		return standard
+	}), nil)
+	expensiveLimiter := middleware.NewRateLimiter(func() config.RateLimit {
+		_, expensive := appService.RateLimits()
+		return expensive
+	})
+	expensive := middleware.RateLimit(expensiveLimiter, nil)
+	searchOrExport := middleware.RateLimit(expensiveLimiter, handlers.IsSearchOrExport)
+
+	r.GET("/health", handlers.Health(appService))
+	r.GET("/openapi.json", handlers.OpenAPISpec())
+	r.NoRoute(handlers.RouteNotFound())
 
-	r.GET("/health", handlers.Health)
-	r.GET("/reviews", handlers.ListReviews(appService))
+	limits := routeLimits{limited: limited, expensive: expensive, searchOrExport: searchOrExport}
+	registerRoutes(r.Group("/v1"), appService, limits)
+	// the unversioned routes of before /v1, sharing its limits
+	registerRoutes(r.Group("", middleware.Deprecated(unversionedDeprecatedSince, "/v1")), appService, limits)
 
 	return r
 }
+
+// compressMinSize is the size from which response bodies are compressed, smaller ones gain too little
+const compressMinSize = 1024
+
+// unversionedDeprecatedSince is when the unversioned routes were deprecated in favor of /v1
+var unversionedDeprecatedSince = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// routeLimits are the rate limiting middlewares of the API routes
+type routeLimits struct {
+	limited        gin.HandlerFunc
+	expensive      gin.HandlerFunc
+	searchOrExport gin.HandlerFunc
+}
+
+// registerRoutes adds the API routes to group
+func registerRoutes(group *gin.RouterGroup, appService *app.App, limits routeLimits) {
+	// each group needs an API key with at least its role
+	read := group.Group("", middleware.RequireRole(appService, models.RoleReadOnly), limits.limited)
+	read.GET("/reviews", limits.searchOrExport, handlers.ListReviews(appService))
+	read.GET("/reviews/stream", handlers.StreamReviews(appService))
+	read.GET("/ws", handlers.ReviewsWebSocket(appService))
+	read.GET("/reviews/stats", limits.expensive, handlers.ReviewsStats(appService))
+	read.GET("/reviews/:id/triage", handlers.GetReviewTriage(appService))
+	read.GET("/views", handlers.ListViews(appService))
+	read.GET("/views/:id", handlers.GetView(appService))
+	read.GET("/views/:id/reviews", limits.searchOrExport, handlers.ListViewReviews(appService))
+	read.GET("/insights/keywords", limits.expensive, handlers.KeywordInsights(appService))
+	read.GET("/feeds/reviews.atom", limits.searchOrExport, handlers.ReviewsAtomFeed(appService))
+	read.GET("/feeds/reviews.rss", limits.searchOrExport, handlers.ReviewsRSSFeed(appService))
+	graphQL := handlers.GraphQL(appService)
+	read.GET("/graphql", limits.expensive, graphQL)
+	read.POST("/graphql", limits.expensive, graphQL)
+	read.GET("/graphql/schema", handlers.GraphQLSchema(appService))
+
+	triage := group.Group("", middleware.RequireRole(appService, models.RoleTriage), limits.limited)
+	triage.PATCH("/reviews/:id/triage", handlers.UpdateReviewTriage(appService))
+	triage.POST("/reviews/:id/notes", handlers.AddReviewNote(appService))
+	triage.POST("/views", handlers.CreateView(appService))
+	triage.PUT("/views/:id", handlers.UpdateView(appService))
+	triage.DELETE("/views/:id", handlers.DeleteView(appService))
+
+	admin := group.Group("/admin", middleware.RequireRole(appService, models.RoleAdmin), limits.limited)
+	admin.GET("/webhooks", handlers.ListWebhooks(appService))
+	admin.POST("/webhooks", handlers.CreateWebhook(appService))
+	admin.DELETE("/webhooks/:id", handlers.DeleteWebhook(appService))
+	admin.GET("/webhooks/outbox", handlers.ListWebhookOutbox(appService))
+	admin.GET("/webhooks/dead-letters", handlers.ListWebhookDeadLetters(appService))
+	admin.POST("/webhooks/dead-letters/:id/retry", handlers.RetryWebhookDeadLetter(appService))
+	admin.GET("/alert-rules", handlers.ListAlertRules(appService))
+	admin.POST("/alert-rules", handlers.CreateAlertRule(appService))
+	admin.DELETE("/alert-rules/:id", handlers.DeleteAlertRule(appService))
+	admin.GET("/tag-rules", handlers.ListTagRules(appService))
+	admin.POST("/tag-rules", handlers.CreateTagRule(appService))
+	admin.DELETE("/tag-rules/:id", handlers.DeleteTagRule(appService))
+	admin.GET("/flags", handlers.ListFlaggedReviews(appService))
+	admin.DELETE("/flags/:id", handlers.UnflagReview(appService))
+	admin.GET("/api-keys", handlers.ListAPIKeys(appService))
+	admin.POST("/api-keys", handlers.CreateAPIKey(appService))
+	admin.DELETE("/api-keys/:id", handlers.RevokeAPIKey(appService))
+}
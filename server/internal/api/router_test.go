@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/api/problem"
+)
+
+// TestRouter_DeprecatedAliases verifies that the unversioned routes answer like /v1 with deprecation headers
+func TestRouter_DeprecatedAliases(t *testing.T) {
+	r := createSpecTestRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reviews/stats", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if deprecation := w.Header().Get("Deprecation"); deprecation != "@1792368000" {
+		t.Errorf("Expected Deprecation @1792368000, got %q", deprecation)
+	}
+	if link := w.Header().Get("Link"); link != `</v1/reviews/stats>; rel="successor-version"` {
+		t.Errorf("Expected a successor-version link to /v1/reviews/stats, got %q", link)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/reviews/stats", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if deprecation := w.Header().Get("Deprecation"); deprecation != "" {
+		t.Errorf("Expected no Deprecation header under /v1, got %q", deprecation)
+	}
+}
+
+// TestRouter_ErrorFormats verifies that /v1 answers problem details and the unversioned routes their old error body
+func TestRouter_ErrorFormats(t *testing.T) {
+	r := createSpecTestRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/reviews?rating=9", nil))
+	if contentType := w.Header().Get("Content-Type"); contentType != problem.ContentType {
+		t.Errorf("Expected content type %s, got %s", problem.ContentType, contentType)
+	}
+	var details problem.Problem
+	json.Unmarshal(w.Body.Bytes(), &details)
+	expectedError := problem.FieldError{Field: "rating", In: problem.InQuery, Code: problem.FieldInvalid, Message: "Invalid rating parameter"}
+	if details.Status != http.StatusBadRequest || details.Code != problem.CodeValidation || details.Instance != "/v1/reviews" {
+		t.Errorf("Expected a 400 validation_failed problem for /v1/reviews, got %+v", details)
+	}
+	if len(details.Errors) != 1 || details.Errors[0] != expectedError {
+		t.Errorf("Expected the field error %+v, got %+v", expectedError, details.Errors)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reviews?rating=9", nil))
+	var legacy map[string]any
+	json.Unmarshal(w.Body.Bytes(), &legacy)
+	if w.Code != http.StatusBadRequest || len(legacy) != 1 || legacy["error"] != "Invalid rating parameter" {
+		t.Errorf("Expected the old error body, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	body := strings.NewReader(`{"name": "Recent", "filter": {"hours": 200}}`)
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/views", body))
+	details = problem.Problem{}
+	json.Unmarshal(w.Body.Bytes(), &details)
+	if len(details.Errors) != 1 || details.Errors[0].Field != "filter.hours" || details.Errors[0].In != problem.InBody || details.Errors[0].Code != problem.FieldOutOfRange {
+		t.Errorf("Expected an out_of_range error for the body field filter.hours, got %+v", details.Errors)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/unknown", nil))
+	details = problem.Problem{}
+	json.Unmarshal(w.Body.Bytes(), &details)
+	if w.Code != http.StatusNotFound || details.Code != problem.CodeNotFound {
+		t.Errorf("Expected a 404 not_found problem for an unknown route, got %d %s", w.Code, w.Body.String())
+	}
+}
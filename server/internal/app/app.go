@@ -1,40 +1,376 @@
 package app
 
 import (
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/alerts"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/events"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/language"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/notifiers"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sentiment"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/spam"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/tagging"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/webhooks"
 )
 
 type AppServiceInterface interface {
-	ListLatestReviews(hours int, rating *int) []models.AppStoreReview
-	GetLatestReview() *models.AppStoreReview
-	AddReviews(reviews []models.AppStoreReview) (int, error)
+	ListLatestReviews(appID string, hours int, rating *int) []models.AppStoreReview
+	GetLatestReview(appID string) *models.AppStoreReview
+	AddReviews(appID string, reviews []models.AppStoreReview) ([]models.AppStoreReview, error)
 	GetAppID() string
+	TrackedAppIDs() []string
 }
 
 type App struct {
-	repo *repositories.AppReviewsRepository
-	cfg  *config.Config
+	mu       sync.RWMutex
+	repos    map[string]*repositories.AppReviewsRepository // one repository per tracked app
+	cfg      *config.Config
+	events   *events.Hub // publishes every review stored by AddReviews
+	webhooks *webhooks.Dispatcher
+	alerts   *alerts.Engine
+	tagRules *repositories.TagRulesRepository // rules created through the API
+	tagger   *tagging.Tagger                  // config and API rules, rebuilt when they change
+	spam     map[string]*spam.Detector        // one detector per tracked app, fed with every stored review
+	triage   *repositories.TriageRepository
+	views    *repositories.ViewsRepository
+	apiKeys  *repositories.APIKeysRepository
 }
 
+// New creates the app service with repo as the primary app repository.
+// The remaining tracked apps have their repositories loaded from storage.
 func New(repo *repositories.AppReviewsRepository, cfg *config.Config) *App {
-	return &App{repo: repo, cfg: cfg}
+	a := &App{
+		repos:    map[string]*repositories.AppReviewsRepository{},
+		spam:     map[string]*spam.Detector{},
+		events:   events.NewHub(500, 64),
+		webhooks: webhooks.New(repositories.LoadWebhooks(cfg.DataFilePath("webhooks.json"))),
+		tagRules: repositories.LoadTagRules(cfg.DataFilePath("tag-rules.json")),
+		triage:   repositories.LoadTriage(cfg.DataFilePath("triage.json")),
+		views:    repositories.LoadViews(cfg.DataFilePath("views.json")),
+		apiKeys:  repositories.LoadAPIKeys(cfg.DataFilePath("api-keys.json")),
+	}
+	a.events.AddBatchListener(a.webhooks.HandleBatch)
+	a.alerts = alerts.New(repositories.LoadAlertRules(cfg.DataFilePath("alert-rules.json")), a, notifiers.AlertSenderFromConfig(cfg))
+
+	a.cfg = cfg
+	a.tagger = a.buildTagger()
+	a.repos[cfg.AppID] = a.prepare(cfg.AppID, repo)
+	a.ApplyConfig(cfg)
+	return a
+}
+
+// ApplyConfig switches the app service to cfg, loading repositories of newly tracked apps
+// and dropping the ones no longer tracked. Stored reviews are tagged again when the config tag rules changed.
+func (a *App) ApplyConfig(cfg *config.Config) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	previous := a.cfg
+	a.cfg = cfg
+	if !reflect.DeepEqual(previous.TagRules, cfg.TagRules) {
+		log.Printf("APP: config tag rules changed")
+		a.tagger = a.buildTagger()
+		a.retagLocked()
+	}
+	if alertWebhookChanged(previous, cfg) {
+		log.Printf("APP: alert webhook changed, rebuilding the alert sender")
+		a.alerts.SetSender(notifiers.AlertSenderFromConfig(cfg))
+	}
+
+	tracked := cfg.TrackedAppIDs()
+	for _, appID := range tracked {
+		if _, ok := a.repos[appID]; !ok {
+			log.Printf("APP: tracking app %s", appID)
+			a.repos[appID] = a.prepare(appID, repositories.Load(cfg.StorageFilePathFor(appID)))
+		}
+	}
+	for appID := range a.repos {
+		if !slices.Contains(tracked, appID) {
+			log.Printf("APP: no longer tracking app %s", appID)
+			delete(a.repos, appID) // its storage file is kept, tracking it again reloads it
+			delete(a.spam, appID)
+		}
+	}
+}
+
+// alertWebhookChanged reports whether the Slack settings the alert sender is built from changed
+func alertWebhookChanged(previous, cfg *config.Config) bool {
+	return previous.SlackWebhookURL != cfg.SlackWebhookURL || previous.SlackRoutes[1] != cfg.SlackRoutes[1]
+}
+
+// prepare fills the fields added to reviews after they were stored, like their sentiment and language,
+// and applies the current tag rules, which may have changed while the reviews were not loaded.
+// The spam detector of the app is fed with the stored reviews, oldest first, flagging them again,
+// except the ones whose flags an admin cleared.
+func (a *App) prepare(appID string, repo *repositories.AppReviewsRepository) *repositories.AppReviewsRepository {
+	detector := spam.New()
+	flags := make(map[string][]models.ReviewFlag)
+	stored := repo.ListAll(repositories.ReviewFilter{})
+	for i := len(stored) - 1; i >= 0; i-- {
+		if checked := detector.Check(stored[i]); !stored[i].FlagsCleared {
+			flags[stored[i].ID] = checked
+		}
+	}
+	a.spam[appID] = detector
+
+	changed, err := repo.Backfill(func(review *models.AppStoreReview) bool {
+		scored := sentiment.AnnotateMissing(review)
+		detected := language.AnnotateMissing(review)
+		tagged := a.tagger.Apply(review)
+		flagged := !slices.Equal(review.Flags, flags[review.ID])
+		if flagged {
+			review.Flags = flags[review.ID]
+		}
+		return scored || detected || tagged || flagged
+	})
+	if err != nil {
+		log.Printf("APP: error saving backfilled reviews of app %s: %v", appID, err)
+	}
+	if changed > 0 {
+		log.Printf("APP: updated the sentiment, language, tags and flags of %d stored reviews of app %s", changed, appID)
+	}
+	return repo
+}
+
+// repo returns the repository of the given app, nil if the app is not tracked
+func (a *App) repo(appID string) *repositories.AppReviewsRepository {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.repos[appID]
+}
+
+func (a *App) ListLatestReviews(appID string, hours int, rating *int) []models.AppStoreReview {
+	repo := a.repo(appID)
+	if repo == nil {
+		return []models.AppStoreReview{}
+	}
+	return repo.ListLatest(hours, repositories.ReviewFilter{Rating: rating})
 }
 
-func (a *App) ListLatestReviews(hours int, rating *int) []models.AppStoreReview {
-	return a.repo.ListLatest(hours, repositories.ReviewFilter{Rating: rating})
+// QueryReviews returns the reviews of the last hours matching filter, triage conditions included, sorted by sortBy
+func (a *App) QueryReviews(appID string, hours int, filter repositories.ReviewFilter, sortBy string) []models.AppStoreReview {
+	repo := a.repo(appID)
+	if repo == nil {
+		return []models.AppStoreReview{}
+	}
+	reviews := repo.ListLatest(hours, filter)
+	if filter.HasTriage() {
+		reviews = slices.DeleteFunc(reviews, func(review models.AppStoreReview) bool { return !a.matchesTriage(appID, filter, review) })
+	}
+	if sortBy != models.SortNewest {
+		reviews.SortBy(sortBy)
+	}
+	return reviews
 }
 
-func (a *App) GetLatestReview() *models.AppStoreReview {
-	return a.repo.GetLatestReview()
+// StreamLatestReviews streams the reviews of the last hours matching filter, triage conditions included, to fn,
+// newest first, without building a slice. The repository lock is not held while fn runs, so fn may be slow.
+func (a *App) StreamLatestReviews(appID string, hours int, filter repositories.ReviewFilter, fn func(review models.AppStoreReview) error) error {
+	repo := a.repo(appID)
+	if repo == nil {
+		return nil
+	}
+	return repo.StreamLatest(hours, filter, func(review models.AppStoreReview) error {
+		if !a.matchesTriage(appID, filter, review) {
+			return nil
+		}
+		return fn(review)
+	})
 }
 
-// AddReviews adds new reviews to the repository and returns the number of reviews added
-func (a *App) AddReviews(reviews []models.AppStoreReview) (int, error) {
-	return a.repo.AddBatch(reviews)
+// Stats summarizes the stored reviews of an app, false if the app is not tracked.
+// Flagged reviews are left out unless includeFlagged is set.
+func (a *App) Stats(appID string, includeFlagged bool) (repositories.ReviewStats, bool) {
+	repo := a.repo(appID)
+	if repo == nil {
+		return repositories.ReviewStats{}, false
+	}
+	return repo.Stats(includeFlagged), true
 }
 
+// ReviewsVersion returns the data version of the reviews of an app, their triage included, and when it last
+// changed, false if the app is not tracked. With hours above 0 it also changes when a review leaves the window
+// of the last hours, so it identifies what QueryReviews returns for them.
+func (a *App) ReviewsVersion(appID string, hours int) (string, time.Time, bool) {
+	repo := a.repo(appID)
+	if repo == nil {
+		return "", time.Time{}, false
+	}
+
+	reviewsVersion, modified := repo.Version()
+	triageVersion, triageModified := a.triage.Version()
+	if triageModified.After(modified) {
+		modified = triageModified
+	}
+	var expired time.Time
+	if hours > 0 {
+		if expired = repo.LastExpired(hours); expired.After(modified) {
+			modified = expired
+		}
+	}
+	return fmt.Sprintf("%d.%d.%d", reviewsVersion, triageVersion, expired.Unix()), modified, true
+}
+
+// FlaggedReviews returns every stored review of an app flagged as spam, newest first
+func (a *App) FlaggedReviews(appID string) []models.AppStoreReview {
+	repo := a.repo(appID)
+	if repo == nil {
+		return []models.AppStoreReview{}
+	}
+	flagged := true
+	return repo.ListAll(repositories.ReviewFilter{Flagged: &flagged})
+}
+
+// UnflagReview clears the spam flags of a stored review of an app, for good.
+// Returns false if the review is not flagged or the app is not tracked
+func (a *App) UnflagReview(appID, reviewID string) (bool, error) {
+	repo := a.repo(appID)
+	if repo == nil {
+		return false, nil
+	}
+	return repo.ClearFlags(reviewID)
+}
+
+// GetReview returns a stored review of an app, nil if it is not stored or the app is not tracked
+func (a *App) GetReview(appID, reviewID string) *models.AppStoreReview {
+	repo := a.repo(appID)
+	if repo == nil {
+		return nil
+	}
+	return repo.GetReview(reviewID)
+}
+
+func (a *App) GetLatestReview(appID string) *models.AppStoreReview {
+	repo := a.repo(appID)
+	if repo == nil {
+		return nil
+	}
+	return repo.GetLatestReview()
+}
+
+// AddReviews adds new reviews to the app repository and returns the reviews that were added
+func (a *App) AddReviews(appID string, reviews []models.AppStoreReview) ([]models.AppStoreReview, error) {
+	added, err := a.addReviews(appID, reviews)
+	if err != nil || added == nil {
+		return nil, err
+	}
+
+	a.events.Publish(appID, added)
+	return added, nil
+}
+
+// addReviews scores, detects the language of, tags, checks for spam and stores reviews. The read lock keeps
+// tag rules from changing between tagging the reviews and storing them, so retagging can't miss them.
+func (a *App) addReviews(appID string, reviews []models.AppStoreReview) (models.AppStoreReviews, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	repo := a.repos[appID]
+	if repo == nil {
+		log.Printf("APP: discarding %d reviews of untracked app %s", len(reviews), appID)
+		return nil, nil
+	}
+
+	annotated := make(models.AppStoreReviews, len(reviews))
+	for i, review := range reviews {
+		sentiment.Annotate(&review)
+		language.Annotate(&review)
+		a.tagger.Apply(&review)
+		annotated[i] = review
+	}
+
+	// the detector flags the later of two duplicates, so it checks the oldest reviews first
+	annotated.SortBy(models.SortOldest)
+	for i := range annotated {
+		annotated[i].Flags = a.spam[appID].Check(annotated[i])
+	}
+
+	return repo.AddNewReviews(annotated)
+}
+
+// Webhooks returns the dispatcher of outgoing webhooks
+func (a *App) Webhooks() *webhooks.Dispatcher {
+	return a.webhooks
+}
+
+// Alerts returns the alert rules engine
+func (a *App) Alerts() *alerts.Engine {
+	return a.alerts
+}
+
+// Views returns the saved views
+func (a *App) Views() *repositories.ViewsRepository {
+	return a.views
+}
+
+// APIKeys returns the API keys
+func (a *App) APIKeys() *repositories.APIKeysRepository {
+	return a.apiKeys
+}
+
+// Events returns the hub publishing newly stored reviews
+func (a *App) Events() *events.Hub {
+	return a.events
+}
+
+// GetAppID returns the primary app ID
 func (a *App) GetAppID() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.cfg.AppID
 }
+
+// TrackedAppIDs returns the IDs of every tracked app, primary first
+func (a *App) TrackedAppIDs() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cfg.TrackedAppIDs()
+}
+
+// IsTracked reports whether the given app is tracked
+func (a *App) IsTracked(appID string) bool {
+	return a.repo(appID) != nil
+}
+
+// GetConfigVersion returns the version of the active config
+func (a *App) GetConfigVersion() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cfg.Version
+}
+
+// AuthEnabled reports whether requests need an API key
+func (a *App) AuthEnabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cfg.AuthEnabled
+}
+
+// AllowsOrigin reports whether browsers may call the API from origin
+func (a *App) AllowsOrigin(origin string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return slices.Contains(a.cfg.CORSAllowedOrigins, "*") || slices.Contains(a.cfg.CORSAllowedOrigins, origin)
+}
+
+// RateLimits returns the limits of every request and of expensive ones
+func (a *App) RateLimits() (config.RateLimit, config.RateLimit) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cfg.RateLimit, a.cfg.ExpensiveRateLimit
+}
+
+// TrustedProxies returns the proxies trusted to give the client IP, read at startup
+func (a *App) TrustedProxies() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cfg.TrustedProxies
+}
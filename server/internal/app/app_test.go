@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	// Suppress logs during testing
+	log.SetOutput(io.Discard)
+
+	// Run tests
+	code := m.Run()
+
+	// Restore log output
+	log.SetOutput(os.Stderr)
+
+	// Exit with the same code as the tests
+	os.Exit(code)
+}
+
+// TestApplyConfig_RebuildsAlertSender verifies that alerts go to the webhook of the reloaded config
+func TestApplyConfig_RebuildsAlertSender(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+	}))
+	t.Cleanup(server.Close)
+
+	reviews := models.AppStoreReviews{{ID: "review-1", Rating: 1, UpdatedAt: time.Now().UTC().Add(-time.Hour)}}
+	cfg := &config.Config{AppID: "test-app-id", StorageFilePath: filepath.Join(t.TempDir(), "reviews.json")}
+	a := New(&repositories.AppReviewsRepository{Reviews: reviews}, cfg)
+	if _, err := a.Alerts().AddRule(models.AlertRule{Type: models.AlertRuleAverageRatingBelow, WindowHours: 24, Threshold: 3}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reloaded := *cfg
+	reloaded.SlackWebhookURL = server.URL
+	a.ApplyConfig(&reloaded)
+	a.Alerts().Evaluate(context.Background())
+
+	if got := posts.Load(); got != 1 {
+		t.Errorf("Expected 1 alert on the reloaded webhook, got %d", got)
+	}
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/ids"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/tagging"
+)
+
+var (
+	// ErrInvalidTagRule is wrapped by the errors of AddTagRule caused by an invalid rule
+	ErrInvalidTagRule = errors.New("invalid tag rule")
+	// ErrConfigTagRule is returned when deleting a rule defined in the config file
+	ErrConfigTagRule = errors.New("tag rule is defined in the config file")
+)
+
+// TagRules returns the config tag rules followed by the ones created through the API
+func (a *App) TagRules() []models.TagRule {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return slices.Concat(a.cfg.TagRules, a.tagRules.List())
+}
+
+// AddTagRule validates and stores a rule, then tags the stored reviews again
+func (a *App) AddTagRule(rule models.TagRule) (models.TagRule, error) {
+	rule.Tag = strings.TrimSpace(rule.Tag)
+	if err := tagging.Validate(rule); err != nil {
+		return models.TagRule{}, fmt.Errorf("%w: %w", ErrInvalidTagRule, err)
+	}
+
+	now := time.Now().UTC()
+	rule.ID = ids.New()
+	rule.Source = models.TagRuleSourceAPI
+	rule.CreatedAt = &now
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.tagRules.Add(rule); err != nil {
+		return models.TagRule{}, fmt.Errorf("saving tag rule: %w", err)
+	}
+	a.tagger = a.buildTagger()
+	a.retagLocked()
+	return rule, nil
+}
+
+// DeleteTagRule deletes a rule created through the API and removes its tags from the stored reviews.
+// Returns false if the rule doesn't exist.
+func (a *App) DeleteTagRule(id string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if slices.ContainsFunc(a.cfg.TagRules, func(rule models.TagRule) bool { return rule.ID == id }) {
+		return false, ErrConfigTagRule
+	}
+
+	deleted, err := a.tagRules.Delete(id)
+	if !deleted {
+		return false, err
+	}
+	a.tagger = a.buildTagger()
+	a.retagLocked()
+	return true, err
+}
+
+// buildTagger compiles the config and API tag rules, logging the ones that don't compile.
+// Callers must hold a.mu or be the only goroutine with access to the app.
+func (a *App) buildTagger() *tagging.Tagger {
+	tagger, errs := tagging.New(slices.Concat(a.cfg.TagRules, a.tagRules.List()))
+	for _, err := range errs {
+		log.Printf("APP: skipping %v", err)
+	}
+	return tagger
+}
+
+// retagLocked applies the current tagger to the stored reviews of every tracked app. Callers must hold a.mu.
+func (a *App) retagLocked() {
+	for appID, repo := range a.repos {
+		changed, err := repo.Backfill(a.tagger.Apply)
+		if err != nil {
+			log.Printf("APP: error saving retagged reviews of app %s: %v", appID, err)
+		}
+		if changed > 0 {
+			log.Printf("APP: updated the tags of %d stored reviews of app %s", changed, appID)
+		}
+	}
+}
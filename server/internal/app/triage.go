@@ -0,0 +1,137 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/ids"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+const (
+	maxTriageLabels     = 20
+	maxTriageFieldRunes = 100 // of the assignee and each label
+	maxTriageNoteRunes  = 5000
+)
+
+var (
+	// ErrReviewNotFound is returned when triaging a review that is not stored
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrInvalidTriage is wrapped by the errors of UpdateTriage and AddTriageNote caused by invalid input
+	ErrInvalidTriage = errors.New("invalid triage")
+)
+
+// TriageUpdate holds the triage fields to change, nil fields are kept
+type TriageUpdate struct {
+	Status   *string
+	Assignee *string   // empty to unassign
+	Labels   *[]string // replaces every label
+}
+
+// Triage returns the triage of a stored review, status new if it was never triaged
+func (a *App) Triage(appID, reviewID string) (models.Triage, error) {
+	if a.GetReview(appID, reviewID) == nil {
+		return models.Triage{}, ErrReviewNotFound
+	}
+	return a.triage.Get(appID, reviewID), nil
+}
+
+// UpdateTriage validates and applies update to the triage of a stored review
+func (a *App) UpdateTriage(appID, reviewID string, update TriageUpdate) (models.Triage, error) {
+	if a.GetReview(appID, reviewID) == nil {
+		return models.Triage{}, ErrReviewNotFound
+	}
+
+	var labels []string
+	if update.Labels != nil {
+		var err error
+		if labels, err = normalizeLabels(*update.Labels); err != nil {
+			return models.Triage{}, fmt.Errorf("%w: %w", ErrInvalidTriage, err)
+		}
+	}
+	if update.Status != nil && !slices.Contains(models.TriageStatuses, *update.Status) {
+		return models.Triage{}, fmt.Errorf("%w: status must be one of %s", ErrInvalidTriage, strings.Join(models.TriageStatuses, ", "))
+	}
+	if update.Assignee != nil && utf8.RuneCountInString(strings.TrimSpace(*update.Assignee)) > maxTriageFieldRunes {
+		return models.Triage{}, fmt.Errorf("%w: assignee must be at most %d characters", ErrInvalidTriage, maxTriageFieldRunes)
+	}
+
+	return a.triage.Update(appID, reviewID, func(record *models.Triage) error {
+		if update.Status != nil {
+			record.Status = *update.Status
+		}
+		if update.Assignee != nil {
+			record.Assignee = strings.TrimSpace(*update.Assignee)
+		}
+		if update.Labels != nil {
+			record.Labels = labels
+		}
+		now := time.Now().UTC()
+		record.UpdatedAt = &now
+		return nil
+	})
+}
+
+// AddTriageNote adds an internal note to the triage of a stored review
+func (a *App) AddTriageNote(appID, reviewID, author, text string) (models.TriageNote, error) {
+	if a.GetReview(appID, reviewID) == nil {
+		return models.TriageNote{}, ErrReviewNotFound
+	}
+
+	text = strings.TrimSpace(text)
+	author = strings.TrimSpace(author)
+	switch {
+	case text == "":
+		return models.TriageNote{}, fmt.Errorf("%w: note text must not be empty", ErrInvalidTriage)
+	case utf8.RuneCountInString(text) > maxTriageNoteRunes:
+		return models.TriageNote{}, fmt.Errorf("%w: note text must be at most %d characters", ErrInvalidTriage, maxTriageNoteRunes)
+	case utf8.RuneCountInString(author) > maxTriageFieldRunes:
+		return models.TriageNote{}, fmt.Errorf("%w: note author must be at most %d characters", ErrInvalidTriage, maxTriageFieldRunes)
+	}
+
+	note := models.TriageNote{ID: ids.New(), Author: author, Text: text, CreatedAt: time.Now().UTC()}
+	_, err := a.triage.Update(appID, reviewID, func(record *models.Triage) error {
+		record.Notes = append(record.Notes, note)
+		record.UpdatedAt = &note.CreatedAt
+		return nil
+	})
+	if err != nil {
+		return models.TriageNote{}, err
+	}
+	return note, nil
+}
+
+// normalizeLabels trims labels, drops empty and repeated ones and sorts them
+func normalizeLabels(labels []string) ([]string, error) {
+	normalized := []string{}
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || slices.Contains(normalized, label) {
+			continue
+		}
+		if utf8.RuneCountInString(label) > maxTriageFieldRunes {
+			return nil, fmt.Errorf("labels must be at most %d characters", maxTriageFieldRunes)
+		}
+		normalized = append(normalized, label)
+	}
+	if len(normalized) > maxTriageLabels {
+		return nil, fmt.Errorf("a review can have at most %d labels", maxTriageLabels)
+	}
+	slices.Sort(normalized)
+	return normalized, nil
+}
+
+// matchesTriage reports whether the triage of review passes the triage conditions of filter
+func (a *App) matchesTriage(appID string, filter repositories.ReviewFilter, review models.AppStoreReview) bool {
+	if !filter.HasTriage() {
+		return true
+	}
+	record := a.triage.Get(appID, review.ID)
+	return (filter.Status == "" || record.Status == filter.Status) &&
+		(filter.Assignee == "" || record.Assignee == filter.Assignee)
+}
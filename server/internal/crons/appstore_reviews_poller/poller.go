@@ -2,27 +2,71 @@ package appstore_reviews_poller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/notifiers"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sources"
 )
 
+// AlertEvaluator evaluates the alert rules over the stored reviews
+type AlertEvaluator interface {
+	Evaluate(ctx context.Context)
+}
+
 type AppStoreReviewsPoller struct {
-	cfg        *config.Config
-	appService app.AppServiceInterface
-	fetcher    FetcherInterface
+	cfg           *config.Config
+	appService    app.AppServiceInterface
+	sources       []sources.ReviewSource
+	tokens        map[sourceApp]string // cursor tokens returned by the last fetch of every source and app
+	notifier      notifiers.Notifier   // optional, called with every batch of newly added reviews, rebuilt on reload
+	alerts        AlertEvaluator       // optional, evaluated after every processLatestReviews
+	configUpdates chan *config.Config
+}
+
+// sourceApp identifies the cursor of an app in a source, by the index of the source
+type sourceApp struct {
+	source int
+	appID  string
 }
 
-func New(cfg *config.Config, appService *app.App) *AppStoreReviewsPoller {
-	fetcher := NewFetcher("https://itunes.apple.com/us/rss/customerreviews")
+// New creates a poller fetching from the REVIEW_SOURCES of cfg, built from the kinds of sources.Default
+func New(cfg *config.Config, appService *app.App) (*AppStoreReviewsPoller, error) {
+	reviewSources, err := sources.Default.Build(cfg.ReviewSources)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range reviewSources {
+		metadata, capabilities := source.Metadata(), source.Capabilities()
+		log.Printf("poller: review source %s (%s) at %s, incremental=%t", metadata.Kind, metadata.Description, metadata.Location, capabilities.Incremental)
+	}
+
 	return &AppStoreReviewsPoller{
-		cfg:        cfg,
-		appService: appService,
-		fetcher:    fetcher,
+		cfg:           cfg,
+		appService:    appService,
+		sources:       reviewSources,
+		tokens:        map[sourceApp]string{},
+		notifier:      notifiers.FromConfig(cfg),
+		alerts:        appService.Alerts(),
+		configUpdates: make(chan *config.Config, 1),
+	}, nil
+}
+
+// UpdateConfig hands a reloaded config to the running poller, it is applied between polls
+func (p *AppStoreReviewsPoller) UpdateConfig(cfg *config.Config) {
+	// drop a pending config the poller didn't pick up yet, the new one supersedes it
+	select {
+	case <-p.configUpdates:
+	default:
 	}
+	p.configUpdates <- cfg
 }
 
 func (p *AppStoreReviewsPoller) Run(ctx context.Context) {
@@ -38,64 +82,155 @@ func (p *AppStoreReviewsPoller) Run(ctx context.Context) {
 			return
 		case <-ticker.C:
 			p.processLatestReviews(ctx)
+		case cfg := <-p.configUpdates:
+			p.applyConfig(ctx, cfg, ticker)
 		}
 	}
 }
 
-// fetchLatestReviews fetches all reviews with pagination
-func (p *AppStoreReviewsPoller) fetchLatestReviews(ctx context.Context, latestReviewId *string) ([]models.AppStoreReview, error) {
-	log.Printf(" > FETCH: fetching reviews - appId: %s", p.cfg.AppID)
+// PollOnce runs a single processLatestReviews for every tracked app and returns
+func (p *AppStoreReviewsPoller) PollOnce(ctx context.Context) {
+	p.processLatestReviews(ctx)
+}
 
-	reviews, err := p.fetcher.fetchReviews(ctx, p.cfg.AppID, latestReviewId)
-	if err != nil {
-		return nil, err
+// applyConfig retunes the ticker and polls newly tracked apps right away
+func (p *AppStoreReviewsPoller) applyConfig(ctx context.Context, cfg *config.Config, ticker *time.Ticker) {
+	previous := p.cfg
+	p.cfg = cfg
+
+	if cfg.PollingInterval != previous.PollingInterval {
+		log.Printf("poller: polling interval changed from %s to %s", previous.PollingInterval, cfg.PollingInterval)
+		ticker.Reset(cfg.PollingInterval)
 	}
 
-	if len(reviews) > 0 { // debug helper
-		log.Printf(" > FETCH: 1st review example ID: %+v", reviews[0].ID)
+	if slackChanged(previous, cfg) {
+		log.Printf("poller: Slack settings changed, rebuilding the notifier")
+		p.notifier = notifiers.FromConfig(cfg)
 	}
 
-	return reviews, nil
+	for _, appID := range cfg.TrackedAppIDs() {
+		if !slices.Contains(previous.TrackedAppIDs(), appID) {
+			log.Printf("poller: started tracking app %s", appID)
+			p.processAppLatestReviews(ctx, appID)
+		}
+	}
 }
 
-// processLatestReviews fetches and processes all latest reviews it can find that are not already in the database
+// fetchLatestReviews fetches the reviews of the given app newer than cursor from every source.
+// A failing source doesn't stop the others, its cursor stays where it was for the next tick.
+func (p *AppStoreReviewsPoller) fetchLatestReviews(ctx context.Context, appID string, cursor sources.Cursor) ([]models.AppStoreReview, error) {
+	var allReviews []models.AppStoreReview
+	var errs []error
+	for i, source := range p.sources {
+		metadata := source.Metadata()
+		log.Printf(" > FETCH: fetching reviews - appId: %s, source: %s", appID, metadata.Kind)
+
+		key := sourceApp{source: i, appID: appID}
+		cursor.Token = p.tokens[key]
+		result, err := source.Fetch(ctx, appID, cursor)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", metadata.Kind, err))
+			continue
+		}
+		p.tokens[key] = result.Token
+
+		if len(result.Reviews) > 0 { // debug helper
+			log.Printf(" > FETCH: 1st review example ID: %+v", result.Reviews[0].ID)
+		}
+		if limit := source.Capabilities().MaxReviewsPerFetch; limit > 0 && len(result.Reviews) >= limit {
+			log.Printf(" > FETCH: %s returned its limit of %d reviews, older new reviews may be missing", metadata.Kind, limit)
+		}
+		allReviews = append(allReviews, result.Reviews...)
+	}
+
+	if len(allReviews) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		log.Printf(" > ❌ FETCH: error fetching reviews: %v", err)
+	}
+	return allReviews, nil
+}
+
+// processLatestReviews fetches and processes all latest reviews it can find that are not already in the database,
+// for every tracked app
 func (p *AppStoreReviewsPoller) processLatestReviews(ctx context.Context) {
-	log.Printf(">> PROCESS: starting processLatestReviews <<")
+	notified := false
+	for _, appID := range p.cfg.TrackedAppIDs() {
+		if p.processAppLatestReviews(ctx, appID) {
+			notified = true
+		}
+	}
+
+	// Notify already retried the failed notifications when there were new reviews
+	if p.notifier != nil && !notified {
+		if err := p.notifier.Flush(ctx); err != nil {
+			log.Printf(" > ❌ PROCESS: error retrying notifications: %v", err)
+		}
+	}
+
+	if p.alerts != nil {
+		p.alerts.Evaluate(ctx)
+	}
+}
+
+// processAppLatestReviews fetches and processes the latest reviews of a single app.
+// Returns true when the notifier was called with new reviews.
+func (p *AppStoreReviewsPoller) processAppLatestReviews(ctx context.Context, appID string) bool {
+	log.Printf(">> PROCESS: starting processLatestReviews - appId: %s <<", appID)
 
-	var latestReviewId *string
-	latestReview := p.appService.GetLatestReview()
+	var cursor sources.Cursor
+	latestReview := p.appService.GetLatestReview(appID)
 
 	if latestReview != nil {
-		latestReviewId = &latestReview.ID
+		cursor.LatestReviewID = latestReview.ID
+		cursor.LatestUpdatedAt = latestReview.UpdatedAt
 		log.Printf(" > PROCESS: latest review saved in db: %s", latestReview.ID)
 	} else {
 		log.Printf(" > PROCESS: no latest review found in db, fetching all possible reviews")
 	}
 
-	reviews, err := p.fetchLatestReviews(ctx, latestReviewId)
+	reviews, err := p.fetchLatestReviews(ctx, appID, cursor)
 	if err != nil {
 		log.Printf(" > ❌ PROCESS: error fetching reviews: %v", err)
 		// intentionally not doing error handling here, if it fails, it will be retried in the next tick
-		return
+		return false
 	}
 
 	if len(reviews) == 0 {
 		log.Printf(" > PROCESS: no new reviews found ✅")
-		return
+		return false
 	}
 
 	log.Printf(" > PROCESS: found %d reviews", len(reviews))
 
-	added, err := p.appService.AddReviews(reviews)
+	added, err := p.appService.AddReviews(appID, reviews)
 	if err != nil {
 		log.Printf(" > ❌ PROCESS: error adding reviews: %v", err)
 		// intentionally not doing error handling here, if it fails, it will be retried in the next tick
-		return
+		return false
 	}
 
-	if added > 0 {
-		log.Printf(" > PROCESS: added %d new reviews ✅", added)
+	if len(added) > 0 {
+		log.Printf(" > PROCESS: added %d new reviews ✅", len(added))
 	} else {
 		log.Printf(" > PROCESS: no new reviews added ✅")
+		return false
 	}
+
+	if p.notifier == nil {
+		return false
+	}
+	if err := p.notifier.Notify(ctx, appID, added); err != nil {
+		// failed notifications are kept by the notifier and retried with the next batch or poll
+		log.Printf(" > ❌ PROCESS: error notifying new reviews: %v", err)
+	}
+	return true
+}
+
+// slackChanged reports whether the Slack settings the notifier is built from changed
+func slackChanged(previous, cfg *config.Config) bool {
+	return previous.SlackWebhookURL != cfg.SlackWebhookURL ||
+		!slices.Equal(previous.SlackAlertRatings, cfg.SlackAlertRatings) ||
+		!maps.Equal(previous.SlackRoutes, cfg.SlackRoutes)
 }
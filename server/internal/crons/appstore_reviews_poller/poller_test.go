@@ -3,64 +3,133 @@ package appstore_reviews_poller
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/notifiers"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sources"
 )
 
 // MockApp is a mock implementation of the App service for testing
 type MockApp struct {
+	mu                   sync.Mutex // the poller calls the mock from its own goroutine in Run tests
 	addReviewsFuncCalled int
+	addReviewsAppIDs     []string
+	addedReviews         []models.AppStoreReview
 	mockedLatestReview   *models.AppStoreReview
 }
 
-func (a *MockApp) ListLatestReviews(hours int, rating *int) []models.AppStoreReview {
+func (a *MockApp) ListLatestReviews(appID string, hours int, rating *int) []models.AppStoreReview {
 	return nil
 }
-func (a *MockApp) GetLatestReview() *models.AppStoreReview {
+func (a *MockApp) GetLatestReview(appID string) *models.AppStoreReview {
 	return a.mockedLatestReview
 }
-func (a *MockApp) AddReviews(reviews []models.AppStoreReview) (int, error) {
+func (a *MockApp) AddReviews(appID string, reviews []models.AppStoreReview) ([]models.AppStoreReview, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.addReviewsFuncCalled++
-	return len(reviews), nil
+	a.addReviewsAppIDs = append(a.addReviewsAppIDs, appID)
+	a.addedReviews = append(a.addedReviews, reviews...)
+	return reviews, nil
+}
+
+// addReviewsCalls returns addReviewsFuncCalled, for tests reading it while the poller runs
+func (a *MockApp) addReviewsCalls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.addReviewsFuncCalled
 }
 func (a *MockApp) GetAppID() string {
 	return ""
 }
+func (a *MockApp) TrackedAppIDs() []string {
+	return nil
+}
 
-// MockFetcher is a mock implementation of the Fetcher for testing
-type MockFetcher struct {
-	mockedReviews          []models.AppStoreReview
-	mockedError            error
-	capturedAppID          string
-	capturedLatestReviewId *string
-	fetchReviewsCalled     int
+// MockSource is a mock implementation of a ReviewSource for testing
+type MockSource struct {
+	mu             sync.Mutex // the poller calls the mock from its own goroutine in Run tests
+	mockedReviews  []models.AppStoreReview
+	mockedToken    string
+	mockedError    error
+	capturedAppID  string
+	capturedCursor sources.Cursor
+	fetchCalled    int
 }
 
-func (f *MockFetcher) fetchReviews(ctx context.Context, appID string, latestReviewId *string) ([]models.AppStoreReview, error) {
-	f.fetchReviewsCalled++
+func (f *MockSource) Metadata() sources.Metadata {
+	return sources.Metadata{Kind: "mock"}
+}
+func (f *MockSource) Capabilities() sources.Capabilities {
+	return sources.Capabilities{Incremental: true}
+}
+func (f *MockSource) Fetch(ctx context.Context, appID string, cursor sources.Cursor) (sources.FetchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetchCalled++
 	f.capturedAppID = appID
-	f.capturedLatestReviewId = latestReviewId
+	f.capturedCursor = cursor
 
 	if f.mockedError != nil {
-		return nil, f.mockedError
+		return sources.FetchResult{}, f.mockedError
 	}
-	return f.mockedReviews, nil
+	return sources.FetchResult{Reviews: f.mockedReviews, Token: f.mockedToken}, nil
+}
+
+// fetchCalls returns fetchCalled and capturedAppID, for tests reading them while the poller runs
+func (f *MockSource) fetchCalls() (int, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetchCalled, f.capturedAppID
+}
+
+// MockNotifier is a mock implementation of the Notifier for testing
+type MockNotifier struct {
+	notifiedBatches [][]models.AppStoreReview
+	capturedAppID   string
+	flushCalled     int
+}
+
+func (n *MockNotifier) Notify(ctx context.Context, appID string, reviews []models.AppStoreReview) error {
+	n.notifiedBatches = append(n.notifiedBatches, reviews)
+	n.capturedAppID = appID
+	return nil
+}
+
+func (n *MockNotifier) Flush(ctx context.Context) error {
+	n.flushCalled++
+	return nil
+}
+
+// MockAlertEvaluator is a mock implementation of the AlertEvaluator for testing
+type MockAlertEvaluator struct {
+	evaluateCallCount int
+}
+
+func (e *MockAlertEvaluator) Evaluate(ctx context.Context) {
+	e.evaluateCallCount++
 }
 
 // createTestPoller creates a poller with mocked dependencies for testing
-func createTestPoller(mockApp *MockApp, mockFetcher *MockFetcher) *AppStoreReviewsPoller {
+func createTestPoller(mockApp *MockApp, mockSources ...*MockSource) *AppStoreReviewsPoller {
 	cfg := &config.Config{
 		AppID:           "test-app-id",
 		PollingInterval: 100 * time.Millisecond, // Short interval for testing
 	}
 
 	poller := &AppStoreReviewsPoller{
-		cfg:        cfg,
-		appService: mockApp,
-		fetcher:    mockFetcher,
+		cfg:           cfg,
+		appService:    mockApp,
+		tokens:        map[sourceApp]string{},
+		configUpdates: make(chan *config.Config, 1),
+	}
+
+	for _, source := range mockSources {
+		poller.sources = append(poller.sources, source)
 	}
 
 	return poller
@@ -69,8 +138,8 @@ func createTestPoller(mockApp *MockApp, mockFetcher *MockFetcher) *AppStoreRevie
 // TestRun_StopsGracefullyWhenContextCancelled verifies that the poller stops gracefully when context is cancelled
 func TestRun_StopsGracefullyWhenContextCancelled(t *testing.T) {
 	mockApp := &MockApp{}
-	mockFetcher := &MockFetcher{}
-	poller := createTestPoller(mockApp, mockFetcher)
+	mockSource := &MockSource{}
+	poller := createTestPoller(mockApp, mockSource)
 
 	// Create a context that we can cancel
 	ctx, cancel := context.WithCancel(context.Background())
@@ -100,7 +169,7 @@ func TestRun_StopsGracefullyWhenContextCancelled(t *testing.T) {
 // TestRun_ExecutesImmediatelyBeforeTickerStarts verifies that the first run happens immediately before ticker starts
 func TestRun_ExecutesImmediatelyBeforeTickerStarts(t *testing.T) {
 	mockApp := &MockApp{}
-	mockFetcher := &MockFetcher{
+	mockSource := &MockSource{
 		mockedReviews: []models.AppStoreReview{
 			{
 				ID:        "test-review-1",
@@ -112,7 +181,7 @@ func TestRun_ExecutesImmediatelyBeforeTickerStarts(t *testing.T) {
 			},
 		},
 	}
-	poller := createTestPoller(mockApp, mockFetcher)
+	poller := createTestPoller(mockApp, mockSource)
 
 	// Use a very long polling interval to ensure we're testing immediate execution
 	poller.cfg.PollingInterval = 10 * time.Second
@@ -133,7 +202,7 @@ func TestRun_ExecutesImmediatelyBeforeTickerStarts(t *testing.T) {
 	time.Sleep(10 * time.Millisecond)
 
 	// Verify that processLatestReviews was called immediately
-	addCalls := mockApp.addReviewsFuncCalled
+	addCalls := mockApp.addReviewsCalls()
 	if addCalls != 1 {
 		t.Errorf("Expected exactly 1 call to AddReviews (immediate run), got %d", addCalls)
 	}
@@ -142,7 +211,7 @@ func TestRun_ExecutesImmediatelyBeforeTickerStarts(t *testing.T) {
 // TestRun_ExecutesPeriodicProcessingWithTicker verifies that periodic processing works with ticker
 func TestRun_ExecutesPeriodicProcessingWithTicker(t *testing.T) {
 	mockApp := &MockApp{}
-	mockFetcher := &MockFetcher{
+	mockSource := &MockSource{
 		mockedReviews: []models.AppStoreReview{
 			{
 				ID:        "test-review-1",
@@ -154,7 +223,7 @@ func TestRun_ExecutesPeriodicProcessingWithTicker(t *testing.T) {
 			},
 		},
 	}
-	poller := createTestPoller(mockApp, mockFetcher)
+	poller := createTestPoller(mockApp, mockSource)
 
 	// Use a short polling interval for testing
 	poller.cfg.PollingInterval = 50 * time.Millisecond
@@ -176,7 +245,7 @@ func TestRun_ExecutesPeriodicProcessingWithTicker(t *testing.T) {
 
 	// Verify that processLatestReviews was called multiple times
 	// Should be at least: 1 (immediate) + 3-4 (periodic calls in 200ms with 50ms interval)
-	addCalls := mockApp.addReviewsFuncCalled
+	addCalls := mockApp.addReviewsCalls()
 	if addCalls < 3 {
 		t.Errorf("Expected at least 3 calls to AddReviews (1 immediate + periodic), got %d", addCalls)
 	}
@@ -188,10 +257,10 @@ func TestRun_ExecutesPeriodicProcessingWithTicker(t *testing.T) {
 	}
 }
 
-// TestProcessLatestReviews_CallsAddReviewsWhenReviewsReturned verifies that AddReviews is called when fetcher returns reviews
+// TestProcessLatestReviews_CallsAddReviewsWhenReviewsReturned verifies that AddReviews is called when the source returns reviews
 func TestProcessLatestReviews_CallsAddReviewsWhenReviewsReturned(t *testing.T) {
 	mockApp := &MockApp{}
-	mockFetcher := &MockFetcher{
+	mockSource := &MockSource{
 		mockedReviews: []models.AppStoreReview{
 			{
 				ID:        "test-review-1",
@@ -211,7 +280,7 @@ func TestProcessLatestReviews_CallsAddReviewsWhenReviewsReturned(t *testing.T) {
 			},
 		},
 	}
-	poller := createTestPoller(mockApp, mockFetcher)
+	poller := createTestPoller(mockApp, mockSource)
 
 	ctx := context.Background()
 
@@ -224,13 +293,13 @@ func TestProcessLatestReviews_CallsAddReviewsWhenReviewsReturned(t *testing.T) {
 	}
 }
 
-// TestProcessLatestReviews_DoesNotCallAddReviewsWhenNoReviewsReturned verifies that AddReviews is not called when fetcher returns no reviews
+// TestProcessLatestReviews_DoesNotCallAddReviewsWhenNoReviewsReturned verifies that AddReviews is not called when the source returns no reviews
 func TestProcessLatestReviews_DoesNotCallAddReviewsWhenNoReviewsReturned(t *testing.T) {
 	mockApp := &MockApp{}
-	mockFetcher := &MockFetcher{
+	mockSource := &MockSource{
 		mockedReviews: []models.AppStoreReview{}, // Empty slice - no reviews returned
 	}
-	poller := createTestPoller(mockApp, mockFetcher)
+	poller := createTestPoller(mockApp, mockSource)
 
 	ctx := context.Background()
 
@@ -243,10 +312,10 @@ func TestProcessLatestReviews_DoesNotCallAddReviewsWhenNoReviewsReturned(t *test
 	}
 }
 
-// TestProcessLatestReviews_PassesNilLatestReviewIdWhenNotAvailable verifies that nil is passed to fetchReviews when no latest review is available
-func TestProcessLatestReviews_PassesNilLatestReviewIdWhenNotAvailable(t *testing.T) {
+// TestProcessLatestReviews_PassesEmptyCursorWhenNotAvailable verifies that an empty cursor is passed to Fetch when no latest review is available
+func TestProcessLatestReviews_PassesEmptyCursorWhenNotAvailable(t *testing.T) {
 	mockApp := &MockApp{} // GetLatestReview() returns nil by default
-	mockFetcher := &MockFetcher{
+	mockSource := &MockSource{
 		mockedReviews: []models.AppStoreReview{
 			{
 				ID:        "test-review-1",
@@ -258,30 +327,30 @@ func TestProcessLatestReviews_PassesNilLatestReviewIdWhenNotAvailable(t *testing
 			},
 		},
 	}
-	poller := createTestPoller(mockApp, mockFetcher)
+	poller := createTestPoller(mockApp, mockSource)
 
 	ctx := context.Background()
 
 	// Call processLatestReviews directly
 	poller.processLatestReviews(ctx)
 
-	// Verify that fetchReviews was called with nil latestReviewId
-	if mockFetcher.fetchReviewsCalled != 1 {
-		t.Errorf("Expected fetchReviews to be called exactly once, got %d calls", mockFetcher.fetchReviewsCalled)
+	// Verify that Fetch was called with an empty cursor
+	if mockSource.fetchCalled != 1 {
+		t.Errorf("Expected Fetch to be called exactly once, got %d calls", mockSource.fetchCalled)
 	}
 
-	if mockFetcher.capturedLatestReviewId != nil {
-		t.Errorf("Expected latestReviewId to be nil when no latest review is available, got %v", mockFetcher.capturedLatestReviewId)
+	if mockSource.capturedCursor != (sources.Cursor{}) {
+		t.Errorf("Expected an empty cursor when no latest review is available, got %+v", mockSource.capturedCursor)
 	}
 
 	// Verify the appID was passed correctly
 	expectedAppID := "test-app-id"
-	if mockFetcher.capturedAppID != expectedAppID {
-		t.Errorf("Expected appID to be %s, got %s", expectedAppID, mockFetcher.capturedAppID)
+	if mockSource.capturedAppID != expectedAppID {
+		t.Errorf("Expected appID to be %s, got %s", expectedAppID, mockSource.capturedAppID)
 	}
 }
 
-// TestProcessLatestReviews_PassesLatestReviewIdWhenAvailable verifies that the latest review ID is passed to fetchReviews when available
+// TestProcessLatestReviews_PassesLatestReviewIdWhenAvailable verifies that the latest review ID is passed in the cursor when available
 func TestProcessLatestReviews_PassesLatestReviewIdWhenAvailable(t *testing.T) {
 	expectedLatestReviewID := "existing-review-123"
 	mockApp := &MockApp{
@@ -294,7 +363,7 @@ func TestProcessLatestReviews_PassesLatestReviewIdWhenAvailable(t *testing.T) {
 			UpdatedAt: time.Now().Add(-24 * time.Hour), // 1 day ago
 		},
 	}
-	mockFetcher := &MockFetcher{
+	mockSource := &MockSource{
 		mockedReviews: []models.AppStoreReview{
 			{
 				ID:        "new-review-456",
@@ -306,51 +375,302 @@ func TestProcessLatestReviews_PassesLatestReviewIdWhenAvailable(t *testing.T) {
 			},
 		},
 	}
-	poller := createTestPoller(mockApp, mockFetcher)
+	poller := createTestPoller(mockApp, mockSource)
 
 	ctx := context.Background()
 
 	// Call processLatestReviews directly
 	poller.processLatestReviews(ctx)
 
-	// Verify that fetchReviews was called with the correct latestReviewId
-	if mockFetcher.fetchReviewsCalled != 1 {
-		t.Errorf("Expected fetchReviews to be called exactly once, got %d calls", mockFetcher.fetchReviewsCalled)
+	// Verify that Fetch was called with the correct latest review ID
+	if mockSource.fetchCalled != 1 {
+		t.Errorf("Expected Fetch to be called exactly once, got %d calls", mockSource.fetchCalled)
 	}
 
-	if mockFetcher.capturedLatestReviewId == nil {
-		t.Error("Expected latestReviewId to not be nil when latest review is available")
-	} else if *mockFetcher.capturedLatestReviewId != expectedLatestReviewID {
-		t.Errorf("Expected latestReviewId to be %s, got %s", expectedLatestReviewID, *mockFetcher.capturedLatestReviewId)
+	if mockSource.capturedCursor.LatestReviewID != expectedLatestReviewID {
+		t.Errorf("Expected the cursor latest review ID to be %s, got %s", expectedLatestReviewID, mockSource.capturedCursor.LatestReviewID)
+	}
+	if !mockSource.capturedCursor.LatestUpdatedAt.Equal(mockApp.mockedLatestReview.UpdatedAt) {
+		t.Errorf("Expected the cursor to have the update time of the latest review, got %s", mockSource.capturedCursor.LatestUpdatedAt)
 	}
 
 	// Verify the appID was passed correctly
 	expectedAppID := "test-app-id"
-	if mockFetcher.capturedAppID != expectedAppID {
-		t.Errorf("Expected appID to be %s, got %s", expectedAppID, mockFetcher.capturedAppID)
+	if mockSource.capturedAppID != expectedAppID {
+		t.Errorf("Expected appID to be %s, got %s", expectedAppID, mockSource.capturedAppID)
 	}
 }
 
-// TestProcessLatestReviews_HandlesErrorGracefully verifies that the poller handles fetchReviews errors gracefully
+// TestProcessLatestReviews_HandlesErrorGracefully verifies that the poller handles Fetch errors gracefully
 func TestProcessLatestReviews_HandlesErrorGracefully(t *testing.T) {
 	mockApp := &MockApp{}
-	mockFetcher := &MockFetcher{
+	mockSource := &MockSource{
 		mockedError: errors.New("network connection failed"),
 	}
-	poller := createTestPoller(mockApp, mockFetcher)
+	poller := createTestPoller(mockApp, mockSource)
 
 	ctx := context.Background()
 
 	// Call processLatestReviews directly - this should not panic or crash
 	poller.processLatestReviews(ctx)
 
-	// Verify that fetchReviews was called
-	if mockFetcher.fetchReviewsCalled != 1 {
-		t.Errorf("Expected fetchReviews to be called exactly once, got %d calls", mockFetcher.fetchReviewsCalled)
+	// Verify that Fetch was called
+	if mockSource.fetchCalled != 1 {
+		t.Errorf("Expected Fetch to be called exactly once, got %d calls", mockSource.fetchCalled)
 	}
 
 	// Verify that AddReviews was NOT called when an error occurs
 	if mockApp.addReviewsFuncCalled != 0 {
-		t.Errorf("Expected AddReviews to not be called when fetchReviews returns an error, got %d calls", mockApp.addReviewsFuncCalled)
+		t.Errorf("Expected AddReviews to not be called when Fetch returns an error, got %d calls", mockApp.addReviewsFuncCalled)
+	}
+}
+
+// TestProcessLatestReviews_ProcessesEveryTrackedApp verifies that each tracked app is fetched and stored under its own ID
+func TestProcessLatestReviews_ProcessesEveryTrackedApp(t *testing.T) {
+	mockApp := &MockApp{}
+	mockSource := &MockSource{
+		mockedReviews: []models.AppStoreReview{
+			{ID: "test-review-1", Rating: 5, UpdatedAt: time.Now()},
+		},
+	}
+	poller := createTestPoller(mockApp, mockSource)
+	poller.cfg.AppIDs = []string{"test-app-id", "other-app-id"}
+
+	poller.processLatestReviews(context.Background())
+
+	if mockSource.fetchCalled != 2 {
+		t.Errorf("Expected Fetch to be called once per tracked app, got %d calls", mockSource.fetchCalled)
+	}
+
+	expectedAppIDs := []string{"test-app-id", "other-app-id"}
+	if len(mockApp.addReviewsAppIDs) != len(expectedAppIDs) {
+		t.Fatalf("Expected AddReviews to be called for %v, got %v", expectedAppIDs, mockApp.addReviewsAppIDs)
+	}
+	for i, appID := range expectedAppIDs {
+		if mockApp.addReviewsAppIDs[i] != appID {
+			t.Errorf("Expected AddReviews call %d to be for app %s, got %s", i, appID, mockApp.addReviewsAppIDs[i])
+		}
+	}
+}
+
+// TestProcessLatestReviews_FetchesEverySource verifies that the reviews of every source are added, a failing source not stopping the others
+func TestProcessLatestReviews_FetchesEverySource(t *testing.T) {
+	mockApp := &MockApp{}
+	failingSource := &MockSource{mockedError: errors.New("network connection failed")}
+	workingSource := &MockSource{
+		mockedReviews: []models.AppStoreReview{
+			{ID: "test-review-1", Rating: 5, UpdatedAt: time.Now()},
+		},
+	}
+	poller := createTestPoller(mockApp, failingSource, workingSource)
+
+	poller.processLatestReviews(context.Background())
+
+	if failingSource.fetchCalled != 1 || workingSource.fetchCalled != 1 {
+		t.Errorf("Expected every source to be fetched once, got %d and %d calls", failingSource.fetchCalled, workingSource.fetchCalled)
+	}
+	if len(mockApp.addedReviews) != 1 || mockApp.addedReviews[0].ID != "test-review-1" {
+		t.Errorf("Expected the review of the working source to be added, got %v", mockApp.addedReviews)
+	}
+}
+
+// TestProcessLatestReviews_PassesTokensBack verifies that the token returned by a source is in its cursor on the next poll,
+// and is kept when the fetch fails
+func TestProcessLatestReviews_PassesTokensBack(t *testing.T) {
+	mockApp := &MockApp{}
+	mockSource := &MockSource{mockedToken: "token-1"}
+	otherSource := &MockSource{mockedToken: "other-token"}
+	poller := createTestPoller(mockApp, mockSource, otherSource)
+
+	poller.processLatestReviews(context.Background())
+	if mockSource.capturedCursor.Token != "" {
+		t.Errorf("Expected no token on the first poll, got %s", mockSource.capturedCursor.Token)
+	}
+
+	mockSource.mockedError = errors.New("network connection failed")
+	poller.processLatestReviews(context.Background())
+	if mockSource.capturedCursor.Token != "token-1" {
+		t.Errorf("Expected the token of the first poll, got %s", mockSource.capturedCursor.Token)
+	}
+	if otherSource.capturedCursor.Token != "other-token" {
+		t.Errorf("Expected every source to get its own token, got %s", otherSource.capturedCursor.Token)
+	}
+
+	mockSource.mockedError = nil
+	poller.processLatestReviews(context.Background())
+	if mockSource.capturedCursor.Token != "token-1" {
+		t.Errorf("Expected the token to be kept after a failed fetch, got %s", mockSource.capturedCursor.Token)
+	}
+}
+
+// TestNew_FailsOnUnknownSource verifies that the poller isn't created when REVIEW_SOURCES has an unknown kind
+func TestNew_FailsOnUnknownSource(t *testing.T) {
+	cfg := &config.Config{AppID: "test-app-id", ReviewSources: []string{"appstore-rss", "unknown"}}
+
+	if _, err := New(cfg, nil); err == nil {
+		t.Error("Expected an error for the unknown source")
+	}
+}
+
+// TestRun_AppliesUpdatedPollingInterval verifies that a reloaded config retunes the ticker without restarting the poller
+func TestRun_AppliesUpdatedPollingInterval(t *testing.T) {
+	mockApp := &MockApp{}
+	mockSource := &MockSource{}
+	poller := createTestPoller(mockApp, mockSource)
+
+	// Start with an interval long enough to never tick during the test
+	poller.cfg.PollingInterval = 10 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go poller.Run(ctx)
+	time.Sleep(20 * time.Millisecond)
+
+	poller.UpdateConfig(&config.Config{
+		AppID:           "test-app-id",
+		PollingInterval: 50 * time.Millisecond,
+	})
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+
+	// 1 immediate run + 3-4 runs with the new interval
+	if fetchCalled, _ := mockSource.fetchCalls(); fetchCalled < 3 {
+		t.Errorf("Expected the new polling interval to be applied, got only %d calls to Fetch", fetchCalled)
+	}
+}
+
+// TestRun_PollsNewlyTrackedAppImmediately verifies that an app added by a config reload is polled without waiting for the next tick
+func TestRun_PollsNewlyTrackedAppImmediately(t *testing.T) {
+	mockApp := &MockApp{}
+	mockSource := &MockSource{}
+	poller := createTestPoller(mockApp, mockSource)
+	poller.cfg.PollingInterval = 10 * time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go poller.Run(ctx)
+	time.Sleep(20 * time.Millisecond)
+
+	poller.UpdateConfig(&config.Config{
+		AppID:           "test-app-id",
+		AppIDs:          []string{"test-app-id", "new-app-id"},
+		PollingInterval: 10 * time.Second,
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+
+	fetchCalled, capturedAppID := mockSource.fetchCalls()
+	if fetchCalled != 2 {
+		t.Errorf("Expected 2 calls to Fetch (immediate run + new app), got %d", fetchCalled)
+	}
+	if capturedAppID != "new-app-id" {
+		t.Errorf("Expected the new app to be fetched, got %s", capturedAppID)
+	}
+}
+
+// TestProcessLatestReviews_NotifiesAddedReviews verifies that the notifier is called with the batch of added reviews
+func TestProcessLatestReviews_NotifiesAddedReviews(t *testing.T) {
+	mockApp := &MockApp{}
+	mockSource := &MockSource{
+		mockedReviews: []models.AppStoreReview{
+			{ID: "test-review-1", Rating: 1, UpdatedAt: time.Now()},
+			{ID: "test-review-2", Rating: 5, UpdatedAt: time.Now()},
+		},
+	}
+	mockNotifier := &MockNotifier{}
+	poller := createTestPoller(mockApp, mockSource)
+	poller.notifier = mockNotifier
+
+	poller.processLatestReviews(context.Background())
+
+	if len(mockNotifier.notifiedBatches) != 1 {
+		t.Fatalf("Expected 1 notified batch, got %d", len(mockNotifier.notifiedBatches))
+	}
+	if len(mockNotifier.notifiedBatches[0]) != 2 {
+		t.Errorf("Expected 2 reviews in the batch, got %d", len(mockNotifier.notifiedBatches[0]))
+	}
+	if mockNotifier.capturedAppID != "test-app-id" {
+		t.Errorf("Expected notification for test-app-id, got %s", mockNotifier.capturedAppID)
+	}
+}
+
+// TestProcessLatestReviews_DoesNotNotifyWhenNoReviewsReturned verifies that the notifier is not called without new reviews
+func TestProcessLatestReviews_DoesNotNotifyWhenNoReviewsReturned(t *testing.T) {
+	mockNotifier := &MockNotifier{}
+	poller := createTestPoller(&MockApp{}, &MockSource{})
+	poller.notifier = mockNotifier
+
+	poller.processLatestReviews(context.Background())
+
+	if len(mockNotifier.notifiedBatches) != 0 {
+		t.Errorf("Expected no notification, got %d batches", len(mockNotifier.notifiedBatches))
+	}
+}
+
+// TestProcessLatestReviews_FlushesNotificationsWithoutNewReviews verifies that failed notifications are retried on
+// polls without new reviews, and only by Notify on the others
+func TestProcessLatestReviews_FlushesNotificationsWithoutNewReviews(t *testing.T) {
+	mockNotifier := &MockNotifier{}
+	mockSource := &MockSource{}
+	poller := createTestPoller(&MockApp{}, mockSource)
+	poller.notifier = mockNotifier
+
+	poller.processLatestReviews(context.Background())
+	if mockNotifier.flushCalled != 1 {
+		t.Errorf("Expected the notifier to be flushed once, got %d", mockNotifier.flushCalled)
+	}
+
+	mockSource.mockedReviews = []models.AppStoreReview{{ID: "test-review-1", Rating: 1, UpdatedAt: time.Now()}}
+	poller.processLatestReviews(context.Background())
+	if mockNotifier.flushCalled != 1 || len(mockNotifier.notifiedBatches) != 1 {
+		t.Errorf("Expected Notify instead of Flush, got %d flushes and %d batches", mockNotifier.flushCalled, len(mockNotifier.notifiedBatches))
+	}
+}
+
+// TestApplyConfig_RebuildsNotifierWhenSlackChanges verifies that reloaded Slack settings replace the notifier
+func TestApplyConfig_RebuildsNotifierWhenSlackChanges(t *testing.T) {
+	mockNotifier := &MockNotifier{}
+	poller := createTestPoller(&MockApp{}, &MockSource{})
+	poller.notifier = mockNotifier
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	unchanged := *poller.cfg
+	poller.applyConfig(context.Background(), &unchanged, ticker)
+	if poller.notifier != mockNotifier {
+		t.Error("Expected the notifier to be kept when Slack settings didn't change")
+	}
+
+	withSlack := unchanged
+	withSlack.SlackRoutes = map[int]string{1: "https://hooks.slack.com/services/test"}
+	poller.applyConfig(context.Background(), &withSlack, ticker)
+	if _, ok := poller.notifier.(*notifiers.SlackNotifier); !ok {
+		t.Errorf("Expected a Slack notifier for the new routes, got %T", poller.notifier)
+	}
+
+	poller.applyConfig(context.Background(), &unchanged, ticker)
+	if poller.notifier != nil {
+		t.Errorf("Expected no notifier once Slack is disabled, got %T", poller.notifier)
+	}
+}
+
+// TestProcessLatestReviews_EvaluatesAlertRules verifies that alert rules are evaluated once per run, after every app was processed
+func TestProcessLatestReviews_EvaluatesAlertRules(t *testing.T) {
+	mockApp := &MockApp{}
+	mockEvaluator := &MockAlertEvaluator{}
+	poller := createTestPoller(mockApp, &MockSource{})
+	poller.cfg.AppIDs = []string{"test-app-id", "other-app-id"}
+	poller.alerts = mockEvaluator
+
+	poller.processLatestReviews(context.Background())
+
+	if mockEvaluator.evaluateCallCount != 1 {
+		t.Errorf("Expected alert rules to be evaluated once, got %d", mockEvaluator.evaluateCallCount)
 	}
 }
@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// Job sends the digest of each schedule once its period is over
+type Job struct {
+	source     ReviewSource
+	mailer     Mailer
+	repo       *repositories.DigestsRepository
+	recipients []string
+	schedules  []string
+	hour       int // UTC hour periods end at
+	now        func() time.Time
+}
+
+// New creates a Job sending the digests of schedules to recipients
+func New(source ReviewSource, mailer Mailer, repo *repositories.DigestsRepository, recipients, schedules []string, hour int) *Job {
+	return &Job{
+		source:     source,
+		mailer:     mailer,
+		repo:       repo,
+		recipients: recipients,
+		schedules:  schedules,
+		hour:       hour,
+		now:        time.Now,
+	}
+}
+
+// FromConfig creates the digest job configured in cfg, nil when digests are disabled
+func FromConfig(cfg *config.Config, source ReviewSource) *Job {
+	if cfg.SMTPHost == "" || len(cfg.DigestRecipients) == 0 {
+		return nil
+	}
+
+	mailer := NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	repo := repositories.LoadDigests(cfg.DataFilePath("digests.json"))
+	return New(source, mailer, repo, cfg.DigestRecipients, cfg.DigestSchedules, cfg.DigestHour)
+}
+
+// Run sends the due digests every checkInterval until ctx is done
+func (j *Job) Run(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		j.SendDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends the digest of every schedule whose last completed period wasn't sent yet.
+// Failed digests are retried on the next call.
+func (j *Job) SendDue() {
+	now := j.now()
+	for _, schedule := range j.schedules {
+		end := PeriodEnd(schedule, j.hour, now)
+		if !j.repo.LastSent(schedule).Before(end) {
+			continue
+		}
+
+		if err := j.Send(schedule, end); err != nil {
+			log.Printf("DIGEST: error sending %s digest: %v", schedule, err)
+			continue
+		}
+		if err := j.repo.MarkSent(schedule, end); err != nil {
+			log.Printf("DIGEST: error saving %s digest: %v", schedule, err)
+		}
+	}
+}
+
+// Send builds and sends the digest of schedule for the period ending at end
+func (j *Job) Send(schedule string, end time.Time) error {
+	report := Build(j.source, schedule, end, j.now())
+	text, html, err := Render(report)
+	if err != nil {
+		return fmt.Errorf("rendering digest: %w", err)
+	}
+
+	if err := j.mailer.Send(j.recipients, report.Title(), text, html); err != nil {
+		return err
+	}
+
+	log.Printf("DIGEST: sent %s digest of %s to %d recipients", schedule, end.Format(time.DateOnly), len(j.recipients))
+	return nil
+}
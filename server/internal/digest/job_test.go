@@ -0,0 +1,300 @@
+package digest
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	// Suppress logs during testing
+	log.SetOutput(io.Discard)
+
+	// Run tests
+	code := m.Run()
+
+	// Restore log output
+	log.SetOutput(os.Stderr)
+
+	// Exit with the same code as the tests
+	os.Exit(code)
+}
+
+// smtpStub is an in-process SMTP server recording the messages it receives
+type smtpStub struct {
+	mu       sync.Mutex
+	listener net.Listener
+	messages []stubMessage
+}
+
+type stubMessage struct {
+	from string
+	to   []string
+	data []byte
+}
+
+func startSMTPStub(t *testing.T) *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start SMTP stub: %v", err)
+	}
+	stub := &smtpStub{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go stub.serve(conn)
+		}
+	}()
+	return stub
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 stub ESMTP")
+
+	var message stubMessage
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 stub")
+		case "MAIL":
+			message = stubMessage{from: strings.TrimPrefix(line, "MAIL FROM:")}
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			message.to = append(message.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			message.data = data
+			s.mu.Lock()
+			s.messages = append(s.messages, message)
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (s *smtpStub) received() []stubMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]stubMessage(nil), s.messages...)
+}
+
+func (s *smtpStub) mailer() *SMTPMailer {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return NewSMTPMailer("127.0.0.1", addr.Port, "", "", "digest@example.com")
+}
+
+// fakeSource serves reviews relative to the real clock, like the app service does
+type fakeSource struct {
+	reviews map[string][]models.AppStoreReview
+}
+
+func (s *fakeSource) QueryReviews(appID string, hours int, filter repositories.ReviewFilter, sortBy string) []models.AppStoreReview {
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	var result []models.AppStoreReview
+	for _, review := range s.reviews[appID] {
+		if review.UpdatedAt.After(since) && filter.Matches(review) {
+			result = append(result, review)
+		}
+	}
+	return result
+}
+
+func (s *fakeSource) TrackedAppIDs() []string { return []string{"test-app"} }
+
+// createTestSource returns reviews around the daily period ending at end
+func createTestSource(end time.Time) *fakeSource {
+	return &fakeSource{reviews: map[string][]models.AppStoreReview{"test-app": {
+		{ID: "1", Title: "Crashes", Content: "Crashes on launch every single time", Author: "User1", Rating: 1, UpdatedAt: end.Add(-time.Hour)},
+		{ID: "2", Title: "Meh", Content: "Slow", Author: "User2", Rating: 2, UpdatedAt: end.Add(-2 * time.Hour)},
+		{ID: "3", Title: "Love it", Content: "Best app <ever>", Author: "User3", Rating: 5, UpdatedAt: end.Add(-3 * time.Hour)},
+		{ID: "4", Title: "Good", Content: "Nice", Author: "User4", Rating: 4, UpdatedAt: end.Add(-4 * time.Hour)},
+		{ID: "5", Title: "Previous", Content: "Fine", Author: "User5", Rating: 5, UpdatedAt: end.Add(-30 * time.Hour)},
+		{ID: "6", Title: "Future", Content: "After the period", Author: "User6", Rating: 1, UpdatedAt: end.Add(time.Minute)},
+	}}}
+}
+
+// TestPeriodEnd verifies the end of the last completed daily and weekly periods
+func TestPeriodEnd(t *testing.T) {
+	wednesday := time.Date(2024, 1, 17, 7, 30, 0, 0, time.UTC)
+
+	if end := PeriodEnd(Daily, 8, wednesday); !end.Equal(time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected daily period to end yesterday at 8:00, got %s", end)
+	}
+	if end := PeriodEnd(Daily, 8, wednesday.Add(time.Hour)); !end.Equal(time.Date(2024, 1, 17, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected daily period to end today at 8:00, got %s", end)
+	}
+	if end := PeriodEnd(Weekly, 8, wednesday); !end.Equal(time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected weekly period to end on Monday, got %s", end)
+	}
+}
+
+// TestBuild_ComparesWithPreviousPeriod verifies the counts, averages, histogram and top reviews of a report
+func TestBuild_ComparesWithPreviousPeriod(t *testing.T) {
+	end := PeriodEnd(Daily, time.Now().UTC().Hour(), time.Now())
+	report := Build(createTestSource(end), Daily, end, time.Now())
+
+	if len(report.Apps) != 1 {
+		t.Fatalf("Expected 1 app summary, got %d", len(report.Apps))
+	}
+	summary := report.Apps[0]
+	if summary.Count != 4 || summary.PreviousCount != 1 {
+		t.Errorf("Expected 4 reviews and 1 previous, got %d and %d", summary.Count, summary.PreviousCount)
+	}
+	if summary.Average != 3 || summary.AverageChange() != "-2.00" {
+		t.Errorf("Expected average 3 (-2.00), got %v (%s)", summary.Average, summary.AverageChange())
+	}
+	if summary.Histogram[0].Rating != 5 || summary.Histogram[0].Count != 1 || summary.Histogram[0].Percent != 25 {
+		t.Errorf("Unexpected 5-star bar: %+v", summary.Histogram[0])
+	}
+	if len(summary.TopNegative) != 2 || summary.TopNegative[0].ID != "1" {
+		t.Errorf("Expected the 1-star review first in negatives, got %+v", summary.TopNegative)
+	}
+	if len(summary.TopPositive) != 2 || summary.TopPositive[0].ID != "3" {
+		t.Errorf("Expected the 5-star review first in positives, got %+v", summary.TopPositive)
+	}
+}
+
+// TestBuild_LeavesOutFlaggedReviews verifies that reviews flagged as spam count nowhere in the report
+func TestBuild_LeavesOutFlaggedReviews(t *testing.T) {
+	end := PeriodEnd(Daily, time.Now().UTC().Hour(), time.Now())
+	source := createTestSource(end)
+	source.reviews["test-app"] = append(source.reviews["test-app"], models.AppStoreReview{
+		ID: "spam", Title: "Scam", Content: "Visit http://spam.example for free coins now", Author: "Spammer", Rating: 1,
+		UpdatedAt: end.Add(-5 * time.Hour), Flags: []models.ReviewFlag{{Reason: models.FlagLink}},
+	})
+
+	summary := Build(source, Daily, end, time.Now()).Apps[0]
+
+	if summary.Count != 4 || summary.Average != 3 {
+		t.Errorf("Expected 4 reviews averaging 3 without the flagged one, got %d averaging %v", summary.Count, summary.Average)
+	}
+	for _, review := range summary.TopNegative {
+		if review.ID == "spam" {
+			t.Error("Expected the flagged review not to be highlighted")
+		}
+	}
+}
+
+// TestSendDue_SendsDigestOverSMTP verifies the message received by an SMTP server and that it is sent once per period
+func TestSendDue_SendsDigestOverSMTP(t *testing.T) {
+	stub := startSMTPStub(t)
+	filePath := filepath.Join(t.TempDir(), "digests.json")
+	now := time.Now()
+	end := PeriodEnd(Daily, now.UTC().Hour(), now)
+	recipients := []string{"pm@example.com", "lead@example.com"}
+
+	job := New(createTestSource(end), stub.mailer(), repositories.LoadDigests(filePath), recipients, []string{Daily}, now.UTC().Hour())
+	job.SendDue()
+	job.SendDue()
+
+	messages := stub.received()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	if len(messages[0].to) != 2 || messages[0].to[0] != "pm@example.com" {
+		t.Errorf("Expected both recipients, got %v", messages[0].to)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(messages[0].data)))
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if !strings.HasPrefix(subject, "Daily reviews digest") {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative, got %s", mediaType)
+	}
+	parts := make(map[string]string)
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart() // decodes quoted-printable
+		if err != nil {
+			break
+		}
+		content, _ := io.ReadAll(part)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(content)
+	}
+
+	for _, expected := range []string{"Reviews: 4 (previous period: 1)", "Average rating: 3.00 (-2.00)", "★☆☆☆☆", "Crashes (by User1)", "Best app <ever>"} {
+		if !strings.Contains(parts["text/plain"], expected) {
+			t.Errorf("Expected text part to contain %q, got:\n%s", expected, parts["text/plain"])
+		}
+	}
+	if !strings.Contains(parts["text/html"], "Best app &lt;ever&gt;") {
+		t.Errorf("Expected escaped review content in the HTML part, got:\n%s", parts["text/html"])
+	}
+	if strings.Contains(parts["text/plain"], "After the period") {
+		t.Error("Expected reviews after the period to be left out")
+	}
+
+	restarted := New(createTestSource(end), stub.mailer(), repositories.LoadDigests(filePath), recipients, []string{Daily}, now.UTC().Hour())
+	restarted.SendDue()
+	if len(stub.received()) != 1 {
+		t.Errorf("Expected no digest to be sent again after restart, got %d messages", len(stub.received()))
+	}
+}
+
+// failingMailer fails every send
+type failingMailer struct {
+	attempts int
+}
+
+func (m *failingMailer) Send(to []string, subject, text, html string) error {
+	m.attempts++
+	return errors.New("connection refused")
+}
+
+// TestSendDue_RetriesFailedDigests verifies that a digest that failed to send is not marked as sent
+func TestSendDue_RetriesFailedDigests(t *testing.T) {
+	mailer := &failingMailer{}
+	job := New(&fakeSource{}, mailer, repositories.LoadDigests(""), []string{"pm@example.com"}, []string{Daily, Weekly}, 8)
+
+	job.SendDue()
+	job.SendDue()
+
+	if mailer.attempts != 4 {
+		t.Errorf("Expected both digests to be attempted on every call, got %d attempts", mailer.attempts)
+	}
+}
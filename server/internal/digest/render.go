@@ -0,0 +1,39 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/textfmt"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+var templateFuncs = map[string]any{
+	"stars":   textfmt.Stars,
+	"excerpt": textfmt.Excerpt,
+	"bar": func(percent int) string {
+		return strings.Repeat("#", percent/5)
+	},
+}
+
+var (
+	textTemplate = texttemplate.Must(texttemplate.New("digest.txt.tmpl").Funcs(templateFuncs).ParseFS(templatesFS, "templates/digest.txt.tmpl"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").Funcs(templateFuncs).ParseFS(templatesFS, "templates/digest.html.tmpl"))
+)
+
+// Render renders the report as plain text and HTML
+func Render(report Report) (text string, html string, err error) {
+	var textBuf, htmlBuf bytes.Buffer
+	if err := textTemplate.Execute(&textBuf, report); err != nil {
+		return "", "", err
+	}
+	if err := htmlTemplate.Execute(&htmlBuf, report); err != nil {
+		return "", "", err
+	}
+	return textBuf.String(), htmlBuf.String(), nil
+}
@@ -0,0 +1,184 @@
+package digest
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// Digest schedules
+const (
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+// topReviews is the number of negative and positive reviews highlighted per app
+const topReviews = 3
+
+// ReviewSource gives the digest access to the stored reviews, implemented by the app service
+type ReviewSource interface {
+	QueryReviews(appID string, hours int, filter repositories.ReviewFilter, sortBy string) []models.AppStoreReview
+	TrackedAppIDs() []string
+}
+
+// Report is the content of a digest, one summary per tracked app
+type Report struct {
+	Schedule string
+	From     time.Time
+	To       time.Time
+	Apps     []AppSummary
+}
+
+// AppSummary summarizes the reviews of an app over the period of a Report
+type AppSummary struct {
+	AppID           string
+	Count           int
+	Average         float64 // zero when there are no reviews
+	PreviousCount   int     // reviews of the period before
+	PreviousAverage float64
+	Histogram       []RatingCount // 5 stars first
+	TopNegative     []models.AppStoreReview
+	TopPositive     []models.AppStoreReview
+}
+
+// RatingCount is a histogram bar
+type RatingCount struct {
+	Rating  int
+	Count   int
+	Percent int
+}
+
+// AverageChange describes the average rating compared to the previous period
+func (s AppSummary) AverageChange() string {
+	if s.Count == 0 || s.PreviousCount == 0 {
+		return "no comparison"
+	}
+	delta := s.Average - s.PreviousAverage
+	if math.Abs(delta) < 0.005 {
+		return "unchanged"
+	}
+	return fmt.Sprintf("%+.2f", delta)
+}
+
+// Title is the subject of the digest email
+func (r Report) Title() string {
+	name := "Daily"
+	if r.Schedule == Weekly {
+		name = "Weekly"
+	}
+	return fmt.Sprintf("%s reviews digest: %s to %s", name, r.From.Format("Jan 2 15:04"), r.To.Format("Jan 2 15:04 MST"))
+}
+
+// PeriodLength returns the length of the period covered by schedule
+func PeriodLength(schedule string) time.Duration {
+	if schedule == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// PeriodEnd returns the end of the last period of schedule completed at now.
+// Periods end at hour UTC, weekly ones on Mondays.
+func PeriodEnd(schedule string, hour int, now time.Time) time.Time {
+	now = now.UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if end.After(now) {
+		end = end.AddDate(0, 0, -1)
+	}
+	if schedule == Weekly {
+		for end.Weekday() != time.Monday {
+			end = end.AddDate(0, 0, -1)
+		}
+	}
+	return end
+}
+
+// Build summarizes the reviews of every tracked app for the period of schedule ending at end
+func Build(source ReviewSource, schedule string, end time.Time, now time.Time) Report {
+	length := PeriodLength(schedule)
+	start := end.Add(-length)
+	previousStart := start.Add(-length)
+
+	report := Report{Schedule: schedule, From: start, To: end}
+
+	// the source lists reviews relative to now, going back to the start of the previous period.
+	// Reviews flagged as spam are left out, as they are from the statistics.
+	hours := int(math.Ceil(now.Sub(previousStart).Hours()))
+	unflagged := false
+	filter := repositories.ReviewFilter{Flagged: &unflagged}
+	for _, appID := range source.TrackedAppIDs() {
+		var current, previous []models.AppStoreReview
+		for _, review := range source.QueryReviews(appID, hours, filter, models.SortNewest) {
+			switch {
+			case !review.UpdatedAt.Before(start) && review.UpdatedAt.Before(end):
+				current = append(current, review)
+			case !review.UpdatedAt.Before(previousStart) && review.UpdatedAt.Before(start):
+				previous = append(previous, review)
+			}
+		}
+		report.Apps = append(report.Apps, summarize(appID, current, previous))
+	}
+
+	return report
+}
+
+func summarize(appID string, current, previous []models.AppStoreReview) AppSummary {
+	summary := AppSummary{
+		AppID:           appID,
+		Count:           len(current),
+		Average:         average(current),
+		PreviousCount:   len(previous),
+		PreviousAverage: average(previous),
+	}
+
+	counts := make(map[int]int)
+	for _, review := range current {
+		counts[review.Rating]++
+	}
+	for rating := 5; rating >= 1; rating-- {
+		bar := RatingCount{Rating: rating, Count: counts[rating]}
+		if len(current) > 0 {
+			bar.Percent = int(math.Round(float64(bar.Count) * 100 / float64(len(current))))
+		}
+		summary.Histogram = append(summary.Histogram, bar)
+	}
+
+	// the most detailed reviews of the lowest and highest ratings
+	byDetail := func(a, b models.AppStoreReview) int {
+		return cmp.Compare(len(b.Content), len(a.Content))
+	}
+	var negative, positive []models.AppStoreReview
+	for _, review := range current {
+		if review.Rating <= 2 {
+			negative = append(negative, review)
+		} else if review.Rating >= 4 {
+			positive = append(positive, review)
+		}
+	}
+	slices.SortStableFunc(negative, func(a, b models.AppStoreReview) int {
+		return cmp.Or(cmp.Compare(a.Rating, b.Rating), byDetail(a, b))
+	})
+	slices.SortStableFunc(positive, func(a, b models.AppStoreReview) int {
+		return cmp.Or(cmp.Compare(b.Rating, a.Rating), byDetail(a, b))
+	})
+	summary.TopNegative = negative[:min(topReviews, len(negative))]
+	summary.TopPositive = positive[:min(topReviews, len(positive))]
+
+	return summary
+}
+
+func average(reviews []models.AppStoreReview) float64 {
+	if len(reviews) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, review := range reviews {
+		sum += review.Rating
+	}
+	return float64(sum) / float64(len(reviews))
+}
@@ -0,0 +1,167 @@
+package events
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// ReviewEvent is published for every review stored by the app service
+type ReviewEvent struct {
+	ID          uint64 // increases by one on every event, and across restarts, used to resume streams
+	AppID       string
+	Review      models.AppStoreReview
+	PublishedAt time.Time
+}
+
+// Filter selects the events a subscriber receives
+type Filter struct {
+	AppID  string
+	Rating *int
+}
+
+func (f Filter) matches(event ReviewEvent) bool {
+	if f.AppID != "" && event.AppID != f.AppID {
+		return false
+	}
+	return f.Rating == nil || event.Review.Rating == *f.Rating
+}
+
+// Subscription receives the events published after it was created
+type Subscription struct {
+	C      <-chan ReviewEvent // closed when the subscription ends, including when it falls too far behind
+	ch     chan ReviewEvent
+	filter Filter
+}
+
+// BatchListener is called with every batch of newly stored reviews of an app
+type BatchListener func(appID string, reviews []models.AppStoreReview)
+
+// Hub is an in-process pub/sub of newly stored reviews that keeps a bounded history for resuming
+type Hub struct {
+	mu            sync.Mutex
+	lastID        uint64
+	resumableID   uint64        // the events after it are all in the history, resuming from an older ID misses some
+	history       []ReviewEvent // oldest first, at most historySize events
+	historySize   int
+	subscribers   map[*Subscription]struct{}
+	subscriberBuf int
+	listeners     []BatchListener
+}
+
+// NewHub creates a Hub keeping the last historySize events.
+// Subscribers that fall more than subscriberBuf events behind are dropped.
+func NewHub(historySize, subscriberBuf int) *Hub {
+	// IDs start from the boot time in microseconds, above the IDs of previous runs publishing less than an event
+	// per microsecond, and below 2^53 so JavaScript clients keep them exact
+	firstID := uint64(time.Now().UnixMicro())
+	return &Hub{
+		lastID:        firstID,
+		resumableID:   firstID,
+		historySize:   historySize,
+		subscribers:   make(map[*Subscription]struct{}),
+		subscriberBuf: subscriberBuf,
+	}
+}
+
+// AddBatchListener registers a listener called synchronously by Publish, after the subscribers.
+// Listeners must return quickly since they run in the publisher goroutine.
+func (h *Hub) AddBatchListener(listener BatchListener) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listeners = append(h.listeners, listener)
+}
+
+// Publish sends every review to the matching subscribers, records it in the history
+// and hands the whole batch to the batch listeners
+func (h *Hub) Publish(appID string, reviews []models.AppStoreReview) {
+	if len(reviews) == 0 {
+		return
+	}
+
+	listeners := h.publish(appID, reviews)
+	for _, listener := range listeners {
+		listener(appID, reviews)
+	}
+}
+
+func (h *Hub) publish(appID string, reviews []models.AppStoreReview) []BatchListener {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, review := range reviews {
+		h.lastID++
+		event := ReviewEvent{ID: h.lastID, AppID: appID, Review: review, PublishedAt: now}
+
+		h.history = append(h.history, event)
+		if len(h.history) > h.historySize {
+			h.history = h.history[len(h.history)-h.historySize:]
+			h.resumableID = h.history[0].ID - 1
+		}
+
+		for sub := range h.subscribers {
+			if !sub.filter.matches(event) {
+				continue
+			}
+			select {
+			case sub.ch <- event:
+			default:
+				// slow subscriber, drop it instead of blocking publishers. It can resume from its last event ID
+				h.unsubscribeLocked(sub)
+			}
+		}
+	}
+
+	return slices.Clone(h.listeners)
+}
+
+// Subscribe registers a subscriber for the events matching filter.
+// When afterID is not zero, the events after it are returned to be replayed first. When some of them are no longer
+// in the history, or afterID is unknown, like an ID of a previous run, nothing is replayed and resetID is set to
+// the ID of the last event: the subscriber missed events, it should reload what it shows and resume from resetID.
+func (h *Hub) Subscribe(filter Filter, afterID uint64) (sub *Subscription, missed []ReviewEvent, resetID uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan ReviewEvent, h.subscriberBuf)
+	sub = &Subscription{C: ch, ch: ch, filter: filter}
+	h.subscribers[sub] = struct{}{}
+
+	if afterID == 0 {
+		return sub, nil, 0
+	}
+	if afterID < h.resumableID || afterID > h.lastID {
+		return sub, nil, h.lastID
+	}
+	for _, event := range h.history {
+		if event.ID > afterID && filter.matches(event) {
+			missed = append(missed, event)
+		}
+	}
+	return sub, missed, 0
+}
+
+// Unsubscribe ends the subscription and closes its channel, it is safe to call more than once
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unsubscribeLocked(sub)
+}
+
+func (h *Hub) unsubscribeLocked(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.ch)
+}
+
+// LastID returns the ID of the last published event, the ID events start after if nothing was published
+func (h *Hub) LastID() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastID
+}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+func createTestReviews(ratings ...int) []models.AppStoreReview {
+	reviews := make([]models.AppStoreReview, 0, len(ratings))
+	for i, rating := range ratings {
+		reviews = append(reviews, models.AppStoreReview{
+			ID:        "review-" + string(rune('a'+i)),
+			Rating:    rating,
+			UpdatedAt: time.Now().UTC(),
+		})
+	}
+	return reviews
+}
+
+// receive reads the next event or fails after a short timeout
+func receive(t *testing.T, sub *Subscription) ReviewEvent {
+	t.Helper()
+	select {
+	case event, ok := <-sub.C:
+		if !ok {
+			t.Fatal("Expected an event, subscription channel was closed")
+		}
+		return event
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Expected an event, got none")
+	}
+	return ReviewEvent{}
+}
+
+// TestPublish_DeliversMatchingEvents verifies that subscribers only receive events matching their filter
+func TestPublish_DeliversMatchingEvents(t *testing.T) {
+	hub := NewHub(10, 10)
+	start := hub.LastID()
+	oneStar := 1
+	all, _, _ := hub.Subscribe(Filter{}, 0)
+	onlyOneStar, _, _ := hub.Subscribe(Filter{Rating: &oneStar}, 0)
+	otherApp, _, _ := hub.Subscribe(Filter{AppID: "other-app"}, 0)
+
+	hub.Publish("test-app", createTestReviews(5, 1))
+
+	if event := receive(t, all); event.ID != start+1 || event.Review.Rating != 5 {
+		t.Errorf("Expected first event with ID %d and rating 5, got %+v", start+1, event)
+	}
+	if event := receive(t, all); event.ID != start+2 || event.AppID != "test-app" {
+		t.Errorf("Expected second event with ID %d for test-app, got %+v", start+2, event)
+	}
+	if event := receive(t, onlyOneStar); event.Review.Rating != 1 {
+		t.Errorf("Expected 1-star event, got rating %d", event.Review.Rating)
+	}
+	if len(onlyOneStar.C) != 0 || len(otherApp.C) != 0 {
+		t.Error("Expected filtered subscribers to receive no other events")
+	}
+}
+
+// TestSubscribe_ReplaysHistoryAfterID verifies resuming from a previous event ID
+func TestSubscribe_ReplaysHistoryAfterID(t *testing.T) {
+	hub := NewHub(3, 10)
+	start := hub.LastID()
+	hub.Publish("test-app", createTestReviews(5, 4, 3, 2, 1))
+
+	_, missed, resetID := hub.Subscribe(Filter{}, start+3)
+	if len(missed) != 2 || missed[0].ID != start+4 || missed[1].ID != start+5 || resetID != 0 {
+		t.Errorf("Expected events 4 and 5 to be replayed, got %+v and reset %d", missed, resetID)
+	}
+
+	// every event after 2 is still in history
+	_, missed, resetID = hub.Subscribe(Filter{}, start+2)
+	if len(missed) != 3 || missed[0].ID != start+3 || resetID != 0 {
+		t.Errorf("Expected the 3 events in history to be replayed, got %+v and reset %d", missed, resetID)
+	}
+
+	_, missed, resetID = hub.Subscribe(Filter{}, 0)
+	if len(missed) != 0 || resetID != 0 {
+		t.Errorf("Expected no replay without a last event ID, got %d events and reset %d", len(missed), resetID)
+	}
+}
+
+// TestSubscribe_ResetsUnknownIDs verifies that subscribers resuming from an event no longer in history,
+// or from a previous run, get a reset instead of a partial replay
+func TestSubscribe_ResetsUnknownIDs(t *testing.T) {
+	previousRun := NewHub(3, 10)
+	previousRun.Publish("test-app", createTestReviews(5, 4))
+	time.Sleep(time.Millisecond) // the next run boots later
+
+	hub := NewHub(3, 10)
+	if hub.LastID() <= previousRun.LastID() {
+		t.Fatalf("Expected IDs to keep increasing across runs, got %d after %d", hub.LastID(), previousRun.LastID())
+	}
+	start := hub.LastID()
+	hub.Publish("test-app", createTestReviews(5, 4, 3, 2, 1))
+
+	tests := []struct {
+		name    string
+		afterID uint64
+	}{
+		{"event 2 fell out of history", start + 1},
+		{"previous run", previousRun.LastID()},
+		{"future event", start + 6},
+	}
+	for _, tt := range tests {
+		_, missed, resetID := hub.Subscribe(Filter{}, tt.afterID)
+		if len(missed) != 0 || resetID != start+5 {
+			t.Errorf("%s: expected a reset to event %d without replay, got %d events and reset %d", tt.name, start+5, len(missed), resetID)
+		}
+	}
+}
+
+// TestPublish_DropsSlowSubscribers verifies that a full subscriber is dropped instead of blocking the publisher
+func TestPublish_DropsSlowSubscribers(t *testing.T) {
+	hub := NewHub(10, 1)
+	sub, _, _ := hub.Subscribe(Filter{}, 0)
+
+	hub.Publish("test-app", createTestReviews(5, 4, 3))
+
+	receive(t, sub)
+	if _, ok := <-sub.C; ok {
+		t.Error("Expected slow subscriber channel to be closed")
+	}
+
+	// unsubscribing a dropped subscriber must not panic
+	hub.Unsubscribe(sub)
+}
+
+// TestUnsubscribe_ClosesChannel verifies that unsubscribed subscribers stop receiving events
+func TestUnsubscribe_ClosesChannel(t *testing.T) {
+	hub := NewHub(10, 10)
+	start := hub.LastID()
+	sub, _, _ := hub.Subscribe(Filter{}, 0)
+
+	hub.Unsubscribe(sub)
+	hub.Publish("test-app", createTestReviews(5))
+
+	if _, ok := <-sub.C; ok {
+		t.Error("Expected channel to be closed after unsubscribe")
+	}
+	if hub.LastID() != start+1 {
+		t.Errorf("Expected last ID %d, got %d", start+1, hub.LastID())
+	}
+}
@@ -0,0 +1,187 @@
+// Package insights extracts trends from the text of reviews
+package insights
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/language"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// Bucket sizes of the keyword trends
+const (
+	BucketHour = "hour"
+	BucketDay  = "day"
+	BucketWeek = "week"
+)
+
+// MaxNGram is the longest phrase, in words, extracted as a term
+const MaxNGram = 3
+
+// KeywordOptions selects the period and terms of a keyword report
+type KeywordOptions struct {
+	From     time.Time // start of the current period, the previous period has the same length and ends at From
+	To       time.Time
+	Bucket   string // size of the buckets splitting the current period
+	MaxN     int    // longest phrase extracted, from 1 to MaxNGram
+	Limit    int    // number of terms returned in each list
+	MinCount int    // reviews of the current period a term needs to be reported
+}
+
+// KeywordReport lists the most frequent and the fastest rising terms of a period
+type KeywordReport struct {
+	From                time.Time   `json:"from"`
+	To                  time.Time   `json:"to"`
+	PreviousFrom        time.Time   `json:"previousFrom"`
+	Bucket              string      `json:"bucket"`
+	Buckets             []time.Time `json:"buckets"` // start of each bucket of the current period
+	ReviewCount         int         `json:"reviewCount"`
+	PreviousReviewCount int         `json:"previousReviewCount"`
+	Top                 []TermTrend `json:"top"`    // most frequent terms of the current period
+	Rising              []TermTrend `json:"rising"` // terms with the highest surge
+}
+
+// TermTrend is the frequency of a term, counted in reviews mentioning it
+type TermTrend struct {
+	Term          string `json:"term"`
+	Words         int    `json:"words"`
+	Count         int    `json:"count"`
+	PreviousCount int    `json:"previousCount"`
+	// Surge is the log2 ratio of the current and previous share of reviews mentioning the term, smoothed by one review.
+	// 1 means twice as frequent, negative values mean the term is falling.
+	Surge   float64 `json:"surge"`
+	Buckets []int   `json:"buckets"` // count per bucket of the current period
+}
+
+// BucketSize returns the duration of a bucket, zero for an unknown bucket
+func BucketSize(bucket string) time.Duration {
+	switch bucket {
+	case BucketHour:
+		return time.Hour
+	case BucketDay:
+		return 24 * time.Hour
+	case BucketWeek:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Keywords counts the terms of reviews in the current and previous periods of options.
+// Reviews outside both periods are ignored.
+func Keywords(reviews []models.AppStoreReview, options KeywordOptions) KeywordReport {
+	length := options.To.Sub(options.From)
+	bucketSize := BucketSize(options.Bucket)
+	report := KeywordReport{
+		From:         options.From,
+		To:           options.To,
+		PreviousFrom: options.From.Add(-length),
+		Bucket:       options.Bucket,
+		Top:          []TermTrend{},
+		Rising:       []TermTrend{},
+	}
+	for start := options.From; start.Before(options.To); start = start.Add(bucketSize) {
+		report.Buckets = append(report.Buckets, start)
+	}
+
+	terms := make(map[string]*TermTrend)
+	for _, review := range reviews {
+		current := !review.UpdatedAt.Before(options.From) && review.UpdatedAt.Before(options.To)
+		previous := !review.UpdatedAt.Before(report.PreviousFrom) && review.UpdatedAt.Before(options.From)
+		if !current && !previous {
+			continue
+		}
+
+		if current {
+			report.ReviewCount++
+		} else {
+			report.PreviousReviewCount++
+		}
+		bucket := int(review.UpdatedAt.Sub(options.From) / bucketSize)
+
+		for term, words := range ReviewTerms(review, options.MaxN) {
+			trend, ok := terms[term]
+			if !ok {
+				trend = &TermTrend{Term: term, Words: words, Buckets: make([]int, len(report.Buckets))}
+				terms[term] = trend
+			}
+			if current {
+				trend.Count++
+				trend.Buckets[bucket]++
+			} else {
+				trend.PreviousCount++
+			}
+		}
+	}
+
+	var candidates []TermTrend
+	for _, trend := range terms {
+		if trend.Count < options.MinCount {
+			continue
+		}
+		trend.Surge = surge(trend.Count, report.ReviewCount, trend.PreviousCount, report.PreviousReviewCount)
+		candidates = append(candidates, *trend)
+	}
+
+	// ties are broken by term so reports are stable
+	slices.SortFunc(candidates, func(a, b TermTrend) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(b.Words, a.Words), cmp.Compare(a.Term, b.Term))
+	})
+	report.Top = append(report.Top, candidates[:min(options.Limit, len(candidates))]...)
+
+	slices.SortFunc(candidates, func(a, b TermTrend) int {
+		return cmp.Or(cmp.Compare(b.Surge, a.Surge), cmp.Compare(b.Count, a.Count), cmp.Compare(a.Term, b.Term))
+	})
+	for _, trend := range candidates {
+		if len(report.Rising) == options.Limit || trend.Surge <= 0 {
+			break
+		}
+		report.Rising = append(report.Rising, trend)
+	}
+
+	return report
+}
+
+// surge compares the share of reviews mentioning a term in both periods, so a busier period doesn't make every term rise
+func surge(count, reviews, previousCount, previousReviews int) float64 {
+	currentShare := float64(count+1) / float64(reviews+1)
+	previousShare := float64(previousCount+1) / float64(previousReviews+1)
+	return math.Round(math.Log2(currentShare/previousShare)*1000) / 1000
+}
+
+// ReviewTerms returns the distinct terms of the title and content of a review with their word count.
+// Terms are phrases of 1 to maxN words within a sentence, not starting or ending with a stopword
+// of the review language.
+func ReviewTerms(review models.AppStoreReview, maxN int) map[string]int {
+	terms := make(map[string]int)
+	tokens := language.Tokenize(review.Title+".\n"+review.Content, review.Language)
+
+	for i := range tokens {
+		if !isKeyword(tokens[i], review.Language) {
+			continue
+		}
+		for n := 1; n <= maxN && i+n <= len(tokens); n++ {
+			last := tokens[i+n-1]
+			if last == "" {
+				break // phrases don't cross sentences
+			}
+			if isKeyword(last, review.Language) {
+				terms[strings.Join(tokens[i:i+n], " ")] = n
+			}
+		}
+	}
+	return terms
+}
+
+// isKeyword reports whether a token of a review in lang can start or end a term
+func isKeyword(token, lang string) bool {
+	if utf8.RuneCountInString(token) < 2 || language.IsStopword(lang, token) {
+		return false
+	}
+	// numbers alone say little
+	return strings.ContainsFunc(token, func(r rune) bool { return r < '0' || r > '9' })
+}
@@ -0,0 +1,142 @@
+package insights
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+var testFrom = time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+func reviewAt(day int, hour int, title, content string) models.AppStoreReview {
+	return models.AppStoreReview{Title: title, Content: content, UpdatedAt: testFrom.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour)}
+}
+
+func testOptions() KeywordOptions {
+	return KeywordOptions{From: testFrom, To: testFrom.AddDate(0, 0, 7), Bucket: BucketDay, MaxN: 2, Limit: 10, MinCount: 1}
+}
+
+func findTerm(terms []TermTrend, term string) *TermTrend {
+	for i := range terms {
+		if terms[i].Term == term {
+			return &terms[i]
+		}
+	}
+	return nil
+}
+
+// TestReviewTerms_SkipsStopwordsAndSentenceBoundaries verifies the extracted phrases of a review
+func TestReviewTerms_SkipsStopwordsAndSentenceBoundaries(t *testing.T) {
+	terms := ReviewTerms(models.AppStoreReview{Title: "Dark mode", Content: "Please add dark mode. Crash on login in version 2"}, 3)
+
+	for _, expected := range []string{"dark", "mode", "dark mode", "crash", "login", "please add dark", "crash on login", "version"} {
+		if _, ok := terms[expected]; !ok {
+			t.Errorf("Expected term %q, got %v", expected, terms)
+		}
+	}
+	for _, unexpected := range []string{"on", "in", "mode crash", "mode dark", "2", "version 2", "on login"} {
+		if _, ok := terms[unexpected]; ok {
+			t.Errorf("Expected no term %q", unexpected)
+		}
+	}
+	if terms["dark mode"] != 2 {
+		t.Errorf("Expected dark mode to have 2 words, got %d", terms["dark mode"])
+	}
+}
+
+// TestReviewTerms_UsesReviewLanguage verifies that the stopwords and elisions of the review language are applied
+func TestReviewTerms_UsesReviewLanguage(t *testing.T) {
+	spanish := ReviewTerms(models.AppStoreReview{Title: "No funciona", Content: "La aplicación no abre desde la actualización", Language: "es"}, 2)
+	for _, expected := range []string{"funciona", "abre", "actualización"} {
+		if _, ok := spanish[expected]; !ok {
+			t.Errorf("Expected term %q, got %v", expected, spanish)
+		}
+	}
+	for _, unexpected := range []string{"no", "la", "aplicación", "desde"} {
+		if _, ok := spanish[unexpected]; ok {
+			t.Errorf("Expected no Spanish stopword %q", unexpected)
+		}
+	}
+
+	french := ReviewTerms(models.AppStoreReview{Content: "L'écran reste noir", Language: "fr"}, 1)
+	if _, ok := french["écran"]; !ok {
+		t.Errorf("Expected elision to be dropped, got %v", french)
+	}
+}
+
+// TestKeywords_CountsReviewsPerPeriodAndBucket verifies counts per period and bucket, each review counting a term once
+func TestKeywords_CountsReviewsPerPeriodAndBucket(t *testing.T) {
+	reviews := []models.AppStoreReview{
+		reviewAt(0, 1, "Crash", "crash crash crash"),
+		reviewAt(0, 5, "Crashes", "It crash on start"),
+		reviewAt(3, 0, "Subscription", "Crash after the subscription renewal"),
+		reviewAt(-2, 0, "Old", "Crash before"),
+		reviewAt(-10, 0, "Too old", "Crash long ago"),
+		reviewAt(8, 0, "Future", "Crash later"),
+	}
+
+	report := Keywords(reviews, testOptions())
+
+	if report.ReviewCount != 3 || report.PreviousReviewCount != 1 {
+		t.Fatalf("Expected 3 current and 1 previous review, got %d and %d", report.ReviewCount, report.PreviousReviewCount)
+	}
+	if len(report.Buckets) != 7 {
+		t.Errorf("Expected 7 daily buckets, got %d", len(report.Buckets))
+	}
+
+	crash := findTerm(report.Top, "crash")
+	if crash == nil || crash.Count != 3 || crash.PreviousCount != 1 {
+		t.Fatalf("Expected crash in 3 reviews and 1 previous, got %+v", crash)
+	}
+	if crash.Buckets[0] != 2 || crash.Buckets[3] != 1 {
+		t.Errorf("Expected crash in 2 reviews on day 0 and 1 on day 3, got %v", crash.Buckets)
+	}
+	if report.Top[0].Term != "crash" {
+		t.Errorf("Expected crash to be the top term, got %s", report.Top[0].Term)
+	}
+}
+
+// TestKeywords_RisingTermsBySurge verifies that new terms rise and stable ones don't
+func TestKeywords_RisingTermsBySurge(t *testing.T) {
+	var reviews []models.AppStoreReview
+	for day := 0; day < 4; day++ {
+		reviews = append(reviews,
+			reviewAt(day, 0, "Dark mode", "Needs dark mode"),
+			reviewAt(day, 1, "Login", "Login works"),
+			reviewAt(day-7, 1, "Login", "Login works"),
+		)
+	}
+	options := testOptions()
+	options.MinCount = 2
+
+	report := Keywords(reviews, options)
+
+	darkMode := findTerm(report.Rising, "dark mode")
+	if darkMode == nil || darkMode.Surge <= 1 {
+		t.Fatalf("Expected dark mode to be rising, got %+v", report.Rising)
+	}
+	if findTerm(report.Rising, "login") != nil {
+		t.Error("Expected login, as frequent as before, not to be rising")
+	}
+	if login := findTerm(report.Top, "login"); login == nil || login.Surge >= 0 {
+		t.Errorf("Expected login to have a non positive surge, got %+v", login)
+	}
+}
+
+// TestKeywords_AppliesMinCountAndLimit verifies that rare terms are dropped and lists are capped
+func TestKeywords_AppliesMinCountAndLimit(t *testing.T) {
+	reviews := []models.AppStoreReview{
+		reviewAt(0, 0, "Battery", "battery drain"),
+		reviewAt(1, 0, "Battery", "battery notifications"),
+	}
+	options := testOptions()
+	options.MinCount = 2
+	options.Limit = 1
+
+	report := Keywords(reviews, options)
+
+	if len(report.Top) != 1 || report.Top[0].Term != "battery" {
+		t.Errorf("Expected only battery, got %+v", report.Top)
+	}
+}
@@ -0,0 +1,155 @@
+// Package language identifies the language of reviews offline, from character n-gram profiles,
+// and splits text into words with the stopwords of each language
+package language
+
+import (
+	"embed"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// Language codes (ISO 639-1) of the languages with a profile
+const (
+	English    = "en"
+	Spanish    = "es"
+	Portuguese = "pt"
+	French     = "fr"
+	German     = "de"
+	Italian    = "it"
+)
+
+// Undetermined (ISO 639-2) is the language of texts too short or in a language without a profile
+const Undetermined = "und"
+
+// Supported lists the languages Detect can identify
+var Supported = []string{English, Spanish, Portuguese, French, German, Italian}
+
+const (
+	ngramSize   = 3
+	minLetters  = 6   // shorter texts are undetermined
+	minCoverage = 0.5 // share of the n-grams of a text the best profile must have seen
+)
+
+//go:embed profiles/*.txt
+var profileFiles embed.FS
+
+// profile holds the n-gram log probabilities of a language, learned from a sample text
+type profile struct {
+	lang     string
+	logProbs map[string]float64
+	unseen   float64 // log probability of an n-gram missing from the sample
+}
+
+var profiles = loadProfiles()
+
+func loadProfiles() []profile {
+	counts := make([]map[string]int, len(Supported))
+	vocabulary := make(map[string]bool)
+	for i, lang := range Supported {
+		data, err := profileFiles.ReadFile(path.Join("profiles", lang+".txt"))
+		if err != nil {
+			panic(err) // embedded, only missing if the build is broken
+		}
+		counts[i] = make(map[string]int)
+		for _, gram := range ngrams(string(data)) {
+			counts[i][gram]++
+			vocabulary[gram] = true
+		}
+	}
+
+	// add-one smoothing over the n-grams of every profile
+	result := make([]profile, len(Supported))
+	for i, lang := range Supported {
+		total := 0
+		for _, count := range counts[i] {
+			total += count
+		}
+		denominator := float64(total + len(vocabulary))
+
+		result[i] = profile{lang: lang, logProbs: make(map[string]float64, len(counts[i])), unseen: math.Log(1 / denominator)}
+		for gram, count := range counts[i] {
+			result[i].logProbs[gram] = math.Log(float64(count+1) / denominator)
+		}
+	}
+	return result
+}
+
+// ngrams returns the character n-grams of the words of text, padded with a space on both sides
+// so word beginnings and endings count
+func ngrams(text string) []string {
+	var grams []string
+	for _, token := range Tokenize(text, "") {
+		if token == "" || !strings.ContainsFunc(token, unicode.IsLetter) {
+			continue
+		}
+		runes := []rune(" " + token + " ")
+		if len(runes) < ngramSize {
+			continue
+		}
+		for i := 0; i+ngramSize <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+ngramSize]))
+		}
+	}
+	return grams
+}
+
+// Detect returns the most likely language of text, Undetermined when the text is too short
+// or doesn't look like any of the Supported languages
+func Detect(text string) string {
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < minLetters {
+		return Undetermined
+	}
+
+	grams := ngrams(text)
+	best, bestScore, bestSeen := Undetermined, math.Inf(-1), 0
+	for _, p := range profiles {
+		score, seen := 0.0, 0
+		for _, gram := range grams {
+			if logProb, ok := p.logProbs[gram]; ok {
+				score += logProb
+				seen++
+			} else {
+				score += p.unseen
+			}
+		}
+		if score > bestScore {
+			best, bestScore, bestSeen = p.lang, score, seen
+		}
+	}
+
+	// texts in other languages, or other scripts, share few n-grams with every profile
+	if len(grams) == 0 || float64(bestSeen)/float64(len(grams)) < minCoverage {
+		return Undetermined
+	}
+	return best
+}
+
+// Annotate detects the language of the title and content of review
+func Annotate(review *models.AppStoreReview) {
+	review.Language = Detect(review.Title + "\n" + review.Content)
+}
+
+// AnnotateMissing detects the language of reviews stored before detection existed, reporting whether it did
+func AnnotateMissing(review *models.AppStoreReview) bool {
+	if review.Language != "" {
+		return false
+	}
+	Annotate(review)
+	return true
+}
+
+// IsCode reports whether code is a language Detect returns, Undetermined included
+func IsCode(code string) bool {
+	return code == Undetermined || slices.Contains(Supported, code)
+}
@@ -0,0 +1,49 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// TestDetect_SupportedLanguages verifies short reviews, absent from the profile samples, in each language
+func TestDetect_SupportedLanguages(t *testing.T) {
+	tests := map[string][]string{
+		English:    {"Keeps crashing after update", "Worst app ever, do not download", "Awesome game, my kids play it all day"},
+		Spanish:    {"Muy buena app", "Se cierra cada vez que la abro", "Pésima atención al cliente"},
+		Portuguese: {"Não serve pra nada", "Fecha toda vez que eu abro", "Péssimo atendimento"},
+		French:     {"Super appli", "Elle plante à chaque ouverture", "Je ne vois plus mes commandes"},
+		German:     {"Tolle App", "Stürzt jedes Mal beim Öffnen ab", "Miserabler Kundenservice"},
+		Italian:    {"Ottima app", "Si chiude ogni volta che la apro", "Non vedo più i miei ordini"},
+	}
+
+	for expected, texts := range tests {
+		for _, text := range texts {
+			if lang := Detect(text); lang != expected {
+				t.Errorf("Expected %q to be %s, got %s", text, expected, lang)
+			}
+		}
+	}
+}
+
+// TestDetect_Undetermined verifies that short texts and other scripts are not guessed
+func TestDetect_Undetermined(t *testing.T) {
+	for _, text := range []string{"", "ok", "5/5 👍👍👍", "とても良いアプリです", "Очень хорошее приложение"} {
+		if lang := Detect(text); lang != Undetermined {
+			t.Errorf("Expected %q to be undetermined, got %s", text, lang)
+		}
+	}
+}
+
+// TestAnnotateMissing verifies that only reviews without a language are detected
+func TestAnnotateMissing(t *testing.T) {
+	review := models.AppStoreReview{Title: "Muy buena", Content: "Me encanta esta aplicación"}
+	if !AnnotateMissing(&review) || review.Language != Spanish {
+		t.Fatalf("Expected language to be detected, got %q", review.Language)
+	}
+
+	review.Language = Undetermined
+	if AnnotateMissing(&review) || review.Language != Undetermined {
+		t.Errorf("Expected detected language to be kept, got %q", review.Language)
+	}
+}
@@ -0,0 +1,112 @@
+package language
+
+import (
+	"embed"
+	"path"
+	"strings"
+	"unicode"
+)
+
+//go:embed stopwords/*.txt
+var stopwordFiles embed.FS
+
+var stopwords = loadStopwords()
+
+func loadStopwords() map[string]map[string]bool {
+	result := make(map[string]map[string]bool, len(Supported))
+	for _, lang := range Supported {
+		data, err := stopwordFiles.ReadFile(path.Join("stopwords", lang+".txt"))
+		if err != nil {
+			panic(err) // embedded, only missing if the build is broken
+		}
+		words := make(map[string]bool)
+		for _, word := range strings.Fields(string(data)) {
+			words[word] = true
+		}
+		result[lang] = words
+	}
+	return result
+}
+
+// elisions are the words whose vowel is replaced by an apostrophe before a vowel, as in "l'application"
+var elisions = map[string]map[string]bool{
+	French:  {"l": true, "d": true, "j": true, "m": true, "n": true, "s": true, "t": true, "c": true, "qu": true, "jusqu": true, "lorsqu": true, "puisqu": true},
+	Italian: {"l": true, "d": true, "c": true, "m": true, "s": true, "t": true, "v": true, "un": true, "all": true, "dall": true, "dell": true, "nell": true, "sull": true, "quest": true, "quell": true},
+}
+
+// Tokenize lowercases text and splits it into words, keeping apostrophes inside words.
+// Sentence ends are kept as empty tokens. French and Italian elisions are dropped,
+// so "l'application" gives "application", English contractions are kept whole.
+func Tokenize(text, lang string) []string {
+	var tokens []string
+	var word strings.Builder
+
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, stripElision(strings.Trim(word.String(), "'"), lang))
+			word.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		case r == '\'' || r == '’':
+			word.WriteRune('\'')
+		case r == '.' || r == '!' || r == '?' || r == ';' || r == '\n':
+			flush()
+			if len(tokens) > 0 && tokens[len(tokens)-1] != "" {
+				tokens = append(tokens, "")
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func stripElision(token, lang string) string {
+	prefix, rest, found := strings.Cut(token, "'")
+	if found && rest != "" && elisions[lang][prefix] {
+		return rest
+	}
+	return token
+}
+
+// IsStopword reports whether word is too common in lang to say anything about a review.
+// Undetermined and unknown languages use the English stopwords.
+func IsStopword(lang, word string) bool {
+	words, ok := stopwords[lang]
+	if !ok {
+		words = stopwords[English]
+	}
+	return words[word]
+}
+
+var diacritics = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ß", "ss", "œ", "oe", "æ", "ae",
+)
+
+// Fold removes the diacritics of a lowercase word, so "aplicación" and "aplicacion" compare equal
+func Fold(word string) string {
+	return diacritics.Replace(word)
+}
+
+// SearchTerms returns the folded words of text tokenized for lang, without sentence ends
+func SearchTerms(text, lang string) []string {
+	var terms []string
+	for _, token := range Tokenize(text, lang) {
+		if token != "" {
+			terms = append(terms, Fold(token))
+		}
+	}
+	return terms
+}
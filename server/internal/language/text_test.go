@@ -0,0 +1,59 @@
+package language
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestTokenize_Elisions verifies that French and Italian elisions are split off while English contractions stay whole
+func TestTokenize_Elisions(t *testing.T) {
+	tests := []struct {
+		text     string
+		lang     string
+		expected []string
+	}{
+		{"L'application plante. J'adore", French, []string{"application", "plante", "", "adore"}},
+		{"Dell’app non c'è niente", Italian, []string{"app", "non", "è", "niente"}},
+		{"I don't like l'app", English, []string{"i", "don't", "like", "l'app"}},
+		{"'Quoted' words", Spanish, []string{"quoted", "words"}},
+	}
+
+	for _, test := range tests {
+		if tokens := Tokenize(test.text, test.lang); !slices.Equal(tokens, test.expected) {
+			t.Errorf("Expected %q in %s to give %q, got %q", test.text, test.lang, test.expected, tokens)
+		}
+	}
+}
+
+// TestIsStopword verifies the stopwords of each language, English for undetermined reviews
+func TestIsStopword(t *testing.T) {
+	tests := []struct {
+		lang     string
+		word     string
+		expected bool
+	}{
+		{Spanish, "aplicación", true},
+		{Spanish, "the", false},
+		{Portuguese, "não", true},
+		{German, "nicht", true},
+		{French, "très", true},
+		{Italian, "perché", true},
+		{Undetermined, "the", true},
+		{"", "crash", false},
+	}
+
+	for _, test := range tests {
+		if IsStopword(test.lang, test.word) != test.expected {
+			t.Errorf("Expected %q to be a stopword in %q: %v", test.word, test.lang, test.expected)
+		}
+	}
+}
+
+// TestSearchTerms_FoldsDiacritics verifies that search terms compare equal with or without accents
+func TestSearchTerms_FoldsDiacritics(t *testing.T) {
+	terms := SearchTerms("Pésima aplicación. Straße, l'écran", French)
+	expected := []string{"pesima", "aplicacion", "strasse", "ecran"}
+	if !slices.Equal(terms, expected) {
+		t.Errorf("Expected %q, got %q", expected, terms)
+	}
+}
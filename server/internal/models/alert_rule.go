@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Alert rule types
+const (
+	AlertRuleAverageRatingBelow = "average_rating_below" // average rating of the last WindowHours below Threshold
+	AlertRuleOneStarSpike       = "one_star_spike"       // 1-star reviews of the last WindowHours above Threshold times the baseline
+	AlertRuleNoReviews          = "no_reviews"           // no review for WindowHours, the feed may be broken
+)
+
+// AlertRule is a condition evaluated over stored reviews after every poll
+type AlertRule struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Type            string    `json:"type"`
+	AppID           string    `json:"appId,omitempty"` // empty means the primary app
+	WindowHours     int       `json:"windowHours"`
+	Threshold       float64   `json:"threshold"`                 // average rating for average_rating_below, baseline multiple for one_star_spike
+	BaselineWindows int       `json:"baselineWindows,omitempty"` // previous windows averaged as one_star_spike baseline
+	MinReviews      int       `json:"minReviews,omitempty"`      // reviews needed for average_rating_below to be evaluated
+	CooldownMinutes int       `json:"cooldownMinutes"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// Alert rule statuses
+const (
+	AlertStatusOK               = "ok"
+	AlertStatusFiring           = "firing"
+	AlertStatusInsufficientData = "insufficient_data"
+)
+
+// AlertRuleState is the result of the last evaluation of a rule
+type AlertRuleState struct {
+	RuleID          string     `json:"ruleId"`
+	Status          string     `json:"status"`
+	Value           float64    `json:"value"`
+	Message         string     `json:"message"`
+	LastEvaluatedAt time.Time  `json:"lastEvaluatedAt"`
+	LastTriggeredAt *time.Time `json:"lastTriggeredAt,omitempty"` // last time an alert was sent
+	TriggerCount    int        `json:"triggerCount"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// API key roles, each one allowed everything the previous ones are
+const (
+	RoleReadOnly = "read-only" // read reviews, stats, insights, triage and views
+	RoleTriage   = "triage"    // also change triage, add notes and manage views
+	RoleAdmin    = "admin"     // also manage webhooks, rules, flags and API keys
+)
+
+// Roles lists the API key roles from the least to the most privileged
+var Roles = []string{RoleReadOnly, RoleTriage, RoleAdmin}
+
+// RoleAllows reports whether role grants the access of required
+func RoleAllows(role, required string) bool {
+	index := slices.Index(Roles, role)
+	return index >= 0 && index >= slices.Index(Roles, required)
+}
+
+// APIKey identifies a client of the API. The key itself is only shown when it is created,
+// storage keeps its hash.
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	Prefix    string     `json:"prefix"` // first characters of the key, to tell keys apart
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// IsRevoked reports whether the key was revoked
+func (k APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"cmp"
 	"slices"
 	"time"
 )
@@ -12,10 +13,60 @@ type AppStoreReview struct {
 	Author    string    `json:"author"`
 	Rating    int       `json:"rating"`
 	UpdatedAt time.Time `json:"updatedAt"`
+
+	Sentiment      float64 `json:"sentiment"`                // lexicon score of title and content, from -1 to 1
+	SentimentLabel string  `json:"sentimentLabel,omitempty"` // positive, neutral or negative, empty until scored
+
+	Tags []string `json:"tags,omitempty"` // topics of the matching tag rules, sorted
+
+	Language string `json:"language,omitempty"` // ISO 639-1 code, "und" when undetermined, empty until detected
+
+	Flags        []ReviewFlag `json:"flags,omitempty"`        // reasons the review looks like spam, excluded from statistics by default
+	FlagsCleared bool         `json:"flagsCleared,omitempty"` // an admin cleared its flags, it is not flagged again
+}
+
+// Review flag reasons
+const (
+	FlagDuplicate      = "duplicate"       // near duplicate of an earlier review
+	FlagRepeatedAuthor = "repeated_author" // author posted many reviews in a short time
+	FlagLink           = "link"            // content contains a link
+	FlagCharacterFlood = "character_flood" // a character repeated many times in a row
+)
+
+// ReviewFlag is a reason a review looks like spam
+type ReviewFlag struct {
+	Reason string `json:"reason"`
+	Detail string `json:"detail"`
+}
+
+// IsFlagged reports whether the review has any flag
+func (r AppStoreReview) IsFlagged() bool {
+	return len(r.Flags) > 0
 }
 
 type AppStoreReviews []AppStoreReview
 
+// Sort orders accepted by SortBy
+const (
+	SortNewest        = "newest"
+	SortOldest        = "oldest"
+	SortSentimentAsc  = "sentiment_asc" // most negative first
+	SortSentimentDesc = "sentiment_desc"
+)
+
+// SortBy sorts reviews by order, newest first for an unknown order. Ties keep the newest first.
+func (reviews AppStoreReviews) SortBy(order string) {
+	reviews.Sort()
+	switch order {
+	case SortOldest:
+		slices.Reverse(reviews)
+	case SortSentimentAsc:
+		slices.SortStableFunc(reviews, func(a, b AppStoreReview) int { return cmp.Compare(a.Sentiment, b.Sentiment) })
+	case SortSentimentDesc:
+		slices.SortStableFunc(reviews, func(a, b AppStoreReview) int { return cmp.Compare(b.Sentiment, a.Sentiment) })
+	}
+}
+
 func (reviews AppStoreReviews) Sort() {
 	slices.SortFunc(reviews, func(a, b AppStoreReview) int {
 		if a.UpdatedAt.After(b.UpdatedAt) {
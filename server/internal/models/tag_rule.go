@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Tag rule types
+const (
+	TagRuleKeyword = "keyword" // comma separated words or phrases, any of them matches
+	TagRuleRegex   = "regex"   // Go regular expression, case insensitive
+	TagRuleBoolean = "boolean" // words and "quoted phrases" combined with AND, OR, NOT and parentheses
+)
+
+// Tag rule sources
+const (
+	TagRuleSourceConfig = "config"
+	TagRuleSourceAPI    = "api"
+)
+
+// TagRule adds Tag to the reviews whose title or content match Pattern
+type TagRule struct {
+	ID        string     `json:"id"`
+	Tag       string     `json:"tag"`
+	Type      string     `json:"type"`
+	Pattern   string     `json:"pattern"`
+	Source    string     `json:"source"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"` // nil for config rules
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Triage statuses
+const (
+	TriageNew          = "new"
+	TriageAcknowledged = "acknowledged"
+	TriageResolved     = "resolved"
+	TriageIgnored      = "ignored"
+)
+
+// TriageStatuses lists the valid triage statuses
+var TriageStatuses = []string{TriageNew, TriageAcknowledged, TriageResolved, TriageIgnored}
+
+// Triage tracks how the support team handles a review. It is stored apart from the reviews,
+// which are rewritten from the feed, so polling never overwrites it.
+type Triage struct {
+	AppID     string       `json:"appId"`
+	ReviewID  string       `json:"reviewId"`
+	Status    string       `json:"status"`
+	Assignee  string       `json:"assignee,omitempty"`
+	Labels    []string     `json:"labels"`              // sorted, without duplicates
+	Notes     []TriageNote `json:"notes"`               // oldest first
+	UpdatedAt *time.Time   `json:"updatedAt,omitempty"` // nil until the review is first triaged
+}
+
+// TriageNote is an internal note on a review, never shown to its author
+type TriageNote struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewTriage returns the triage of a review nobody handled yet
+func NewTriage(appID, reviewID string) Triage {
+	return Triage{AppID: appID, ReviewID: reviewID, Status: TriageNew, Labels: []string{}, Notes: []TriageNote{}}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// View is a named reviews filter stored on the server, shared by its ID
+type View struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Filter    ViewFilter `json:"filter"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// ViewFilter holds the GET /reviews query parameters of a view, empty fields use the endpoint defaults
+type ViewFilter struct {
+	AppID     string `json:"appId,omitempty"`
+	Hours     int    `json:"hours,omitempty"`
+	Rating    *int   `json:"rating,omitempty"`
+	Sentiment string `json:"sentiment,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Q         string `json:"q,omitempty"`
+	Lang      string `json:"lang,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Assignee  string `json:"assignee,omitempty"`
+	Sort      string `json:"sort,omitempty"`
+}
+
+// Values returns the filter as GET /reviews query parameters
+func (f ViewFilter) Values() url.Values {
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	set("appId", f.AppID)
+	if f.Hours != 0 {
+		values.Set("hours", strconv.Itoa(f.Hours))
+	}
+	if f.Rating != nil {
+		values.Set("rating", strconv.Itoa(*f.Rating))
+	}
+	set("sentiment", f.Sentiment)
+	set("tag", f.Tag)
+	set("q", f.Q)
+	set("lang", f.Lang)
+	set("status", f.Status)
+	set("assignee", f.Assignee)
+	set("sort", f.Sort)
+	return values
+}
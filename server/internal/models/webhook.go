@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription is a target that receives new reviews as signed JSON POSTs
+type WebhookSubscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	AppID     string    `json:"appId,omitempty"`   // empty means every tracked app
+	Ratings   []int     `json:"ratings,omitempty"` // empty means every rating
+	Secret    string    `json:"secret,omitempty"`  // HMAC key, never returned by the API
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookDelivery is a pending (outbox) or failed (dead letter) POST to a subscription
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	URL            string          `json:"url"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
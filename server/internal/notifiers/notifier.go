@@ -0,0 +1,53 @@
+package notifiers
+
+import (
+	"context"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// Notifier is called by the poller with every batch of newly added reviews of an app
+type Notifier interface {
+	Notify(ctx context.Context, appID string, reviews []models.AppStoreReview) error
+	// Flush retries the notifications that failed before, on polls without new reviews
+	Flush(ctx context.Context) error
+}
+
+// AlertSender is called by the alert rules engine with every triggered rule
+type AlertSender interface {
+	SendAlert(ctx context.Context, rule models.AlertRule, state models.AlertRuleState) error
+}
+
+// AlertSenderFromConfig creates the alert sender enabled in cfg, nil when none is.
+// Alerts go to SLACK_WEBHOOK_URL, falling back to the 1-star route.
+func AlertSenderFromConfig(cfg *config.Config) AlertSender {
+	url := cfg.SlackWebhookURL
+	if url == "" {
+		url = cfg.SlackRoutes[1]
+	}
+	if url == "" {
+		return nil
+	}
+	return NewSlackAlerts(url)
+}
+
+// FromConfig creates the notifiers enabled in cfg, nil when none is
+func FromConfig(cfg *config.Config) Notifier {
+	if cfg.SlackWebhookURL == "" && len(cfg.SlackRoutes) == 0 {
+		return nil
+	}
+
+	routes := make(map[int]string)
+	if cfg.SlackWebhookURL != "" {
+		for _, rating := range cfg.SlackAlertRatings {
+			routes[rating] = cfg.SlackWebhookURL
+		}
+	}
+	for rating, url := range cfg.SlackRoutes {
+		routes[rating] = url
+	}
+
+	return NewSlack(routes, repositories.LoadNotifications(cfg.DataFilePath("slack-notifications.json")))
+}
@@ -0,0 +1,185 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/textfmt"
+)
+
+const (
+	// maxReviewsPerMessage keeps messages under Slack's 50 blocks limit
+	maxReviewsPerMessage = 20
+	contentExcerptLength = 280
+)
+
+// SlackNotifier posts reviews to Slack incoming webhooks, one message per webhook and batch.
+// Reviews are routed by rating, already notified ones are skipped and failed ones retried on the next batch or Flush.
+type SlackNotifier struct {
+	routes map[int]string // rating -> incoming webhook URL
+	repo   *repositories.NotificationsRepository
+	client *http.Client
+}
+
+// NewSlack creates a SlackNotifier sending each rating to its webhook URL in routes, ratings without a route are ignored
+func NewSlack(routes map[int]string, repo *repositories.NotificationsRepository) *SlackNotifier {
+	return &SlackNotifier{
+		routes: routes,
+		repo:   repo,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// slackMessage is an incoming webhook payload
+type slackMessage struct {
+	Text   string       `json:"text"` // fallback for notifications
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, appID string, reviews []models.AppStoreReview) error {
+	var alerted []models.AppStoreReview
+	for _, review := range reviews {
+		if _, ok := s.routes[review.Rating]; ok {
+			alerted = append(alerted, review)
+		}
+	}
+
+	// pending reviews include the ones that failed on previous batches
+	pending, err := s.repo.AddPending(appID, alerted)
+	if err != nil {
+		log.Printf("SLACK: error saving pending notifications: %v", err)
+	}
+	return s.send(ctx, pending)
+}
+
+// Flush retries the reviews whose notification failed before
+func (s *SlackNotifier) Flush(ctx context.Context) error {
+	return s.send(ctx, s.repo.Pending())
+}
+
+// send posts the pending reviews, one message per webhook and app, and marks the sent ones as notified
+func (s *SlackNotifier) send(ctx context.Context, pending []repositories.PendingNotification) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// one message per webhook URL and app
+	type messageKey struct{ url, appID string }
+	batches := make(map[messageKey][]models.AppStoreReview)
+	var keys []messageKey
+	for _, p := range pending {
+		url, ok := s.routes[p.Review.Rating]
+		if !ok {
+			continue // route removed since it was queued
+		}
+		key := messageKey{url, p.AppID}
+		if _, ok := batches[key]; !ok {
+			keys = append(keys, key)
+		}
+		batches[key] = append(batches[key], p.Review)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		batch := batches[key]
+		if err := postSlackMessage(ctx, s.client, key.url, buildSlackMessage(key.appID, batch)); err != nil {
+			errs = append(errs, fmt.Errorf("posting %d reviews: %w", len(batch), err))
+			continue
+		}
+
+		ids := make([]string, 0, len(batch))
+		for _, review := range batch {
+			ids = append(ids, review.ID)
+		}
+		if err := s.repo.MarkNotified(ids); err != nil {
+			errs = append(errs, fmt.Errorf("saving notified reviews: %w", err))
+		}
+		log.Printf("SLACK: notified %d reviews of app %s", len(batch), key.appID)
+	}
+
+	return errors.Join(errs...)
+}
+
+// postSlackMessage posts message to an incoming webhook URL
+func postSlackMessage(ctx context.Context, client *http.Client, url string, message slackMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// buildSlackMessage renders a batch of reviews as a single message
+func buildSlackMessage(appID string, reviews []models.AppStoreReview) slackMessage {
+	text := fmt.Sprintf("%d new low rated reviews for app %s", len(reviews), appID)
+	if len(reviews) == 1 {
+		text = fmt.Sprintf("New %d-star review for app %s: %s", reviews[0].Rating, appID, reviews[0].Title)
+	}
+
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "*" + escapeSlack(text) + "*"}}}
+	for i, review := range reviews {
+		if i == maxReviewsPerMessage {
+			blocks = append(blocks, slackBlock{Type: "divider"}, slackBlock{Type: "section", Text: &slackText{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("_and %d more reviews_", len(reviews)-maxReviewsPerMessage),
+			}})
+			break
+		}
+
+		blocks = append(blocks, slackBlock{Type: "divider"}, slackBlock{Type: "section", Text: &slackText{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s*  %s\nby %s\n>%s",
+				escapeSlack(review.Title),
+				textfmt.Stars(review.Rating),
+				escapeSlack(review.Author),
+				strings.ReplaceAll(escapeSlack(textfmt.Excerpt(review.Content, contentExcerptLength)), "\n", "\n>"),
+			),
+		}})
+	}
+
+	return slackMessage{Text: text, Blocks: blocks}
+}
+
+// escapeSlack escapes the characters Slack uses for links and mentions
+func escapeSlack(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
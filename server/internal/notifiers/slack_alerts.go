@@ -0,0 +1,41 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// SlackAlertSender posts triggered alert rules to a Slack incoming webhook
+type SlackAlertSender struct {
+	url    string
+	client *http.Client
+}
+
+func NewSlackAlerts(url string) *SlackAlertSender {
+	return &SlackAlertSender{
+		url: url,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (s *SlackAlertSender) SendAlert(ctx context.Context, rule models.AlertRule, state models.AlertRuleState) error {
+	return postSlackMessage(ctx, s.client, s.url, buildAlertMessage(rule, state))
+}
+
+// buildAlertMessage renders a triggered rule as a single message
+func buildAlertMessage(rule models.AlertRule, state models.AlertRuleState) slackMessage {
+	text := fmt.Sprintf("Alert: %s", rule.Name)
+	return slackMessage{
+		Text: text,
+		Blocks: []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: ":rotating_light: *" + escapeSlack(text) + "*"}},
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: escapeSlack(state.Message)}},
+		},
+	}
+}
@@ -0,0 +1,252 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	// Suppress logs during testing
+	log.SetOutput(io.Discard)
+
+	// Run tests
+	code := m.Run()
+
+	// Restore log output
+	log.SetOutput(os.Stderr)
+
+	// Exit with the same code as the tests
+	os.Exit(code)
+}
+
+// fakeSlack is a local incoming webhook recording the messages it receives
+type fakeSlack struct {
+	mu       sync.Mutex
+	messages []slackMessage
+	status   int
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.status != 0 && f.status != http.StatusOK {
+		w.WriteHeader(f.status)
+		w.Write([]byte("invalid_token"))
+		return
+	}
+
+	var message slackMessage
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.messages = append(f.messages, message)
+	w.Write([]byte("ok"))
+}
+
+func (f *fakeSlack) received() []slackMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]slackMessage(nil), f.messages...)
+}
+
+func startFakeSlack(t *testing.T) (*fakeSlack, string) {
+	fake := &fakeSlack{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server.URL
+}
+
+func createTestReviews() []models.AppStoreReview {
+	now := time.Now().UTC()
+	return []models.AppStoreReview{
+		{ID: "review-1", Title: "Crashes <always>", Content: "Crashes on launch\nevery time", Author: "User1", Rating: 1, UpdatedAt: now},
+		{ID: "review-2", Title: "Meh", Content: "Slow app", Author: "User2", Rating: 2, UpdatedAt: now},
+		{ID: "review-3", Title: "Great", Content: "Love it", Author: "User3", Rating: 5, UpdatedAt: now},
+	}
+}
+
+func messageText(message slackMessage) string {
+	var texts []string
+	for _, block := range message.Blocks {
+		if block.Text != nil {
+			texts = append(texts, block.Text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// TestNotify_BatchesLowStarReviewsIntoOneMessage verifies that alerted reviews of a batch are sent in a single message
+func TestNotify_BatchesLowStarReviewsIntoOneMessage(t *testing.T) {
+	fake, url := startFakeSlack(t)
+	notifier := NewSlack(map[int]string{1: url, 2: url}, repositories.LoadNotifications(""))
+
+	if err := notifier.Notify(context.Background(), "test-app", createTestReviews()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages := fake.received()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+
+	text := messageText(messages[0])
+	for _, expected := range []string{"Crashes &lt;always&gt;", "★☆☆☆☆", "by User1", ">Crashes on launch\n>every time", "Meh", "★★☆☆☆"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected message to contain %q, got:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, "Love it") {
+		t.Error("Expected 5-star review not to be notified")
+	}
+	if messages[0].Text == "" {
+		t.Error("Expected fallback text to be set")
+	}
+}
+
+// TestNotify_RoutesByRating verifies that each rating is sent to its own webhook
+func TestNotify_RoutesByRating(t *testing.T) {
+	oneStar, oneStarURL := startFakeSlack(t)
+	twoStars, twoStarsURL := startFakeSlack(t)
+	notifier := NewSlack(map[int]string{1: oneStarURL, 2: twoStarsURL}, repositories.LoadNotifications(""))
+
+	notifier.Notify(context.Background(), "test-app", createTestReviews())
+
+	if len(oneStar.received()) != 1 || !strings.Contains(messageText(oneStar.received()[0]), "by User1") {
+		t.Errorf("Expected the 1-star review on the 1-star webhook, got %+v", oneStar.received())
+	}
+	if len(twoStars.received()) != 1 || !strings.Contains(messageText(twoStars.received()[0]), "by User2") {
+		t.Errorf("Expected the 2-star review on the 2-star webhook, got %+v", twoStars.received())
+	}
+}
+
+// TestNotify_DeduplicatesAcrossRestarts verifies that reviews notified before a restart are not sent again
+func TestNotify_DeduplicatesAcrossRestarts(t *testing.T) {
+	fake, url := startFakeSlack(t)
+	filePath := filepath.Join(t.TempDir(), "slack-notifications.json")
+	routes := map[int]string{1: url, 2: url}
+
+	NewSlack(routes, repositories.LoadNotifications(filePath)).Notify(context.Background(), "test-app", createTestReviews())
+
+	restarted := NewSlack(routes, repositories.LoadNotifications(filePath))
+	restarted.Notify(context.Background(), "test-app", createTestReviews())
+
+	if len(fake.received()) != 1 {
+		t.Errorf("Expected reviews to be notified only once, got %d messages", len(fake.received()))
+	}
+}
+
+// TestNotify_RetriesFailedReviewsWithNextBatch verifies that reviews of a failed message are sent with the next batch
+func TestNotify_RetriesFailedReviewsWithNextBatch(t *testing.T) {
+	fake, url := startFakeSlack(t)
+	fake.status = http.StatusForbidden
+	notifier := NewSlack(map[int]string{1: url, 2: url}, repositories.LoadNotifications(""))
+
+	if err := notifier.Notify(context.Background(), "test-app", createTestReviews()[:1]); err == nil {
+		t.Fatal("Expected error when Slack rejects the message, got nil")
+	}
+
+	fake.mu.Lock()
+	fake.status = http.StatusOK
+	fake.mu.Unlock()
+
+	next := []models.AppStoreReview{{ID: "review-4", Title: "Bad", Author: "User4", Rating: 2, UpdatedAt: time.Now().UTC()}}
+	if err := notifier.Notify(context.Background(), "test-app", next); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages := fake.received()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	text := messageText(messages[0])
+	if !strings.Contains(text, "by User1") || !strings.Contains(text, "by User4") {
+		t.Errorf("Expected failed and new review in the same message, got:\n%s", text)
+	}
+}
+
+// TestFlush_RetriesPendingReviews verifies that reviews of a failed message are sent by Flush without a new batch
+func TestFlush_RetriesPendingReviews(t *testing.T) {
+	fake, url := startFakeSlack(t)
+	fake.status = http.StatusForbidden
+	notifier := NewSlack(map[int]string{1: url, 2: url}, repositories.LoadNotifications(""))
+
+	if err := notifier.Notify(context.Background(), "test-app", createTestReviews()[:1]); err == nil {
+		t.Fatal("Expected error when Slack rejects the message, got nil")
+	}
+
+	fake.mu.Lock()
+	fake.status = http.StatusOK
+	fake.mu.Unlock()
+
+	if err := notifier.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := notifier.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages := fake.received()
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	if !strings.Contains(messageText(messages[0]), "by User1") {
+		t.Errorf("Expected the failed review to be sent, got:\n%s", messageText(messages[0]))
+	}
+}
+
+// TestBuildSlackMessage_TruncatesLongBatches verifies that long batches stay within Slack block limits
+func TestBuildSlackMessage_TruncatesLongBatches(t *testing.T) {
+	reviews := make([]models.AppStoreReview, 30)
+	for i := range reviews {
+		reviews[i] = models.AppStoreReview{ID: "review", Title: "Bad", Content: strings.Repeat("a", 500), Rating: 1}
+	}
+
+	message := buildSlackMessage("test-app", reviews)
+
+	if len(message.Blocks) > 50 {
+		t.Errorf("Expected at most 50 blocks, got %d", len(message.Blocks))
+	}
+	if !strings.Contains(messageText(message), "and 10 more reviews") {
+		t.Error("Expected remaining reviews to be summarized")
+	}
+	if !strings.Contains(messageText(message), strings.Repeat("a", contentExcerptLength)+"…") {
+		t.Error("Expected content to be cut to an excerpt")
+	}
+}
+
+// TestFromConfig_BuildsRoutes verifies the routes built from the config
+func TestFromConfig_BuildsRoutes(t *testing.T) {
+	if FromConfig(&config.Config{}) != nil {
+		t.Error("Expected no notifier without Slack config")
+	}
+
+	notifier := FromConfig(&config.Config{
+		SlackWebhookURL:   "https://hooks.example.com/default",
+		SlackAlertRatings: []int{1, 2},
+		SlackRoutes:       map[int]string{1: "https://hooks.example.com/one-star"},
+	}).(*SlackNotifier)
+
+	if notifier.routes[1] != "https://hooks.example.com/one-star" || notifier.routes[2] != "https://hooks.example.com/default" {
+		t.Errorf("Unexpected routes: %v", notifier.routes)
+	}
+	if _, ok := notifier.routes[5]; ok {
+		t.Error("Expected 5-star reviews not to be routed")
+	}
+}
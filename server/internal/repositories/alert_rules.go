@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// AlertRulesRepository stores alert rules and the state of their last evaluation
+type AlertRulesRepository struct {
+	mu              sync.Mutex
+	data            alertRulesData
+	StorageFilePath string
+}
+
+type alertRulesData struct {
+	Rules  []models.AlertRule               `json:"rules"`
+	States map[string]models.AlertRuleState `json:"states"` // by rule ID
+}
+
+func LoadAlertRules(storageFilePath string) *AlertRulesRepository {
+	repo := &AlertRulesRepository{
+		data: alertRulesData{
+			Rules:  []models.AlertRule{},
+			States: map[string]models.AlertRuleState{},
+		},
+		StorageFilePath: storageFilePath,
+	}
+
+	if err := loadJSONFile(storageFilePath, &repo.data); err != nil {
+		log.Printf("Error loading alert rules from file: %v", err)
+		log.Printf("Starting with no alert rules")
+	}
+
+	return repo
+}
+
+func (r *AlertRulesRepository) ListRules() []models.AlertRule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.data.Rules)
+}
+
+func (r *AlertRulesRepository) AddRule(rule models.AlertRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data.Rules = append(r.data.Rules, rule)
+	return saveJSONFile(r.StorageFilePath, r.data)
+}
+
+// DeleteRule removes a rule and its state. Returns false if it doesn't exist
+func (r *AlertRulesRepository) DeleteRule(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := slices.IndexFunc(r.data.Rules, func(rule models.AlertRule) bool { return rule.ID == id })
+	if index < 0 {
+		return false, nil
+	}
+
+	r.data.Rules = slices.Delete(r.data.Rules, index, index+1)
+	delete(r.data.States, id)
+	return true, saveJSONFile(r.StorageFilePath, r.data)
+}
+
+// GetState returns the state of a rule, false if it was never evaluated
+func (r *AlertRulesRepository) GetState(ruleID string) (models.AlertRuleState, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.data.States[ruleID]
+	return state, ok
+}
+
+// SaveStates stores the state of the evaluated rules
+func (r *AlertRulesRepository) SaveStates(states []models.AlertRuleState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, state := range states {
+		// skip rules deleted during the evaluation
+		if slices.ContainsFunc(r.data.Rules, func(rule models.AlertRule) bool { return rule.ID == state.RuleID }) {
+			r.data.States[state.RuleID] = state
+		}
+	}
+	return saveJSONFile(r.StorageFilePath, r.data)
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/ids"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+const (
+	apiKeyPrefix       = "ark_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 6
+)
+
+// fileState tells whether a file changed, the size covering modification times of coarse resolution
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// storedAPIKey is an API key as stored, with the SHA-256 hash of the key instead of the key
+type storedAPIKey struct {
+	models.APIKey
+	Hash string `json:"hash"`
+}
+
+// APIKeysRepository stores the API keys. Keys are created by the server and by the CLI, so the file
+// is read again when it changed on disk since it was last loaded or saved.
+type APIKeysRepository struct {
+	mu              sync.Mutex
+	keys            []storedAPIKey
+	fileState       fileState // of the storage file when it was last loaded or saved
+	StorageFilePath string
+}
+
+func LoadAPIKeys(storageFilePath string) *APIKeysRepository {
+	repo := &APIKeysRepository{
+		keys:            []storedAPIKey{},
+		StorageFilePath: storageFilePath,
+	}
+	repo.reloadIfChanged()
+	return repo
+}
+
+// List returns every key, revoked ones included, in creation order
+func (r *APIKeysRepository) List() []models.APIKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+
+	keys := make([]models.APIKey, len(r.keys))
+	for i, key := range r.keys {
+		keys[i] = key.APIKey
+	}
+	return keys
+}
+
+// Create generates and stores a key, returning it along with the secret to hand to the client.
+// The secret can't be recovered afterwards.
+func (r *APIKeysRepository) Create(name, role string) (models.APIKey, string, error) {
+	secret := apiKeyPrefix + ids.New()
+	key := storedAPIKey{
+		APIKey: models.APIKey{
+			ID:        ids.New(),
+			Name:      name,
+			Role:      role,
+			Prefix:    secret[:apiKeyPrefixLength],
+			CreatedAt: time.Now().UTC(),
+		},
+		Hash: hashAPIKey(secret),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+
+	r.keys = append(r.keys, key)
+	return key.APIKey, secret, r.save()
+}
+
+// Revoke revokes a key, keeping it listed. Returns false if it doesn't exist
+func (r *APIKeysRepository) Revoke(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+
+	index := slices.IndexFunc(r.keys, func(key storedAPIKey) bool { return key.ID == id })
+	if index < 0 {
+		return false, nil
+	}
+	if r.keys[index].IsRevoked() {
+		return true, nil
+	}
+
+	now := time.Now().UTC()
+	r.keys[index].RevokedAt = &now
+	return true, r.save()
+}
+
+// Authenticate returns the key matching secret, false if there is none or it was revoked
+func (r *APIKeysRepository) Authenticate(secret string) (models.APIKey, bool) {
+	hash := hashAPIKey(secret)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+
+	for _, key := range r.keys {
+		if key.Hash == hash && !key.IsRevoked() {
+			return key.APIKey, true
+		}
+	}
+	return models.APIKey{}, false
+}
+
+// reloadIfChanged loads the storage file when it changed since the last load or save.
+// Callers must hold r.mu.
+func (r *APIKeysRepository) reloadIfChanged() {
+	state := r.currentFileState()
+	if state == r.fileState {
+		return
+	}
+
+	var keys []storedAPIKey
+	if err := loadJSONFile(r.StorageFilePath, &keys); err != nil {
+		log.Printf("Error loading API keys from file: %v", err)
+		return
+	}
+	if keys == nil {
+		keys = []storedAPIKey{}
+	}
+	r.keys = keys
+	r.fileState = state
+}
+
+// save persists the keys. Callers must hold r.mu.
+func (r *APIKeysRepository) save() error {
+	if err := saveJSONFile(r.StorageFilePath, r.keys); err != nil {
+		return err
+	}
+	r.fileState = r.currentFileState()
+	return nil
+}
+
+func (r *APIKeysRepository) currentFileState() fileState {
+	if r.StorageFilePath == "" {
+		return fileState{}
+	}
+	info, err := os.Stat(r.StorageFilePath)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}
+}
+
+// hashAPIKey hashes a key with SHA-256. Keys are 128 bit random values, so a fast unsalted hash is enough.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -6,14 +6,19 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
 )
 
 type AppReviewsRepository struct {
+	mu              sync.RWMutex // guards Reviews, the poller writes while the API reads
 	Reviews         models.AppStoreReviews
 	StorageFilePath string
+	version         uint64    // bumped by every change to Reviews
+	modified        time.Time // when version was last bumped
 }
 
 func Load(storageFilePath string) *AppReviewsRepository {
@@ -30,20 +35,120 @@ func Load(storageFilePath string) *AppReviewsRepository {
 		log.Printf("Loaded %d reviews from storage file: %s", len(repo.Reviews), storageFilePath)
 	}
 
+	// versions start from the last save, so they keep increasing across restarts
+	repo.modified = time.Now().UTC()
+	if info, err := os.Stat(storageFilePath); err == nil {
+		repo.modified = info.ModTime().UTC()
+	}
+	repo.version = uint64(repo.modified.UnixNano())
+
 	return repo
 }
 
+// MigrateLegacyStorage moves the reviews file legacyPath of earlier versions to path, the file of the app it was
+// written for. It is moved rather than copied, so it is migrated once and never loaded by another app later.
+// Nothing is moved when legacyPath doesn't exist or isn't a reviews file, or when path exists: the app already
+// has a file of its own, and the legacy one is left for an import.
+func MigrateLegacyStorage(legacyPath, path string) error {
+	data, err := os.ReadFile(legacyPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var reviews models.AppStoreReviews
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return fmt.Errorf("%s is not a reviews file: %v", legacyPath, err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		log.Printf("Legacy storage file %s kept, %s already exists", legacyPath, path)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(legacyPath, path); err != nil {
+		return err
+	}
+	log.Printf("Moved %d reviews from legacy storage file %s to %s", len(reviews), legacyPath, path)
+	return nil
+}
+
+// Version returns the data version of the reviews, which increases with every change, and when it last changed
+func (a *AppReviewsRepository) Version() (uint64, time.Time) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.version, a.modified
+}
+
+// bumpVersion marks the reviews as changed. Callers must hold a.mu.
+func (a *AppReviewsRepository) bumpVersion() {
+	a.version++
+	a.modified = time.Now().UTC()
+}
+
+// LastExpired returns when the newest review older than the last hours left that window, zero if there is none.
+// Listings of the last hours change then without the version changing.
+func (a *AppReviewsRepository) LastExpired(hours int) time.Time {
+	window := time.Duration(hours) * time.Hour
+	cutoffTime := time.Now().UTC().Add(-window)
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	// reviews are sorted by updatedAt in descending order
+	for _, review := range a.Reviews {
+		if !review.UpdatedAt.After(cutoffTime) {
+			return review.UpdatedAt.Add(window)
+		}
+	}
+	return time.Time{}
+}
+
 func (a *AppReviewsRepository) ListLatest(hours int, query ReviewFilter) models.AppStoreReviews {
+	var recentReviews models.AppStoreReviews = make(models.AppStoreReviews, 0)
+
+	a.EachLatest(hours, query, func(review models.AppStoreReview) error {
+		recentReviews = append(recentReviews, review)
+		return nil
+	})
+
+	return recentReviews
+}
+
+// ListAll returns every stored review matching query, newest first
+func (a *AppReviewsRepository) ListAll(query ReviewFilter) models.AppStoreReviews {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	reviews := make(models.AppStoreReviews, 0)
+	for _, review := range a.Reviews {
+		if query.Matches(review) {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews
+}
+
+// EachLatest calls fn for every review of the last hours matching query, newest first,
+// without copying them into a new slice. Iteration stops at the first error fn returns.
+// Writes to the repository wait until it returns, so fn should not be slow.
+func (a *AppReviewsRepository) EachLatest(hours int, query ReviewFilter, fn func(review models.AppStoreReview) error) error {
 	cutoffTime := time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
 
-	var recentReviews models.AppStoreReviews = make(models.AppStoreReviews, 0)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 
 	// I'm considering that the reviews are already sorted by updatedAt in descending order
 	// (since I'm fetching and saving them like that) so I'm choosing not sort them
 	for _, review := range a.Reviews {
 		if review.UpdatedAt.After(cutoffTime) {
-			if query.Rating == nil || review.Rating == *query.Rating {
-				recentReviews = append(recentReviews, review)
+			if query.Matches(review) {
+				if err := fn(review); err != nil {
+					return err
+				}
 			}
 		} else {
 			// log.Printf("review.UpdatedAt before cutoffTime: %s", review.UpdatedAt.Format(time.RFC3339))
@@ -51,14 +156,133 @@ func (a *AppReviewsRepository) ListLatest(hours int, query ReviewFilter) models.
 		}
 	}
 
-	return recentReviews
+	return nil
+}
+
+// streamChunkSize is how many reviews StreamLatest copies each time it takes the lock
+const streamChunkSize = 500
+
+// StreamLatest calls fn for every review of the last hours matching query, newest first, like EachLatest,
+// but takes the lock only to copy the next streamChunkSize reviews, never while fn runs, so fn may be slow.
+// Each chunk resumes after the last review passed to fn, reviews added meanwhile before it are not passed.
+func (a *AppReviewsRepository) StreamLatest(hours int, query ReviewFilter, fn func(review models.AppStoreReview) error) error {
+	cutoffTime := time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
+	next := 0
+	var last *models.AppStoreReview
+	for {
+		var chunk models.AppStoreReviews
+		chunk, next = a.latestChunk(cutoffTime, query, last, next)
+		for _, review := range chunk {
+			if err := fn(review); err != nil {
+				return err
+			}
+		}
+		if len(chunk) < streamChunkSize {
+			return nil
+		}
+		last = &chunk[len(chunk)-1]
+	}
+}
+
+// latestChunk copies up to streamChunkSize reviews matching query and updated after cutoffTime, starting after
+// last, nil for the newest. next is where last was expected to be found, it is searched for when reviews were
+// added or removed before it, or resumed from its updatedAt when it is gone. Returns where the next chunk starts.
+func (a *AppReviewsRepository) latestChunk(cutoffTime time.Time, query ReviewFilter, last *models.AppStoreReview, next int) (models.AppStoreReviews, int) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	start := 0
+	if last != nil {
+		if next > 0 && next <= len(a.Reviews) && a.Reviews[next-1].ID == last.ID {
+			start = next
+		} else if index := slices.IndexFunc(a.Reviews, func(r models.AppStoreReview) bool { return r.ID == last.ID }); index >= 0 {
+			start = index + 1
+		} else {
+			start = slices.IndexFunc(a.Reviews, func(r models.AppStoreReview) bool { return r.UpdatedAt.Before(last.UpdatedAt) })
+			if start < 0 {
+				start = len(a.Reviews)
+			}
+		}
+	}
+
+	chunk := make(models.AppStoreReviews, 0, streamChunkSize)
+	i := start
+	for ; i < len(a.Reviews) && len(chunk) < streamChunkSize; i++ {
+		review := a.Reviews[i]
+		if !review.UpdatedAt.After(cutoffTime) {
+			break
+		}
+		if query.Matches(review) {
+			chunk = append(chunk, review)
+		}
+	}
+	return chunk, i
 }
 
 func (a *AppReviewsRepository) GetLatestReview() *models.AppStoreReview {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
 	if len(a.Reviews) == 0 {
 		return nil
 	}
-	return &a.Reviews[0]
+	latest := a.Reviews[0]
+	return &latest
+}
+
+// Backfill calls fn on every stored review and saves them if fn changed any.
+// Used to fill fields added after reviews were stored. Returns the number of reviews changed
+func (a *AppReviewsRepository) Backfill(fn func(review *models.AppStoreReview) bool) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	changed := 0
+	for i := range a.Reviews {
+		if fn(&a.Reviews[i]) {
+			changed++
+		}
+	}
+
+	if changed > 0 {
+		a.bumpVersion()
+		if err := a.saveToFile(); err != nil {
+			return changed, fmt.Errorf("error saving reviews to file: %v", err)
+		}
+	}
+	return changed, nil
+}
+
+// GetReview returns the review with the given ID, nil if it is not stored
+func (a *AppReviewsRepository) GetReview(id string) *models.AppStoreReview {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, review := range a.Reviews {
+		if review.ID == id {
+			return &review
+		}
+	}
+	return nil
+}
+
+// ClearFlags removes the flags of a review and keeps it from being flagged again.
+// Returns false if no flagged review has the given ID
+func (a *AppReviewsRepository) ClearFlags(id string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := slices.IndexFunc(a.Reviews, func(review models.AppStoreReview) bool { return review.ID == id })
+	if i < 0 || !a.Reviews[i].IsFlagged() {
+		return false, nil
+	}
+	a.Reviews[i].Flags = nil
+	a.Reviews[i].FlagsCleared = true
+
+	a.bumpVersion()
+	if err := a.saveToFile(); err != nil {
+		return true, fmt.Errorf("error saving reviews to file: %v", err)
+	}
+	return true, nil
 }
 
 // hasReviewWithID checks if a review with the given ID already exists
@@ -74,30 +298,41 @@ func (a *AppReviewsRepository) hasReviewWithID(id string) bool {
 // AddBatch adds only new reviews (based on ID) to the repository
 // Returns the number of new reviews added
 func (a *AppReviewsRepository) AddBatch(reviews models.AppStoreReviews) (int, error) {
-	initialLen := len(a.Reviews)
+	added, err := a.AddNewReviews(reviews)
+	return len(added), err
+}
+
+// AddNewReviews adds only new reviews (based on ID) to the repository
+// Returns the reviews that were added
+func (a *AppReviewsRepository) AddNewReviews(reviews models.AppStoreReviews) (models.AppStoreReviews, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	added := make(models.AppStoreReviews, 0)
 
 	// Filter out reviews that already exist
 	for _, review := range reviews {
 		if !a.hasReviewWithID(review.ID) {
 			a.Reviews = append(a.Reviews, review)
+			added = append(added, review)
 		}
 	}
 
 	// Sort by updatedAt in descending order
 	a.Reviews.Sort()
-
-	newReviewsCount := len(a.Reviews) - initialLen
+	added.Sort()
 
 	// Persist to file if new reviews were added
-	if newReviewsCount > 0 {
+	if len(added) > 0 {
+		a.bumpVersion()
 		if err := a.saveToFile(); err != nil {
-			return 0, fmt.Errorf("error saving reviews to file: %v", err)
+			return nil, fmt.Errorf("error saving reviews to file: %v", err)
 		} else {
-			log.Printf("Saved %d new reviews to storage file", newReviewsCount)
+			log.Printf("Saved %d new reviews to storage file", len(added))
 		}
 	}
 
-	return newReviewsCount, nil
+	return added, nil
 }
 
 // saveToFile persists the reviews to the JSON file
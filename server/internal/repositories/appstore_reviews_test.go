@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -134,6 +135,44 @@ func TestLoad_WithExistingFile(t *testing.T) {
 	}
 }
 
+// TestMigrateLegacyStorage verifies that the legacy file is moved to the app file once, and left alone when
+// the app already has a file or when it isn't a reviews file
+func TestMigrateLegacyStorage(t *testing.T) {
+	legacyPath := createTempFileWithReviews(t, createTestReviews())
+	path := filepath.Join(t.TempDir(), "data", "reviews-111.json")
+
+	if err := MigrateLegacyStorage(legacyPath, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the legacy file to be moved, got %v", err)
+	}
+	if repo := Load(path); len(repo.Reviews) != len(createTestReviews()) {
+		t.Errorf("Expected %d reviews in the app file, got %d", len(createTestReviews()), len(repo.Reviews))
+	}
+
+	// a second run finds no legacy file
+	if err := MigrateLegacyStorage(legacyPath, path); err != nil {
+		t.Errorf("Expected nothing to migrate, got %v", err)
+	}
+
+	// the app file is never replaced
+	otherLegacyPath := createTempFileWithReviews(t, createTestReviews()[:1])
+	if err := MigrateLegacyStorage(otherLegacyPath, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(otherLegacyPath); err != nil {
+		t.Errorf("Expected the legacy file to be kept, got %v", err)
+	}
+	if repo := Load(path); len(repo.Reviews) != len(createTestReviews()) {
+		t.Errorf("Expected the app file to be kept, got %d reviews", len(repo.Reviews))
+	}
+
+	if err := MigrateLegacyStorage(createTempFileWithInvalidJSON(t), filepath.Join(t.TempDir(), "reviews-222.json")); err == nil {
+		t.Error("Expected an error for a legacy file that isn't a reviews file")
+	}
+}
+
 // TestLoad_WithNonExistentFile verifies that Load handles non-existent files gracefully
 func TestLoad_WithNonExistentFile(t *testing.T) {
 	nonExistentPath := filepath.Join(t.TempDir(), "nonexistent.json")
@@ -494,3 +533,75 @@ func TestListLatest_WithRatingFilter(t *testing.T) {
 		t.Errorf("Expected 0 reviews with 4-star rating within 2 hours, got %d", len(recentReviews))
 	}
 }
+
+// TestAddBatch_BumpsVersion verifies that the data version changes when reviews are added, and only then
+func TestAddBatch_BumpsVersion(t *testing.T) {
+	filePath := createTempFileWithReviews(t, createTestReviews())
+	repo := Load(filePath)
+	initialVersion, _ := repo.Version()
+
+	if _, err := repo.AddBatch(models.AppStoreReviews{{ID: "review-1", UpdatedAt: time.Now().UTC()}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if version, _ := repo.Version(); version != initialVersion {
+		t.Errorf("Expected version %d after adding duplicates only, got %d", initialVersion, version)
+	}
+
+	if _, err := repo.AddBatch(models.AppStoreReviews{{ID: "review-new", UpdatedAt: time.Now().UTC()}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	version, modified := repo.Version()
+	if version <= initialVersion {
+		t.Errorf("Expected version above %d after adding a review, got %d", initialVersion, version)
+	}
+	if time.Since(modified) > time.Minute {
+		t.Errorf("Expected the version to be modified now, got %v", modified)
+	}
+
+	// a restart keeps versions increasing
+	if reloaded, _ := Load(filePath).Version(); reloaded <= initialVersion {
+		t.Errorf("Expected a reloaded version above %d, got %d", initialVersion, reloaded)
+	}
+}
+
+// TestLastExpired verifies when the newest review outside the window left it
+func TestLastExpired(t *testing.T) {
+	reviews := createTestReviews()
+	repo := &AppReviewsRepository{Reviews: reviews}
+
+	if expired := repo.LastExpired(24); !expired.Equal(reviews[2].UpdatedAt.Add(24 * time.Hour)) {
+		t.Errorf("Expected review-3 to have left the window at %v, got %v", reviews[2].UpdatedAt.Add(24*time.Hour), expired)
+	}
+	if expired := repo.LastExpired(100); !expired.IsZero() {
+		t.Errorf("Expected no expired review, got %v", expired)
+	}
+}
+
+// TestStreamLatest_ResumesAfterLastReview verifies that reviews are streamed in chunks without the lock held,
+// each one once even when reviews are added between chunks
+func TestStreamLatest_ResumesAfterLastReview(t *testing.T) {
+	now := time.Now().UTC()
+	reviews := make(models.AppStoreReviews, streamChunkSize*2+10)
+	for i := range reviews {
+		reviews[i] = models.AppStoreReview{ID: fmt.Sprintf("review-%d", i), Rating: 1 + i%5, UpdatedAt: now.Add(-time.Duration(i) * time.Second)}
+	}
+	repo := &AppReviewsRepository{Reviews: reviews}
+
+	seen := map[string]bool{}
+	err := repo.StreamLatest(24, ReviewFilter{}, func(review models.AppStoreReview) error {
+		if seen[review.ID] {
+			t.Errorf("Expected %s to be streamed once", review.ID)
+		}
+		seen[review.ID] = true
+		// fails with a deadlock if the lock is held while streaming
+		repo.AddBatch(models.AppStoreReviews{{ID: "new-" + review.ID, Rating: 5, UpdatedAt: time.Now().UTC()}})
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(seen) != len(reviews) {
+		t.Errorf("Expected the %d reviews stored when streaming started, got %d", len(reviews), len(seen))
+	}
+}
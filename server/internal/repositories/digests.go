@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// DigestsRepository remembers the end of the last period sent for each digest schedule,
+// so restarts don't send a digest twice
+type DigestsRepository struct {
+	mu              sync.Mutex
+	data            digestsData
+	StorageFilePath string
+}
+
+type digestsData struct {
+	LastSent map[string]time.Time `json:"lastSent"` // schedule -> end of the last period sent
+}
+
+func LoadDigests(storageFilePath string) *DigestsRepository {
+	repo := &DigestsRepository{
+		data:            digestsData{LastSent: map[string]time.Time{}},
+		StorageFilePath: storageFilePath,
+	}
+
+	if err := loadJSONFile(storageFilePath, &repo.data); err != nil {
+		log.Printf("Error loading digests from file: %v", err)
+		log.Printf("Starting with no digest sent")
+	}
+
+	return repo
+}
+
+// LastSent returns the end of the last period sent for schedule, zero if none was sent
+func (r *DigestsRepository) LastSent(schedule string) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.data.LastSent[schedule]
+}
+
+// MarkSent records periodEnd as the last period sent for schedule
+func (r *DigestsRepository) MarkSent(schedule string, periodEnd time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data.LastSent[schedule] = periodEnd
+	return saveJSONFile(r.StorageFilePath, r.data)
+}
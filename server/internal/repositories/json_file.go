@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// saveJSONFile persists data as indented JSON, skipping it when no file path is configured
+func saveJSONFile(storageFilePath string, data any) error {
+	if storageFilePath == "" {
+		return nil // No file path configured, skip persistence
+	}
+
+	dir := filepath.Dir(storageFilePath)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(data, "", "	")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(storageFilePath, content, 0644)
+}
+
+// loadJSONFile loads data from a JSON file, leaving it untouched when the file doesn't exist
+func loadJSONFile(storageFilePath string, data any) error {
+	if storageFilePath == "" {
+		return nil // No file path configured, skip loading
+	}
+
+	content, err := os.ReadFile(storageFilePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, data)
+}
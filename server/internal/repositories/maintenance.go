@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sentiment"
+)
+
+// Verify checks the stored reviews for integrity problems:
+// sort order, duplicate and empty IDs, zero timestamps and out of range ratings
+func (a *AppReviewsRepository) Verify() []IntegrityIssue {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	issues := make([]IntegrityIssue, 0)
+	seenIDs := make(map[string]int, len(a.Reviews))
+
+	for i, review := range a.Reviews {
+		if review.ID == "" {
+			issues = append(issues, IntegrityIssue{Index: i, Problem: "empty ID"})
+		} else if firstIndex, ok := seenIDs[review.ID]; ok {
+			issues = append(issues, IntegrityIssue{Index: i, ReviewID: review.ID, Problem: fmt.Sprintf("duplicate ID, first seen at index %d", firstIndex)})
+		} else {
+			seenIDs[review.ID] = i
+		}
+
+		if review.UpdatedAt.IsZero() {
+			issues = append(issues, IntegrityIssue{Index: i, ReviewID: review.ID, Problem: "zero updatedAt timestamp"})
+		}
+
+		if review.Rating < 1 || review.Rating > 5 {
+			issues = append(issues, IntegrityIssue{Index: i, ReviewID: review.ID, Problem: fmt.Sprintf("rating %d out of range", review.Rating)})
+		}
+
+		// reviews must be sorted by updatedAt in descending order, ListLatest relies on it
+		if i > 0 && review.UpdatedAt.After(a.Reviews[i-1].UpdatedAt) {
+			issues = append(issues, IntegrityIssue{Index: i, ReviewID: review.ID, Problem: "not sorted by updatedAt in descending order"})
+		}
+	}
+
+	return issues
+}
+
+// Stats summarizes the stored reviews. Flagged reviews are only counted in FlaggedCount unless includeFlagged is set.
+func (a *AppReviewsRepository) Stats(includeFlagged bool) ReviewStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	reviews := a.Reviews
+	flagged := 0
+	for _, review := range a.Reviews {
+		if review.IsFlagged() {
+			flagged++
+		}
+	}
+	if !includeFlagged && flagged > 0 {
+		reviews = make(models.AppStoreReviews, 0, len(a.Reviews)-flagged)
+		for _, review := range a.Reviews {
+			if !review.IsFlagged() {
+				reviews = append(reviews, review)
+			}
+		}
+	}
+
+	stats := ReviewStats{
+		Count:           len(reviews),
+		FlaggedCount:    flagged,
+		RatingCounts:    map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0},
+		SentimentCounts: map[string]int{sentiment.Positive: 0, sentiment.Neutral: 0, sentiment.Negative: 0},
+		SentimentTrend:  sentimentTrend(reviews, time.Now()),
+		TagCounts:       map[string]int{},
+	}
+	if len(reviews) == 0 {
+		return stats
+	}
+
+	ratingSum := 0
+	sentimentSum := 0.0
+	for _, review := range reviews {
+		stats.RatingCounts[review.Rating]++
+		ratingSum += review.Rating
+		if review.SentimentLabel != "" {
+			stats.SentimentCounts[review.SentimentLabel]++
+		}
+		sentimentSum += review.Sentiment
+		for _, tag := range review.Tags {
+			stats.TagCounts[tag]++
+		}
+	}
+	stats.AverageRating = float64(ratingSum) / float64(len(reviews))
+	stats.AverageSentiment = sentimentSum / float64(len(reviews))
+
+	// reviews are sorted by updatedAt in descending order
+	newest := reviews[0].UpdatedAt
+	oldest := reviews[len(reviews)-1].UpdatedAt
+	stats.Newest = &newest
+	stats.Oldest = &oldest
+
+	return stats
+}
+
+// sentimentTrendDays is the number of days covered by the sentiment trend of Stats
+const sentimentTrendDays = 14
+
+// sentimentTrend returns the average sentiment per day of the last sentimentTrendDays days, today included
+func sentimentTrend(reviews models.AppStoreReviews, now time.Time) []SentimentPoint {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	first := today.AddDate(0, 0, -(sentimentTrendDays - 1))
+
+	trend := make([]SentimentPoint, sentimentTrendDays)
+	sums := make([]float64, sentimentTrendDays)
+	for i := range trend {
+		trend[i].Date = first.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+
+	// reviews are sorted by updatedAt in descending order
+	for _, review := range reviews {
+		updatedAt := review.UpdatedAt.UTC()
+		if updatedAt.Before(first) {
+			break
+		}
+		day := int(updatedAt.Sub(first).Hours() / 24)
+		if day >= sentimentTrendDays {
+			continue // clock skew, review from the future
+		}
+		trend[day].Count++
+		sums[day] += review.Sentiment
+	}
+
+	for i := range trend {
+		if trend[i].Count > 0 {
+			trend[i].AverageSentiment = math.Round(sums[i]/float64(trend[i].Count)*1000) / 1000
+		}
+	}
+	return trend
+}
+
+// Export writes all stored reviews to w, in the same JSON format as the storage file
+func (a *AppReviewsRepository) Export(w io.Writer) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "	")
+	return encoder.Encode(a.Reviews)
+}
+
+// Import reads reviews in the storage file JSON format from r and adds the new ones.
+// Returns the number of new reviews added
+func (a *AppReviewsRepository) Import(r io.Reader) (int, error) {
+	var reviews models.AppStoreReviews
+	if err := json.NewDecoder(r).Decode(&reviews); err != nil {
+		return 0, fmt.Errorf("decoding reviews: %w", err)
+	}
+
+	return a.AddBatch(reviews)
+}
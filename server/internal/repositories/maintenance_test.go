@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// TestVerify_WithValidReviews verifies that no issues are reported for well formed storage
+func TestVerify_WithValidReviews(t *testing.T) {
+	repo := &AppReviewsRepository{Reviews: createTestReviews()}
+
+	issues := repo.Verify()
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", issues)
+	}
+}
+
+// TestVerify_DetectsProblems verifies that sort order, duplicate IDs, zero timestamps and invalid ratings are reported
+func TestVerify_DetectsProblems(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &AppReviewsRepository{
+		Reviews: models.AppStoreReviews{
+			{ID: "review-1", Rating: 5, UpdatedAt: now.Add(-2 * time.Hour)},
+			{ID: "review-2", Rating: 4, UpdatedAt: now.Add(-1 * time.Hour)}, // out of order
+			{ID: "review-1", Rating: 3, UpdatedAt: now.Add(-3 * time.Hour)}, // duplicate
+			{ID: "review-3", Rating: 0, UpdatedAt: now.Add(-4 * time.Hour)}, // invalid rating
+			{ID: "review-4", Rating: 2},                                     // zero timestamp
+		},
+	}
+
+	issues := repo.Verify()
+
+	expectedProblems := map[int]string{
+		1: "not sorted",
+		2: "duplicate ID",
+		3: "rating 0 out of range",
+		4: "zero updatedAt",
+	}
+	if len(issues) != len(expectedProblems) {
+		t.Fatalf("Expected %d issues, got %d: %+v", len(expectedProblems), len(issues), issues)
+	}
+	for _, issue := range issues {
+		expected, ok := expectedProblems[issue.Index]
+		if !ok || !strings.Contains(issue.Problem, expected) {
+			t.Errorf("Unexpected issue at index %d: %s", issue.Index, issue.Problem)
+		}
+	}
+}
+
+// TestStats_WithReviews verifies the summary of stored reviews
+func TestStats_WithReviews(t *testing.T) {
+	testReviews := createTestReviews()
+	repo := &AppReviewsRepository{Reviews: testReviews}
+
+	stats := repo.Stats(true)
+
+	if stats.Count != 4 {
+		t.Errorf("Expected count 4, got %d", stats.Count)
+	}
+	if stats.AverageRating != 3.5 {
+		t.Errorf("Expected average rating 3.5, got %f", stats.AverageRating)
+	}
+	if stats.RatingCounts[5] != 1 || stats.RatingCounts[1] != 0 {
+		t.Errorf("Unexpected rating counts: %v", stats.RatingCounts)
+	}
+	if stats.Newest == nil || !stats.Newest.Equal(testReviews[0].UpdatedAt) {
+		t.Errorf("Expected newest to be %s, got %v", testReviews[0].UpdatedAt, stats.Newest)
+	}
+	if stats.Oldest == nil || !stats.Oldest.Equal(testReviews[3].UpdatedAt) {
+		t.Errorf("Expected oldest to be %s, got %v", testReviews[3].UpdatedAt, stats.Oldest)
+	}
+}
+
+// TestStats_WithEmptyRepository verifies the summary of an empty repository
+func TestStats_WithEmptyRepository(t *testing.T) {
+	repo := &AppReviewsRepository{Reviews: models.AppStoreReviews{}}
+
+	stats := repo.Stats(true)
+
+	if stats.Count != 0 || stats.AverageRating != 0 || stats.Newest != nil || stats.Oldest != nil {
+		t.Errorf("Expected zero stats, got %+v", stats)
+	}
+}
+
+// TestExportImport_RoundTrip verifies that exported reviews can be imported into another repository
+func TestExportImport_RoundTrip(t *testing.T) {
+	source := &AppReviewsRepository{Reviews: createTestReviews()}
+
+	var buf bytes.Buffer
+	if err := source.Export(&buf); err != nil {
+		t.Fatalf("Unexpected export error: %v", err)
+	}
+
+	target := &AppReviewsRepository{
+		Reviews:         models.AppStoreReviews{},
+		StorageFilePath: filepath.Join(t.TempDir(), "imported.json"),
+	}
+	added, err := target.Import(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	if added != len(source.Reviews) {
+		t.Errorf("Expected %d reviews imported, got %d", len(source.Reviews), added)
+	}
+
+	// importing again must not duplicate reviews
+	added, err = target.Import(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected import error: %v", err)
+	}
+	if added != 0 {
+		t.Errorf("Expected 0 reviews imported the second time, got %d", added)
+	}
+
+	reloaded := Load(target.StorageFilePath)
+	if len(reloaded.Reviews) != len(source.Reviews) {
+		t.Errorf("Expected %d reviews persisted, got %d", len(source.Reviews), len(reloaded.Reviews))
+	}
+}
+
+// TestImport_WithInvalidJSON verifies that invalid input is rejected
+func TestImport_WithInvalidJSON(t *testing.T) {
+	repo := &AppReviewsRepository{Reviews: models.AppStoreReviews{}}
+
+	_, err := repo.Import(strings.NewReader("{ invalid json"))
+	if err == nil {
+		t.Error("Expected error for invalid JSON, got nil")
+	}
+}
+
+// TestSentimentTrend_AveragesPerDay verifies the daily buckets of the sentiment trend
+func TestSentimentTrend_AveragesPerDay(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	reviews := models.AppStoreReviews{
+		{ID: "1", Sentiment: 0.5, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "2", Sentiment: -0.3, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "3", Sentiment: -0.8, UpdatedAt: now.Add(-24 * time.Hour)},
+		{ID: "4", Sentiment: 1, UpdatedAt: now.Add(-30 * 24 * time.Hour)},
+	}
+
+	trend := sentimentTrend(reviews, now)
+
+	if len(trend) != sentimentTrendDays {
+		t.Fatalf("Expected %d points, got %d", sentimentTrendDays, len(trend))
+	}
+	today, yesterday := trend[len(trend)-1], trend[len(trend)-2]
+	if today.Date != "2024-01-15" || today.Count != 2 || today.AverageSentiment != 0.1 {
+		t.Errorf("Unexpected point for today: %+v", today)
+	}
+	if yesterday.Count != 1 || yesterday.AverageSentiment != -0.8 {
+		t.Errorf("Unexpected point for yesterday: %+v", yesterday)
+	}
+	if trend[0].Date != "2024-01-02" || trend[0].Count != 0 {
+		t.Errorf("Expected the trend to start 13 days ago without reviews, got %+v", trend[0])
+	}
+}
+
+// TestBackfill_SavesChangedReviews verifies that backfilled fields are persisted
+func TestBackfill_SavesChangedReviews(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "reviews.json")
+	repo := Load(filePath)
+	repo.AddNewReviews(createTestReviews())
+
+	changed, err := repo.Backfill(func(review *models.AppStoreReview) bool {
+		if review.Rating != 5 {
+			return false
+		}
+		review.SentimentLabel = "positive"
+		return true
+	})
+	if err != nil || changed != 1 {
+		t.Fatalf("Expected 1 changed review, got %d (err %v)", changed, err)
+	}
+
+	reloaded := Load(filePath)
+	if stats := reloaded.Stats(true); stats.SentimentCounts["positive"] != 1 {
+		t.Errorf("Expected backfilled label to be persisted, got %v", stats.SentimentCounts)
+	}
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// maxNotifiedIDs bounds the IDs kept for deduplication, older reviews never come back from the feed
+const maxNotifiedIDs = 5000
+
+// PendingNotification is a review waiting to be notified
+type PendingNotification struct {
+	AppID  string                `json:"appId"`
+	Review models.AppStoreReview `json:"review"`
+}
+
+// NotificationsRepository remembers which reviews a notifier already sent, so restarts don't send them again,
+// and keeps the ones whose sending failed to retry them
+type NotificationsRepository struct {
+	mu              sync.Mutex
+	data            notificationsData
+	notified        map[string]bool
+	StorageFilePath string
+}
+
+type notificationsData struct {
+	NotifiedIDs []string              `json:"notifiedIds"` // oldest first
+	Pending     []PendingNotification `json:"pending"`
+}
+
+func LoadNotifications(storageFilePath string) *NotificationsRepository {
+	repo := &NotificationsRepository{
+		data: notificationsData{
+			NotifiedIDs: []string{},
+			Pending:     []PendingNotification{},
+		},
+		notified:        make(map[string]bool),
+		StorageFilePath: storageFilePath,
+	}
+
+	if err := loadJSONFile(storageFilePath, &repo.data); err != nil {
+		log.Printf("Error loading notifications from file: %v", err)
+		log.Printf("Starting with no notified reviews")
+	}
+	for _, id := range repo.data.NotifiedIDs {
+		repo.notified[id] = true
+	}
+
+	return repo
+}
+
+// AddPending queues the reviews not notified nor pending yet. Returns every pending notification
+func (n *NotificationsRepository) AddPending(appID string, reviews []models.AppStoreReview) ([]PendingNotification, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	added := false
+	for _, review := range reviews {
+		isPending := slices.ContainsFunc(n.data.Pending, func(p PendingNotification) bool { return p.Review.ID == review.ID })
+		if n.notified[review.ID] || isPending {
+			continue
+		}
+		n.data.Pending = append(n.data.Pending, PendingNotification{AppID: appID, Review: review})
+		added = true
+	}
+
+	if added {
+		if err := saveJSONFile(n.StorageFilePath, n.data); err != nil {
+			return slices.Clone(n.data.Pending), err
+		}
+	}
+	return slices.Clone(n.data.Pending), nil
+}
+
+// Pending returns the notifications waiting to be sent
+func (n *NotificationsRepository) Pending() []PendingNotification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.data.Pending)
+}
+
+// MarkNotified removes the given reviews from the pending ones and remembers them as notified
+func (n *NotificationsRepository) MarkNotified(reviewIDs []string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.data.Pending = slices.DeleteFunc(n.data.Pending, func(p PendingNotification) bool {
+		return slices.Contains(reviewIDs, p.Review.ID)
+	})
+	for _, id := range reviewIDs {
+		if !n.notified[id] {
+			n.notified[id] = true
+			n.data.NotifiedIDs = append(n.data.NotifiedIDs, id)
+		}
+	}
+
+	if overflow := len(n.data.NotifiedIDs) - maxNotifiedIDs; overflow > 0 {
+		for _, id := range n.data.NotifiedIDs[:overflow] {
+			delete(n.notified, id)
+		}
+		n.data.NotifiedIDs = slices.Clone(n.data.NotifiedIDs[overflow:])
+	}
+
+	return saveJSONFile(n.StorageFilePath, n.data)
+}
+
+// WasNotified reports whether a review was already notified
+func (n *NotificationsRepository) WasNotified(reviewID string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.notified[reviewID]
+}
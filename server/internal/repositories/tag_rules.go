@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// TagRulesRepository stores the tag rules created through the API, config rules are not stored
+type TagRulesRepository struct {
+	mu              sync.Mutex
+	rules           []models.TagRule
+	StorageFilePath string
+}
+
+func LoadTagRules(storageFilePath string) *TagRulesRepository {
+	repo := &TagRulesRepository{
+		rules:           []models.TagRule{},
+		StorageFilePath: storageFilePath,
+	}
+
+	if err := loadJSONFile(storageFilePath, &repo.rules); err != nil {
+		log.Printf("Error loading tag rules from file: %v", err)
+		log.Printf("Starting with no tag rules")
+	}
+
+	return repo
+}
+
+func (r *TagRulesRepository) List() []models.TagRule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.rules)
+}
+
+func (r *TagRulesRepository) Add(rule models.TagRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules = append(r.rules, rule)
+	return saveJSONFile(r.StorageFilePath, r.rules)
+}
+
+// Delete removes a rule. Returns false if it doesn't exist
+func (r *TagRulesRepository) Delete(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := slices.IndexFunc(r.rules, func(rule models.TagRule) bool { return rule.ID == id })
+	if index < 0 {
+		return false, nil
+	}
+
+	r.rules = slices.Delete(r.rules, index, index+1)
+	return true, saveJSONFile(r.StorageFilePath, r.rules)
+}
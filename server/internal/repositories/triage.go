@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// TriageRepository stores the triage of reviews, apart from the reviews so polling never overwrites it
+type TriageRepository struct {
+	mu              sync.RWMutex
+	records         map[string]models.Triage // by triageKey
+	StorageFilePath string
+	version         uint64 // bumped by every update
+	modified        time.Time
+}
+
+func LoadTriage(storageFilePath string) *TriageRepository {
+	repo := &TriageRepository{
+		records:         map[string]models.Triage{},
+		StorageFilePath: storageFilePath,
+	}
+
+	var records []models.Triage
+	if err := loadJSONFile(storageFilePath, &records); err != nil {
+		log.Printf("Error loading triage from file: %v", err)
+		log.Printf("Starting with no triage")
+	}
+	for _, record := range records {
+		repo.records[triageKey(record.AppID, record.ReviewID)] = record
+		if record.UpdatedAt != nil && record.UpdatedAt.After(repo.modified) {
+			repo.modified = *record.UpdatedAt
+		}
+	}
+	// versions start from the last update, so they keep increasing across restarts
+	if !repo.modified.IsZero() {
+		repo.version = uint64(repo.modified.UnixNano())
+	}
+
+	return repo
+}
+
+func triageKey(appID, reviewID string) string {
+	return appID + "/" + reviewID
+}
+
+// Get returns the triage of a review, a new one if the review was never triaged
+func (r *TriageRepository) Get(appID, reviewID string) models.Triage {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.records[triageKey(appID, reviewID)]
+	if !ok {
+		return models.NewTriage(appID, reviewID)
+	}
+	return cloneTriage(record)
+}
+
+// Update applies fn to the triage of a review and stores the result. Nothing is stored when fn returns an error.
+func (r *TriageRepository) Update(appID, reviewID string, fn func(record *models.Triage) error) (models.Triage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := triageKey(appID, reviewID)
+	record, ok := r.records[key]
+	if !ok {
+		record = models.NewTriage(appID, reviewID)
+	}
+	record = cloneTriage(record)
+	if err := fn(&record); err != nil {
+		return models.Triage{}, err
+	}
+
+	previous, existed := r.records[key]
+	r.records[key] = record
+	if err := r.save(); err != nil {
+		if existed {
+			r.records[key] = previous
+		} else {
+			delete(r.records, key)
+		}
+		return models.Triage{}, err
+	}
+	r.version++
+	r.modified = time.Now().UTC()
+	return cloneTriage(record), nil
+}
+
+// Version returns the data version of the triage records, which increases with every update, and when it last changed
+func (r *TriageRepository) Version() (uint64, time.Time) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version, r.modified
+}
+
+// save writes the records sorted by app and review ID, so the file diffs well. Callers must hold r.mu.
+func (r *TriageRepository) save() error {
+	keys := make([]string, 0, len(r.records))
+	for key := range r.records {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	records := make([]models.Triage, len(keys))
+	for i, key := range keys {
+		records[i] = r.records[key]
+	}
+	return saveJSONFile(r.StorageFilePath, records)
+}
+
+// cloneTriage copies the slices of record, so callers can't modify the stored one
+func cloneTriage(record models.Triage) models.Triage {
+	record.Labels = slices.Clone(record.Labels)
+	record.Notes = slices.Clone(record.Notes)
+	return record
+}
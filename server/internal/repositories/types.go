@@ -1,5 +1,89 @@
 package repositories
 
+import (
+	"slices"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/language"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
 type ReviewFilter struct {
-	Rating *int
+	Rating    *int
+	Sentiment string // sentiment label, empty for any
+	Tag       string // topic tag, empty for any
+	Language  string // language code, empty for any
+	Query     string // words the title or content must all contain, accents ignored
+	Flagged   *bool  // only flagged (true) or unflagged (false) reviews, nil for any
+
+	// Triage filters, ignored by Matches and applied by the app service, which holds the triage records
+	Status   string // triage status, empty for any
+	Assignee string // triage assignee, empty for any
+}
+
+// HasTriage reports whether the filter has triage conditions
+func (f ReviewFilter) HasTriage() bool {
+	return f.Status != "" || f.Assignee != ""
+}
+
+// Matches reports whether review passes every filter
+func (f ReviewFilter) Matches(review models.AppStoreReview) bool {
+	if f.Rating != nil && review.Rating != *f.Rating {
+		return false
+	}
+	if f.Sentiment != "" && review.SentimentLabel != f.Sentiment {
+		return false
+	}
+	if f.Tag != "" && !slices.Contains(review.Tags, f.Tag) {
+		return false
+	}
+	if f.Language != "" && review.Language != f.Language {
+		return false
+	}
+	if f.Flagged != nil && review.IsFlagged() != *f.Flagged {
+		return false
+	}
+	return f.Query == "" || containsTerms(review, f.Query)
+}
+
+// containsTerms reports whether the title or content of review contain every word of query,
+// both tokenized for the review language
+func containsTerms(review models.AppStoreReview, query string) bool {
+	terms := language.SearchTerms(review.Title+"\n"+review.Content, review.Language)
+	for _, term := range language.SearchTerms(query, review.Language) {
+		if !slices.Contains(terms, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// IntegrityIssue describes a problem found in the stored reviews
+type IntegrityIssue struct {
+	Index    int    `json:"index"`
+	ReviewID string `json:"reviewId"`
+	Problem  string `json:"problem"`
+}
+
+// ReviewStats summarizes the stored reviews
+type ReviewStats struct {
+	Count         int         `json:"count"`
+	FlaggedCount  int         `json:"flaggedCount"` // flagged reviews, left out of the other fields unless included
+	AverageRating float64     `json:"averageRating"`
+	RatingCounts  map[int]int `json:"ratingCounts"`
+	Oldest        *time.Time  `json:"oldest,omitempty"`
+	Newest        *time.Time  `json:"newest,omitempty"`
+
+	AverageSentiment float64          `json:"averageSentiment"`
+	SentimentCounts  map[string]int   `json:"sentimentCounts"` // by sentiment label
+	SentimentTrend   []SentimentPoint `json:"sentimentTrend"`  // one point per day of the last sentimentTrendDays, oldest first
+
+	TagCounts map[string]int `json:"tagCounts"` // number of reviews per topic tag
+}
+
+// SentimentPoint is the sentiment of the reviews of a day (UTC)
+type SentimentPoint struct {
+	Date             string  `json:"date"`
+	Count            int     `json:"count"`
+	AverageSentiment float64 `json:"averageSentiment"` // zero when there are no reviews
 }
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// ViewsRepository stores the saved views, in creation order
+type ViewsRepository struct {
+	mu              sync.Mutex
+	views           []models.View
+	StorageFilePath string
+}
+
+func LoadViews(storageFilePath string) *ViewsRepository {
+	repo := &ViewsRepository{
+		views:           []models.View{},
+		StorageFilePath: storageFilePath,
+	}
+
+	if err := loadJSONFile(storageFilePath, &repo.views); err != nil {
+		log.Printf("Error loading views from file: %v", err)
+		log.Printf("Starting with no views")
+	}
+
+	return repo
+}
+
+func (r *ViewsRepository) List() []models.View {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.views)
+}
+
+// Get returns a view, false if it doesn't exist
+func (r *ViewsRepository) Get(id string) (models.View, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := r.indexOf(id)
+	if index < 0 {
+		return models.View{}, false
+	}
+	return r.views[index], true
+}
+
+func (r *ViewsRepository) Add(view models.View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.views = append(r.views, view)
+	return saveJSONFile(r.StorageFilePath, r.views)
+}
+
+// Update replaces the view with the same ID. Returns false if it doesn't exist
+func (r *ViewsRepository) Update(view models.View) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := r.indexOf(view.ID)
+	if index < 0 {
+		return false, nil
+	}
+
+	r.views[index] = view
+	return true, saveJSONFile(r.StorageFilePath, r.views)
+}
+
+// Delete removes a view. Returns false if it doesn't exist
+func (r *ViewsRepository) Delete(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := r.indexOf(id)
+	if index < 0 {
+		return false, nil
+	}
+
+	r.views = slices.Delete(r.views, index, index+1)
+	return true, saveJSONFile(r.StorageFilePath, r.views)
+}
+
+func (r *ViewsRepository) indexOf(id string) int {
+	return slices.IndexFunc(r.views, func(view models.View) bool { return view.ID == id })
+}
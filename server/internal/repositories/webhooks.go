@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// WebhooksRepository stores webhook subscriptions, the outbox of pending deliveries and the dead letters
+type WebhooksRepository struct {
+	mu              sync.Mutex
+	data            webhooksData
+	StorageFilePath string
+}
+
+type webhooksData struct {
+	Subscriptions []models.WebhookSubscription `json:"subscriptions"`
+	Outbox        []models.WebhookDelivery     `json:"outbox"`
+	DeadLetters   []models.WebhookDelivery     `json:"deadLetters"`
+}
+
+func LoadWebhooks(storageFilePath string) *WebhooksRepository {
+	repo := &WebhooksRepository{
+		data: webhooksData{
+			Subscriptions: []models.WebhookSubscription{},
+			Outbox:        []models.WebhookDelivery{},
+			DeadLetters:   []models.WebhookDelivery{},
+		},
+		StorageFilePath: storageFilePath,
+	}
+
+	if err := loadJSONFile(storageFilePath, &repo.data); err != nil {
+		log.Printf("Error loading webhooks from file: %v", err)
+		log.Printf("Starting with no webhooks")
+	}
+
+	return repo
+}
+
+func (w *WebhooksRepository) ListSubscriptions() []models.WebhookSubscription {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.data.Subscriptions)
+}
+
+func (w *WebhooksRepository) AddSubscription(subscription models.WebhookSubscription) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.data.Subscriptions = append(w.data.Subscriptions, subscription)
+	return w.saveToFile()
+}
+
+// DeleteSubscription removes a subscription and its pending deliveries. Returns false if it doesn't exist
+func (w *WebhooksRepository) DeleteSubscription(id string) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	index := slices.IndexFunc(w.data.Subscriptions, func(s models.WebhookSubscription) bool { return s.ID == id })
+	if index < 0 {
+		return false, nil
+	}
+
+	w.data.Subscriptions = slices.Delete(w.data.Subscriptions, index, index+1)
+	w.data.Outbox = slices.DeleteFunc(w.data.Outbox, func(d models.WebhookDelivery) bool { return d.SubscriptionID == id })
+	return true, w.saveToFile()
+}
+
+// Enqueue adds deliveries to the outbox
+func (w *WebhooksRepository) Enqueue(deliveries []models.WebhookDelivery) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.data.Outbox = append(w.data.Outbox, deliveries...)
+	return w.saveToFile()
+}
+
+// ListOutbox returns the pending deliveries
+func (w *WebhooksRepository) ListOutbox() []models.WebhookDelivery {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.data.Outbox)
+}
+
+// DueDeliveries returns the pending deliveries whose next attempt is due at now
+func (w *WebhooksRepository) DueDeliveries(now time.Time) []models.WebhookDelivery {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	due := make([]models.WebhookDelivery, 0)
+	for _, delivery := range w.data.Outbox {
+		if !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due
+}
+
+// CompleteDelivery removes a successfully sent delivery from the outbox
+func (w *WebhooksRepository) CompleteDelivery(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.data.Outbox = slices.DeleteFunc(w.data.Outbox, func(d models.WebhookDelivery) bool { return d.ID == id })
+	return w.saveToFile()
+}
+
+// RescheduleDelivery updates a failed delivery that will be retried
+func (w *WebhooksRepository) RescheduleDelivery(delivery models.WebhookDelivery) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	index := slices.IndexFunc(w.data.Outbox, func(d models.WebhookDelivery) bool { return d.ID == delivery.ID })
+	if index < 0 {
+		return nil // subscription deleted while the delivery was in flight
+	}
+	w.data.Outbox[index] = delivery
+	return w.saveToFile()
+}
+
+// DeadLetter moves a delivery that won't be retried anymore from the outbox to the dead letters
+func (w *WebhooksRepository) DeadLetter(delivery models.WebhookDelivery) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.data.Outbox = slices.DeleteFunc(w.data.Outbox, func(d models.WebhookDelivery) bool { return d.ID == delivery.ID })
+	w.data.DeadLetters = append(w.data.DeadLetters, delivery)
+	return w.saveToFile()
+}
+
+// ListDeadLetters returns the deliveries that exhausted their retries
+func (w *WebhooksRepository) ListDeadLetters() []models.WebhookDelivery {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.data.DeadLetters)
+}
+
+// RequeueDeadLetter moves a dead letter back to the outbox, due at now with its attempts reset.
+// Returns false if it doesn't exist
+func (w *WebhooksRepository) RequeueDeadLetter(id string, now time.Time) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	index := slices.IndexFunc(w.data.DeadLetters, func(d models.WebhookDelivery) bool { return d.ID == id })
+	if index < 0 {
+		return false, nil
+	}
+
+	delivery := w.data.DeadLetters[index]
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	w.data.DeadLetters = slices.Delete(w.data.DeadLetters, index, index+1)
+	w.data.Outbox = append(w.data.Outbox, delivery)
+	return true, w.saveToFile()
+}
+
+// saveToFile persists subscriptions, outbox and dead letters to the JSON file
+func (w *WebhooksRepository) saveToFile() error {
+	return saveJSONFile(w.StorageFilePath, w.data)
+}
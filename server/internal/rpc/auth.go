@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authenticator checks the API key of every call, as middleware.RequireRole does for HTTP routes.
+// The ReviewsService only reads, so a read-only key is enough; server reflection needs one too.
+type authenticator struct {
+	appService AppService
+}
+
+func (a authenticator) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := a.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a authenticator) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authenticate(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (a authenticator) authenticate(ctx context.Context) error {
+	if !a.appService.AuthEnabled() {
+		return nil
+	}
+
+	secret := callAPIKey(ctx)
+	if secret == "" {
+		return status.Error(codes.Unauthenticated, "API key required")
+	}
+	key, ok := a.appService.APIKeys().Authenticate(secret)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "Invalid or revoked API key")
+	}
+	if !models.RoleAllows(key.Role, models.RoleReadOnly) {
+		return status.Error(codes.PermissionDenied, "API key role "+key.Role+" can't read reviews")
+	}
+	return nil
+}
+
+// callAPIKey reads the key from the x-api-key metadata or a bearer token in authorization
+func callAPIKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get("x-api-key"); len(keys) > 0 && keys[0] != "" {
+		return strings.TrimSpace(keys[0])
+	}
+	for _, authorization := range md.Get("authorization") {
+		scheme, token, found := strings.Cut(authorization, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,1132 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: reviewsv1/reviews.proto
+
+// Reviews of the tracked App Store apps, for internal services. It mirrors the /v1 HTTP API.
+
+package reviewsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ReviewSort int32
+
+const (
+	// Newest first
+	ReviewSort_REVIEW_SORT_UNSPECIFIED ReviewSort = 0
+	ReviewSort_REVIEW_SORT_NEWEST      ReviewSort = 1
+	ReviewSort_REVIEW_SORT_OLDEST      ReviewSort = 2
+	// Most negative first
+	ReviewSort_REVIEW_SORT_SENTIMENT_ASC  ReviewSort = 3
+	ReviewSort_REVIEW_SORT_SENTIMENT_DESC ReviewSort = 4
+)
+
+// Enum value maps for ReviewSort.
+var (
+	ReviewSort_name = map[int32]string{
+		0: "REVIEW_SORT_UNSPECIFIED",
+		1: "REVIEW_SORT_NEWEST",
+		2: "REVIEW_SORT_OLDEST",
+		3: "REVIEW_SORT_SENTIMENT_ASC",
+		4: "REVIEW_SORT_SENTIMENT_DESC",
+	}
+	ReviewSort_value = map[string]int32{
+		"REVIEW_SORT_UNSPECIFIED":    0,
+		"REVIEW_SORT_NEWEST":         1,
+		"REVIEW_SORT_OLDEST":         2,
+		"REVIEW_SORT_SENTIMENT_ASC":  3,
+		"REVIEW_SORT_SENTIMENT_DESC": 4,
+	}
+)
+
+func (x ReviewSort) Enum() *ReviewSort {
+	p := new(ReviewSort)
+	*p = x
+	return p
+}
+
+func (x ReviewSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReviewSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_reviewsv1_reviews_proto_enumTypes[0].Descriptor()
+}
+
+func (ReviewSort) Type() protoreflect.EnumType {
+	return &file_reviewsv1_reviews_proto_enumTypes[0]
+}
+
+func (x ReviewSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReviewSort.Descriptor instead.
+func (ReviewSort) EnumDescriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{0}
+}
+
+type Review struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AppId     string                 `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Title     string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content   string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Author    string                 `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	Rating    int32                  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Lexicon score of the title and content, from -1 to 1
+	Sentiment float64 `protobuf:"fixed64,8,opt,name=sentiment,proto3" json:"sentiment,omitempty"`
+	// positive, neutral or negative
+	SentimentLabel string `protobuf:"bytes,9,opt,name=sentiment_label,json=sentimentLabel,proto3" json:"sentiment_label,omitempty"`
+	// ISO 639-1 code, und when undetermined
+	Language string   `protobuf:"bytes,10,opt,name=language,proto3" json:"language,omitempty"`
+	Tags     []string `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Reasons the review looks like spam
+	Flags         []*ReviewFlag `protobuf:"bytes,12,rep,name=flags,proto3" json:"flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *Review) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Review) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Review) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Review) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Review) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Review) GetSentiment() float64 {
+	if x != nil {
+		return x.Sentiment
+	}
+	return 0
+}
+
+func (x *Review) GetSentimentLabel() string {
+	if x != nil {
+		return x.SentimentLabel
+	}
+	return ""
+}
+
+func (x *Review) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Review) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Review) GetFlags() []*ReviewFlag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+type ReviewFlag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Detail        string                 `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewFlag) Reset() {
+	*x = ReviewFlag{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewFlag) ProtoMessage() {}
+
+func (x *ReviewFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewFlag.ProtoReflect.Descriptor instead.
+func (*ReviewFlag) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReviewFlag) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReviewFlag) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// The filters of GET /v1/reviews, empty fields matching every review
+type ReviewFilter struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Rating    *int32                 `protobuf:"varint,1,opt,name=rating,proto3,oneof" json:"rating,omitempty"`
+	Sentiment string                 `protobuf:"bytes,2,opt,name=sentiment,proto3" json:"sentiment,omitempty"`
+	Tag       string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Words the title or content must all contain, accents ignored
+	Query    string `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	Language string `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`
+	// Triage status: new, acknowledged, resolved or ignored
+	Status        string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Assignee      string `protobuf:"bytes,7,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewFilter) Reset() {
+	*x = ReviewFilter{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewFilter) ProtoMessage() {}
+
+func (x *ReviewFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewFilter.ProtoReflect.Descriptor instead.
+func (*ReviewFilter) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReviewFilter) GetRating() int32 {
+	if x != nil && x.Rating != nil {
+		return *x.Rating
+	}
+	return 0
+}
+
+func (x *ReviewFilter) GetSentiment() string {
+	if x != nil {
+		return x.Sentiment
+	}
+	return ""
+}
+
+func (x *ReviewFilter) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ReviewFilter) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ReviewFilter) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ReviewFilter) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReviewFilter) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+type ListReviewsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A tracked app, the primary one when empty
+	AppId string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	// Reviews of the last hours, from 1 to 96, 48 when zero
+	Hours  int32         `protobuf:"varint,2,opt,name=hours,proto3" json:"hours,omitempty"`
+	Filter *ReviewFilter `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	Sort   ReviewSort    `protobuf:"varint,4,opt,name=sort,proto3,enum=reviews.v1.ReviewSort" json:"sort,omitempty"`
+	// At most 100, 20 when zero
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page
+	PageToken     string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRequest) Reset() {
+	*x = ListReviewsRequest{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRequest) ProtoMessage() {}
+
+func (x *ListReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListReviewsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *ListReviewsRequest) GetHours() int32 {
+	if x != nil {
+		return x.Hours
+	}
+	return 0
+}
+
+func (x *ListReviewsRequest) GetFilter() *ReviewFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListReviewsRequest) GetSort() ReviewSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ReviewSort_REVIEW_SORT_UNSPECIFIED
+}
+
+func (x *ListReviewsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReviewsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListReviewsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Reviews []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	// Empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Reviews matching the filter, on every page
+	TotalSize     int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsResponse) Reset() {
+	*x = ListReviewsResponse{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsResponse) ProtoMessage() {}
+
+func (x *ListReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListReviewsResponse) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *ListReviewsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListReviewsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type GetReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppId         string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReviewRequest) Reset() {
+	*x = GetReviewRequest{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewRequest) ProtoMessage() {}
+
+func (x *GetReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewRequest.ProtoReflect.Descriptor instead.
+func (*GetReviewRequest) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetReviewRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *GetReviewRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	AppId string                 `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	// Count the reviews flagged as spam in the statistics
+	IncludeFlagged bool `protobuf:"varint,2,opt,name=include_flagged,json=includeFlagged,proto3" json:"include_flagged,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStatsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *GetStatsRequest) GetIncludeFlagged() bool {
+	if x != nil {
+		return x.IncludeFlagged
+	}
+	return false
+}
+
+type ReviewStats struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Count            int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	FlaggedCount     int32                  `protobuf:"varint,2,opt,name=flagged_count,json=flaggedCount,proto3" json:"flagged_count,omitempty"`
+	AverageRating    float64                `protobuf:"fixed64,3,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
+	RatingCounts     map[int32]int32        `protobuf:"bytes,4,rep,name=rating_counts,json=ratingCounts,proto3" json:"rating_counts,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Oldest           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=oldest,proto3" json:"oldest,omitempty"`
+	Newest           *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=newest,proto3" json:"newest,omitempty"`
+	AverageSentiment float64                `protobuf:"fixed64,7,opt,name=average_sentiment,json=averageSentiment,proto3" json:"average_sentiment,omitempty"`
+	// By sentiment label
+	SentimentCounts map[string]int32 `protobuf:"bytes,8,rep,name=sentiment_counts,json=sentimentCounts,proto3" json:"sentiment_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// One point per day of the last two weeks, oldest first
+	SentimentTrend []*SentimentPoint `protobuf:"bytes,9,rep,name=sentiment_trend,json=sentimentTrend,proto3" json:"sentiment_trend,omitempty"`
+	TagCounts      map[string]int32  `protobuf:"bytes,10,rep,name=tag_counts,json=tagCounts,proto3" json:"tag_counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReviewStats) Reset() {
+	*x = ReviewStats{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewStats) ProtoMessage() {}
+
+func (x *ReviewStats) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewStats.ProtoReflect.Descriptor instead.
+func (*ReviewStats) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReviewStats) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReviewStats) GetFlaggedCount() int32 {
+	if x != nil {
+		return x.FlaggedCount
+	}
+	return 0
+}
+
+func (x *ReviewStats) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
+func (x *ReviewStats) GetRatingCounts() map[int32]int32 {
+	if x != nil {
+		return x.RatingCounts
+	}
+	return nil
+}
+
+func (x *ReviewStats) GetOldest() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Oldest
+	}
+	return nil
+}
+
+func (x *ReviewStats) GetNewest() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Newest
+	}
+	return nil
+}
+
+func (x *ReviewStats) GetAverageSentiment() float64 {
+	if x != nil {
+		return x.AverageSentiment
+	}
+	return 0
+}
+
+func (x *ReviewStats) GetSentimentCounts() map[string]int32 {
+	if x != nil {
+		return x.SentimentCounts
+	}
+	return nil
+}
+
+func (x *ReviewStats) GetSentimentTrend() []*SentimentPoint {
+	if x != nil {
+		return x.SentimentTrend
+	}
+	return nil
+}
+
+func (x *ReviewStats) GetTagCounts() map[string]int32 {
+	if x != nil {
+		return x.TagCounts
+	}
+	return nil
+}
+
+// The sentiment of the reviews of a day (UTC)
+type SentimentPoint struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// YYYY-MM-DD
+	Date             string  `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Count            int32   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	AverageSentiment float64 `protobuf:"fixed64,3,opt,name=average_sentiment,json=averageSentiment,proto3" json:"average_sentiment,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SentimentPoint) Reset() {
+	*x = SentimentPoint{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SentimentPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SentimentPoint) ProtoMessage() {}
+
+func (x *SentimentPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SentimentPoint.ProtoReflect.Descriptor instead.
+func (*SentimentPoint) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SentimentPoint) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *SentimentPoint) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SentimentPoint) GetAverageSentiment() float64 {
+	if x != nil {
+		return x.AverageSentiment
+	}
+	return 0
+}
+
+type WatchReviewsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only the reviews of this app, every tracked app when empty
+	AppId  string `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Rating *int32 `protobuf:"varint,2,opt,name=rating,proto3,oneof" json:"rating,omitempty"`
+	// Resume after this event
+	LastEventId   uint64 `protobuf:"varint,3,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchReviewsRequest) Reset() {
+	*x = WatchReviewsRequest{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchReviewsRequest) ProtoMessage() {}
+
+func (x *WatchReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchReviewsRequest.ProtoReflect.Descriptor instead.
+func (*WatchReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchReviewsRequest) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *WatchReviewsRequest) GetRating() int32 {
+	if x != nil && x.Rating != nil {
+		return *x.Rating
+	}
+	return 0
+}
+
+func (x *WatchReviewsRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type ReviewEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Increases by one on every event, and across restarts
+	Id          uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AppId       string                 `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	Review      *Review                `protobuf:"bytes,3,opt,name=review,proto3" json:"review,omitempty"`
+	PublishedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	// Set, without a review, when the events after last_event_id are gone: the client reloads what it shows
+	// and resumes from id
+	HistoryReset  bool `protobuf:"varint,5,opt,name=history_reset,json=historyReset,proto3" json:"history_reset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewEvent) Reset() {
+	*x = ReviewEvent{}
+	mi := &file_reviewsv1_reviews_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewEvent) ProtoMessage() {}
+
+func (x *ReviewEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewsv1_reviews_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewEvent.ProtoReflect.Descriptor instead.
+func (*ReviewEvent) Descriptor() ([]byte, []int) {
+	return file_reviewsv1_reviews_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReviewEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReviewEvent) GetAppId() string {
+	if x != nil {
+		return x.AppId
+	}
+	return ""
+}
+
+func (x *ReviewEvent) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *ReviewEvent) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *ReviewEvent) GetHistoryReset() bool {
+	if x != nil {
+		return x.HistoryReset
+	}
+	return false
+}
+
+var File_reviewsv1_reviews_proto protoreflect.FileDescriptor
+
+const file_reviewsv1_reviews_proto_rawDesc = "" +
+	"\n" +
+	"\x17reviewsv1/reviews.proto\x12\n" +
+	"reviews.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x02\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\tR\x05appId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x16\n" +
+	"\x06author\x18\x05 \x01(\tR\x06author\x12\x16\n" +
+	"\x06rating\x18\x06 \x01(\x05R\x06rating\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1c\n" +
+	"\tsentiment\x18\b \x01(\x01R\tsentiment\x12'\n" +
+	"\x0fsentiment_label\x18\t \x01(\tR\x0esentimentLabel\x12\x1a\n" +
+	"\blanguage\x18\n" +
+	" \x01(\tR\blanguage\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12,\n" +
+	"\x05flags\x18\f \x03(\v2\x16.reviews.v1.ReviewFlagR\x05flags\"<\n" +
+	"\n" +
+	"ReviewFlag\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06detail\x18\x02 \x01(\tR\x06detail\"\xcc\x01\n" +
+	"\fReviewFilter\x12\x1b\n" +
+	"\x06rating\x18\x01 \x01(\x05H\x00R\x06rating\x88\x01\x01\x12\x1c\n" +
+	"\tsentiment\x18\x02 \x01(\tR\tsentiment\x12\x10\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12\x1a\n" +
+	"\blanguage\x18\x05 \x01(\tR\blanguage\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1a\n" +
+	"\bassignee\x18\a \x01(\tR\bassigneeB\t\n" +
+	"\a_rating\"\xdb\x01\n" +
+	"\x12ListReviewsRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x14\n" +
+	"\x05hours\x18\x02 \x01(\x05R\x05hours\x120\n" +
+	"\x06filter\x18\x03 \x01(\v2\x18.reviews.v1.ReviewFilterR\x06filter\x12*\n" +
+	"\x04sort\x18\x04 \x01(\x0e2\x16.reviews.v1.ReviewSortR\x04sort\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"\x8a\x01\n" +
+	"\x13ListReviewsResponse\x12,\n" +
+	"\areviews\x18\x01 \x03(\v2\x12.reviews.v1.ReviewR\areviews\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"9\n" +
+	"\x10GetReviewRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"Q\n" +
+	"\x0fGetStatsRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12'\n" +
+	"\x0finclude_flagged\x18\x02 \x01(\bR\x0eincludeFlagged\"\xfc\x05\n" +
+	"\vReviewStats\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\x12#\n" +
+	"\rflagged_count\x18\x02 \x01(\x05R\fflaggedCount\x12%\n" +
+	"\x0eaverage_rating\x18\x03 \x01(\x01R\raverageRating\x12N\n" +
+	"\rrating_counts\x18\x04 \x03(\v2).reviews.v1.ReviewStats.RatingCountsEntryR\fratingCounts\x122\n" +
+	"\x06oldest\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06oldest\x122\n" +
+	"\x06newest\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06newest\x12+\n" +
+	"\x11average_sentiment\x18\a \x01(\x01R\x10averageSentiment\x12W\n" +
+	"\x10sentiment_counts\x18\b \x03(\v2,.reviews.v1.ReviewStats.SentimentCountsEntryR\x0fsentimentCounts\x12C\n" +
+	"\x0fsentiment_trend\x18\t \x03(\v2\x1a.reviews.v1.SentimentPointR\x0esentimentTrend\x12E\n" +
+	"\n" +
+	"tag_counts\x18\n" +
+	" \x03(\v2&.reviews.v1.ReviewStats.TagCountsEntryR\ttagCounts\x1a?\n" +
+	"\x11RatingCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aB\n" +
+	"\x14SentimentCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a<\n" +
+	"\x0eTagCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"g\n" +
+	"\x0eSentimentPoint\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12+\n" +
+	"\x11average_sentiment\x18\x03 \x01(\x01R\x10averageSentiment\"x\n" +
+	"\x13WatchReviewsRequest\x12\x15\n" +
+	"\x06app_id\x18\x01 \x01(\tR\x05appId\x12\x1b\n" +
+	"\x06rating\x18\x02 \x01(\x05H\x00R\x06rating\x88\x01\x01\x12\"\n" +
+	"\rlast_event_id\x18\x03 \x01(\x04R\vlastEventIdB\t\n" +
+	"\a_rating\"\xc4\x01\n" +
+	"\vReviewEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x15\n" +
+	"\x06app_id\x18\x02 \x01(\tR\x05appId\x12*\n" +
+	"\x06review\x18\x03 \x01(\v2\x12.reviews.v1.ReviewR\x06review\x12=\n" +
+	"\fpublished_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12#\n" +
+	"\rhistory_reset\x18\x05 \x01(\bR\fhistoryReset*\x98\x01\n" +
+	"\n" +
+	"ReviewSort\x12\x1b\n" +
+	"\x17REVIEW_SORT_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12REVIEW_SORT_NEWEST\x10\x01\x12\x16\n" +
+	"\x12REVIEW_SORT_OLDEST\x10\x02\x12\x1d\n" +
+	"\x19REVIEW_SORT_SENTIMENT_ASC\x10\x03\x12\x1e\n" +
+	"\x1aREVIEW_SORT_SENTIMENT_DESC\x10\x042\xad\x02\n" +
+	"\x0eReviewsService\x12N\n" +
+	"\vListReviews\x12\x1e.reviews.v1.ListReviewsRequest\x1a\x1f.reviews.v1.ListReviewsResponse\x12=\n" +
+	"\tGetReview\x12\x1c.reviews.v1.GetReviewRequest\x1a\x12.reviews.v1.Review\x12@\n" +
+	"\bGetStats\x12\x1b.reviews.v1.GetStatsRequest\x1a\x17.reviews.v1.ReviewStats\x12J\n" +
+	"\fWatchReviews\x12\x1f.reviews.v1.WatchReviewsRequest\x1a\x17.reviews.v1.ReviewEvent0\x01BPZNgithub.com/Gust4voSales/appstore-rss-reviews-app/server/internal/rpc/reviewsv1b\x06proto3"
+
+var (
+	file_reviewsv1_reviews_proto_rawDescOnce sync.Once
+	file_reviewsv1_reviews_proto_rawDescData []byte
+)
+
+func file_reviewsv1_reviews_proto_rawDescGZIP() []byte {
+	file_reviewsv1_reviews_proto_rawDescOnce.Do(func() {
+		file_reviewsv1_reviews_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewsv1_reviews_proto_rawDesc), len(file_reviewsv1_reviews_proto_rawDesc)))
+	})
+	return file_reviewsv1_reviews_proto_rawDescData
+}
+
+var file_reviewsv1_reviews_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_reviewsv1_reviews_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_reviewsv1_reviews_proto_goTypes = []any{
+	(ReviewSort)(0),               // 0: reviews.v1.ReviewSort
+	(*Review)(nil),                // 1: reviews.v1.Review
+	(*ReviewFlag)(nil),            // 2: reviews.v1.ReviewFlag
+	(*ReviewFilter)(nil),          // 3: reviews.v1.ReviewFilter
+	(*ListReviewsRequest)(nil),    // 4: reviews.v1.ListReviewsRequest
+	(*ListReviewsResponse)(nil),   // 5: reviews.v1.ListReviewsResponse
+	(*GetReviewRequest)(nil),      // 6: reviews.v1.GetReviewRequest
+	(*GetStatsRequest)(nil),       // 7: reviews.v1.GetStatsRequest
+	(*ReviewStats)(nil),           // 8: reviews.v1.ReviewStats
+	(*SentimentPoint)(nil),        // 9: reviews.v1.SentimentPoint
+	(*WatchReviewsRequest)(nil),   // 10: reviews.v1.WatchReviewsRequest
+	(*ReviewEvent)(nil),           // 11: reviews.v1.ReviewEvent
+	nil,                           // 12: reviews.v1.ReviewStats.RatingCountsEntry
+	nil,                           // 13: reviews.v1.ReviewStats.SentimentCountsEntry
+	nil,                           // 14: reviews.v1.ReviewStats.TagCountsEntry
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_reviewsv1_reviews_proto_depIdxs = []int32{
+	15, // 0: reviews.v1.Review.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 1: reviews.v1.Review.flags:type_name -> reviews.v1.ReviewFlag
+	3,  // 2: reviews.v1.ListReviewsRequest.filter:type_name -> reviews.v1.ReviewFilter
+	0,  // 3: reviews.v1.ListReviewsRequest.sort:type_name -> reviews.v1.ReviewSort
+	1,  // 4: reviews.v1.ListReviewsResponse.reviews:type_name -> reviews.v1.Review
+	12, // 5: reviews.v1.ReviewStats.rating_counts:type_name -> reviews.v1.ReviewStats.RatingCountsEntry
+	15, // 6: reviews.v1.ReviewStats.oldest:type_name -> google.protobuf.Timestamp
+	15, // 7: reviews.v1.ReviewStats.newest:type_name -> google.protobuf.Timestamp
+	13, // 8: reviews.v1.ReviewStats.sentiment_counts:type_name -> reviews.v1.ReviewStats.SentimentCountsEntry
+	9,  // 9: reviews.v1.ReviewStats.sentiment_trend:type_name -> reviews.v1.SentimentPoint
+	14, // 10: reviews.v1.ReviewStats.tag_counts:type_name -> reviews.v1.ReviewStats.TagCountsEntry
+	1,  // 11: reviews.v1.ReviewEvent.review:type_name -> reviews.v1.Review
+	15, // 12: reviews.v1.ReviewEvent.published_at:type_name -> google.protobuf.Timestamp
+	4,  // 13: reviews.v1.ReviewsService.ListReviews:input_type -> reviews.v1.ListReviewsRequest
+	6,  // 14: reviews.v1.ReviewsService.GetReview:input_type -> reviews.v1.GetReviewRequest
+	7,  // 15: reviews.v1.ReviewsService.GetStats:input_type -> reviews.v1.GetStatsRequest
+	10, // 16: reviews.v1.ReviewsService.WatchReviews:input_type -> reviews.v1.WatchReviewsRequest
+	5,  // 17: reviews.v1.ReviewsService.ListReviews:output_type -> reviews.v1.ListReviewsResponse
+	1,  // 18: reviews.v1.ReviewsService.GetReview:output_type -> reviews.v1.Review
+	8,  // 19: reviews.v1.ReviewsService.GetStats:output_type -> reviews.v1.ReviewStats
+	11, // 20: reviews.v1.ReviewsService.WatchReviews:output_type -> reviews.v1.ReviewEvent
+	17, // [17:21] is the sub-list for method output_type
+	13, // [13:17] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_reviewsv1_reviews_proto_init() }
+func file_reviewsv1_reviews_proto_init() {
+	if File_reviewsv1_reviews_proto != nil {
+		return
+	}
+	file_reviewsv1_reviews_proto_msgTypes[2].OneofWrappers = []any{}
+	file_reviewsv1_reviews_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewsv1_reviews_proto_rawDesc), len(file_reviewsv1_reviews_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewsv1_reviews_proto_goTypes,
+		DependencyIndexes: file_reviewsv1_reviews_proto_depIdxs,
+		EnumInfos:         file_reviewsv1_reviews_proto_enumTypes,
+		MessageInfos:      file_reviewsv1_reviews_proto_msgTypes,
+	}.Build()
+	File_reviewsv1_reviews_proto = out.File
+	file_reviewsv1_reviews_proto_goTypes = nil
+	file_reviewsv1_reviews_proto_depIdxs = nil
+}
@@ -0,0 +1,328 @@
+// Package rpc serves the reviews over gRPC, for internal services that would rather not consume JSON.
+// The ReviewsService is defined in reviewsv1/reviews.proto; its Go code is generated with protoc-gen-go
+// and protoc-gen-go-grpc by go generate.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative reviewsv1/reviews.proto
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/events"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/language"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/rpc/reviewsv1"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sentiment"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultHours    = 48
+	maxHours        = 96
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// AppService is what the ReviewsService reads from, the poller's app.AppServiceInterface
+// with the queries, stats, events and API keys of the app service
+type AppService interface {
+	app.AppServiceInterface
+	IsTracked(appID string) bool
+	QueryReviews(appID string, hours int, filter repositories.ReviewFilter, sortBy string) []models.AppStoreReview
+	GetReview(appID, reviewID string) *models.AppStoreReview
+	Stats(appID string, includeFlagged bool) (repositories.ReviewStats, bool)
+	Events() *events.Hub
+	AuthEnabled() bool
+	APIKeys() *repositories.APIKeysRepository
+}
+
+// Server implements reviewsv1.ReviewsServiceServer on the app service
+type Server struct {
+	reviewsv1.UnimplementedReviewsServiceServer
+	appService AppService
+}
+
+// NewServer returns a gRPC server of the ReviewsService, with server reflection for tools like grpcurl.
+// Calls need a read-only API key in the x-api-key or authorization (Bearer) metadata, unless auth is disabled.
+func NewServer(appService AppService) *grpc.Server {
+	auth := authenticator{appService: appService}
+	server := grpc.NewServer(grpc.UnaryInterceptor(auth.unary), grpc.StreamInterceptor(auth.stream))
+	reviewsv1.RegisterReviewsServiceServer(server, &Server{appService: appService})
+	reflection.Register(server)
+	return server
+}
+
+// ListReviews returns a page of the reviews of the last hours matching a filter, like GET /v1/reviews
+func (s *Server) ListReviews(ctx context.Context, req *reviewsv1.ListReviewsRequest) (*reviewsv1.ListReviewsResponse, error) {
+	appID, err := s.appID(req.GetAppId())
+	if err != nil {
+		return nil, err
+	}
+	hours := int(req.GetHours())
+	if hours == 0 {
+		hours = defaultHours
+	}
+	if hours < 1 || hours > maxHours {
+		return nil, invalidArgument("hours", "hours must be between 1 and 96")
+	}
+	filter, err := reviewFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	sortBy, ok := reviewSorts[req.GetSort()]
+	if !ok {
+		return nil, invalidArgument("sort", "Unknown sort "+req.GetSort().String())
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 1 || pageSize > maxPageSize {
+		return nil, invalidArgument("page_size", "page_size must be between 1 and 100")
+	}
+
+	reviews := s.appService.QueryReviews(appID, hours, filter, sortBy)
+	start := 0
+	if req.GetPageToken() != "" {
+		id, ok := decodePageToken(req.GetPageToken())
+		index := slices.IndexFunc(reviews, func(r models.AppStoreReview) bool { return r.ID == id })
+		if !ok || index < 0 {
+			return nil, invalidArgument("page_token", "page_token is not the token of a page of this listing, its review may have left the hours window")
+		}
+		start = index + 1
+	}
+	end := min(start+pageSize, len(reviews))
+
+	response := &reviewsv1.ListReviewsResponse{TotalSize: int32(len(reviews))}
+	for _, review := range reviews[start:end] {
+		response.Reviews = append(response.Reviews, toReview(appID, review))
+	}
+	if end < len(reviews) {
+		response.NextPageToken = encodePageToken(reviews[end-1].ID)
+	}
+	return response, nil
+}
+
+// GetReview returns a stored review
+func (s *Server) GetReview(ctx context.Context, req *reviewsv1.GetReviewRequest) (*reviewsv1.Review, error) {
+	appID, err := s.appID(req.GetAppId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, invalidArgument("id", "id is required")
+	}
+	review := s.appService.GetReview(appID, req.GetId())
+	if review == nil {
+		return nil, status.Errorf(codes.NotFound, "Review %s not found", req.GetId())
+	}
+	return toReview(appID, *review), nil
+}
+
+// GetStats returns the statistics of the stored reviews of an app
+func (s *Server) GetStats(ctx context.Context, req *reviewsv1.GetStatsRequest) (*reviewsv1.ReviewStats, error) {
+	appID, err := s.appID(req.GetAppId())
+	if err != nil {
+		return nil, err
+	}
+	stats, _ := s.appService.Stats(appID, req.GetIncludeFlagged())
+	return toStats(stats), nil
+}
+
+// WatchReviews streams the newly stored reviews, after the missed ones still in history when resuming,
+// or a reset event when some are no longer there.
+// The stream ends with ABORTED when the client falls too far behind, it then resumes from its last event.
+func (s *Server) WatchReviews(req *reviewsv1.WatchReviewsRequest, stream grpc.ServerStreamingServer[reviewsv1.ReviewEvent]) error {
+	filter := events.Filter{AppID: req.GetAppId()}
+	if filter.AppID != "" && !s.appService.IsTracked(filter.AppID) {
+		return invalidArgument("app_id", "App is not tracked")
+	}
+	if req.Rating != nil {
+		rating := int(req.GetRating())
+		if rating < 1 || rating > 5 {
+			return invalidArgument("rating", "rating must be between 1 and 5")
+		}
+		filter.Rating = &rating
+	}
+
+	hub := s.appService.Events()
+	sub, missed, resetID := hub.Subscribe(filter, req.GetLastEventId())
+	defer hub.Unsubscribe(sub)
+
+	// headers tell clients the stream is subscribed, reviews stored from then on are sent
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	if resetID != 0 {
+		if err := stream.Send(&reviewsv1.ReviewEvent{Id: resetID, HistoryReset: true}); err != nil {
+			return err
+		}
+	}
+	for _, event := range missed {
+		if err := stream.Send(toEvent(event)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case event, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Aborted, "Client fell too far behind, resume from the last event received")
+			}
+			if err := stream.Send(toEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// appID returns the requested app, the primary one when empty
+func (s *Server) appID(appID string) (string, error) {
+	if appID == "" {
+		return s.appService.GetAppID(), nil
+	}
+	if !s.appService.IsTracked(appID) {
+		return "", invalidArgument("app_id", "App is not tracked")
+	}
+	return appID, nil
+}
+
+var reviewSorts = map[reviewsv1.ReviewSort]string{
+	reviewsv1.ReviewSort_REVIEW_SORT_UNSPECIFIED:    models.SortNewest,
+	reviewsv1.ReviewSort_REVIEW_SORT_NEWEST:         models.SortNewest,
+	reviewsv1.ReviewSort_REVIEW_SORT_OLDEST:         models.SortOldest,
+	reviewsv1.ReviewSort_REVIEW_SORT_SENTIMENT_ASC:  models.SortSentimentAsc,
+	reviewsv1.ReviewSort_REVIEW_SORT_SENTIMENT_DESC: models.SortSentimentDesc,
+}
+
+// reviewFilter validates a filter as GET /v1/reviews validates its parameters
+func reviewFilter(f *reviewsv1.ReviewFilter) (repositories.ReviewFilter, error) {
+	filter := repositories.ReviewFilter{
+		Sentiment: f.GetSentiment(),
+		Tag:       strings.TrimSpace(f.GetTag()),
+		Query:     strings.TrimSpace(f.GetQuery()),
+		Language:  strings.ToLower(f.GetLanguage()),
+		Status:    f.GetStatus(),
+		Assignee:  strings.TrimSpace(f.GetAssignee()),
+	}
+	if f != nil && f.Rating != nil {
+		rating := int(f.GetRating())
+		if rating < 1 || rating > 5 {
+			return filter, invalidArgument("filter.rating", "rating must be between 1 and 5")
+		}
+		filter.Rating = &rating
+	}
+	if filter.Sentiment != "" && !sentiment.IsLabel(filter.Sentiment) {
+		return filter, invalidArgument("filter.sentiment", "sentiment must be positive, neutral or negative")
+	}
+	if filter.Language != "" && !language.IsCode(filter.Language) {
+		return filter, invalidArgument("filter.language", "language must be one of "+strings.Join(language.Supported, ", ")+" or "+language.Undetermined)
+	}
+	if filter.Status != "" && !slices.Contains(models.TriageStatuses, filter.Status) {
+		return filter, invalidArgument("filter.status", "status must be one of "+strings.Join(models.TriageStatuses, ", "))
+	}
+	return filter, nil
+}
+
+// invalidArgument returns an INVALID_ARGUMENT status with the field as a BadRequest detail
+func invalidArgument(field, description string) error {
+	st := status.New(codes.InvalidArgument, description)
+	detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// Page tokens are opaque to clients, they hold the ID of the last review of a page
+func encodePageToken(reviewID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("review:" + reviewID))
+}
+
+func decodePageToken(token string) (string, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", false
+	}
+	return strings.CutPrefix(string(decoded), "review:")
+}
+
+func toReview(appID string, review models.AppStoreReview) *reviewsv1.Review {
+	flags := make([]*reviewsv1.ReviewFlag, len(review.Flags))
+	for i, flag := range review.Flags {
+		flags[i] = &reviewsv1.ReviewFlag{Reason: flag.Reason, Detail: flag.Detail}
+	}
+	return &reviewsv1.Review{
+		Id:             review.ID,
+		AppId:          appID,
+		Title:          review.Title,
+		Content:        review.Content,
+		Author:         review.Author,
+		Rating:         int32(review.Rating),
+		UpdatedAt:      timestamppb.New(review.UpdatedAt),
+		Sentiment:      review.Sentiment,
+		SentimentLabel: review.SentimentLabel,
+		Language:       review.Language,
+		Tags:           review.Tags,
+		Flags:          flags,
+	}
+}
+
+func toStats(stats repositories.ReviewStats) *reviewsv1.ReviewStats {
+	response := &reviewsv1.ReviewStats{
+		Count:            int32(stats.Count),
+		FlaggedCount:     int32(stats.FlaggedCount),
+		AverageRating:    stats.AverageRating,
+		RatingCounts:     map[int32]int32{},
+		AverageSentiment: stats.AverageSentiment,
+		SentimentCounts:  map[string]int32{},
+		TagCounts:        map[string]int32{},
+	}
+	for rating, count := range stats.RatingCounts {
+		response.RatingCounts[int32(rating)] = int32(count)
+	}
+	for label, count := range stats.SentimentCounts {
+		response.SentimentCounts[label] = int32(count)
+	}
+	for tag, count := range stats.TagCounts {
+		response.TagCounts[tag] = int32(count)
+	}
+	for _, point := range stats.SentimentTrend {
+		response.SentimentTrend = append(response.SentimentTrend, &reviewsv1.SentimentPoint{
+			Date:             point.Date,
+			Count:            int32(point.Count),
+			AverageSentiment: point.AverageSentiment,
+		})
+	}
+	if stats.Oldest != nil {
+		response.Oldest = timestamppb.New(*stats.Oldest)
+	}
+	if stats.Newest != nil {
+		response.Newest = timestamppb.New(*stats.Newest)
+	}
+	return response
+}
+
+func toEvent(event events.ReviewEvent) *reviewsv1.ReviewEvent {
+	return &reviewsv1.ReviewEvent{
+		Id:          event.ID,
+		AppId:       event.AppID,
+		Review:      toReview(event.AppID, event.Review),
+		PublishedAt: timestamppb.New(event.PublishedAt),
+	}
+}
@@ -0,0 +1,251 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/config"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/app"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/rpc/reviewsv1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+
+	code := m.Run()
+
+	log.SetOutput(os.Stderr)
+	os.Exit(code)
+}
+
+// startTestServer serves the ReviewsService over an in-memory connection and returns a client of it
+func startTestServer(t *testing.T, cfg *config.Config) (reviewsv1.ReviewsServiceClient, *app.App) {
+	now := time.Now().UTC()
+	reviews := models.AppStoreReviews{
+		{ID: "review-1", Title: "Love it", Content: "Amazing and fast", Author: "ana", Rating: 5, UpdatedAt: now.Add(-1 * time.Hour)},
+		{ID: "review-2", Title: "Buggy", Content: "Crashes every time, terrible", Author: "bo", Rating: 1, UpdatedAt: now.Add(-2 * time.Hour)},
+		{ID: "review-3", Title: "Ok", Content: "It opens", Author: "cy", Rating: 3, UpdatedAt: now.Add(-3 * time.Hour)},
+	}
+	appService := app.New(&repositories.AppReviewsRepository{Reviews: reviews}, cfg)
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(appService)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return reviewsv1.NewReviewsServiceClient(conn), appService
+}
+
+// TestListReviews_Pagination verifies that page tokens walk through the filtered reviews
+func TestListReviews_Pagination(t *testing.T) {
+	client, _ := startTestServer(t, &config.Config{AppID: "test-app-id"})
+
+	var ids []string
+	request := &reviewsv1.ListReviewsRequest{PageSize: 2, Sort: reviewsv1.ReviewSort_REVIEW_SORT_OLDEST}
+	for page := 0; page < 3; page++ {
+		response, err := client.ListReviews(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if response.GetTotalSize() != 3 {
+			t.Errorf("Expected a total size of 3, got %d", response.GetTotalSize())
+		}
+		for _, review := range response.GetReviews() {
+			ids = append(ids, review.GetId())
+		}
+		if response.GetNextPageToken() == "" {
+			break
+		}
+		request.PageToken = response.GetNextPageToken()
+	}
+
+	if len(ids) != 3 || ids[0] != "review-3" || ids[1] != "review-2" || ids[2] != "review-1" {
+		t.Errorf("Expected every review once, oldest first, got %v", ids)
+	}
+}
+
+// TestListReviews_Filter verifies the filters and that invalid ones are INVALID_ARGUMENT errors naming the field
+func TestListReviews_Filter(t *testing.T) {
+	client, _ := startTestServer(t, &config.Config{AppID: "test-app-id"})
+
+	response, err := client.ListReviews(context.Background(), &reviewsv1.ListReviewsRequest{
+		Filter: &reviewsv1.ReviewFilter{Rating: proto.Int32(1), Sentiment: "negative"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.GetReviews()) != 1 || response.GetReviews()[0].GetId() != "review-2" || response.GetReviews()[0].GetAppId() != "test-app-id" {
+		t.Errorf("Expected review-2 only, got %v", response.GetReviews())
+	}
+
+	tests := []struct {
+		request *reviewsv1.ListReviewsRequest
+		field   string
+	}{
+		{&reviewsv1.ListReviewsRequest{Filter: &reviewsv1.ReviewFilter{Rating: proto.Int32(0)}}, "filter.rating"},
+		{&reviewsv1.ListReviewsRequest{Filter: &reviewsv1.ReviewFilter{Status: "done"}}, "filter.status"},
+		{&reviewsv1.ListReviewsRequest{Hours: 200}, "hours"},
+		{&reviewsv1.ListReviewsRequest{AppId: "other-app"}, "app_id"},
+		{&reviewsv1.ListReviewsRequest{PageSize: 101}, "page_size"},
+		{&reviewsv1.ListReviewsRequest{PageToken: "bm9wZQ"}, "page_token"},
+	}
+	for _, tt := range tests {
+		_, err := client.ListReviews(context.Background(), tt.request)
+		st := status.Convert(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("Expected INVALID_ARGUMENT for %v, got %v", tt.request, err)
+			continue
+		}
+		details := st.Details()
+		badRequest, ok := details[0].(*errdetails.BadRequest)
+		if len(details) != 1 || !ok || badRequest.GetFieldViolations()[0].GetField() != tt.field {
+			t.Errorf("Expected a field violation of %s, got %v", tt.field, details)
+		}
+	}
+}
+
+// TestGetReview verifies that stored reviews are returned and missing ones are NOT_FOUND
+func TestGetReview(t *testing.T) {
+	client, _ := startTestServer(t, &config.Config{AppID: "test-app-id"})
+
+	review, err := client.GetReview(context.Background(), &reviewsv1.GetReviewRequest{Id: "review-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if review.GetTitle() != "Love it" || review.GetRating() != 5 || review.GetSentimentLabel() != "positive" || review.GetUpdatedAt().AsTime().IsZero() {
+		t.Errorf("Unexpected review: %v", review)
+	}
+
+	_, err = client.GetReview(context.Background(), &reviewsv1.GetReviewRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NOT_FOUND, got %v", err)
+	}
+}
+
+// TestGetStats verifies the statistics of the stored reviews
+func TestGetStats(t *testing.T) {
+	client, _ := startTestServer(t, &config.Config{AppID: "test-app-id"})
+
+	stats, err := client.GetStats(context.Background(), &reviewsv1.GetStatsRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.GetCount() != 3 || stats.GetAverageRating() != 3 || stats.GetRatingCounts()[1] != 1 || stats.GetSentimentCounts()["negative"] != 1 {
+		t.Errorf("Unexpected stats: %v", stats)
+	}
+	if stats.GetNewest().AsTime().Before(stats.GetOldest().AsTime()) {
+		t.Errorf("Expected the newest review after the oldest, got %v and %v", stats.GetNewest(), stats.GetOldest())
+	}
+}
+
+// TestWatchReviews verifies that new reviews matching the rating are streamed, and that streams resume from an event
+func TestWatchReviews(t *testing.T) {
+	client, appService := startTestServer(t, &config.Config{AppID: "test-app-id"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchReviews(ctx, &reviewsv1.WatchReviewsRequest{Rating: proto.Int32(1)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// headers are sent once the server is subscribed
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("Expected the stream to open, got %v", err)
+	}
+
+	now := time.Now().UTC()
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		{ID: "review-4", Title: "Great", Content: "Love it", Rating: 5, UpdatedAt: now},
+		{ID: "review-5", Title: "Broken", Content: "Crashes", Rating: 1, UpdatedAt: now},
+	})
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected an event, got %v", err)
+	}
+	if event.GetReview().GetId() != "review-5" || event.GetAppId() != "test-app-id" || event.GetId() == 0 {
+		t.Errorf("Expected the event of review-5, got %v", event)
+	}
+
+	appService.AddReviews("test-app-id", []models.AppStoreReview{
+		{ID: "review-6", Title: "Meh", Content: "Slow", Rating: 2, UpdatedAt: now},
+	})
+	resumed, err := client.WatchReviews(ctx, &reviewsv1.WatchReviewsRequest{Rating: proto.Int32(2), LastEventId: event.GetId()})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	missed, err := resumed.Recv()
+	if err != nil || missed.GetReview().GetId() != "review-6" || missed.GetId() <= event.GetId() {
+		t.Errorf("Expected the missed event of review-6 first, got %v %v", missed, err)
+	}
+	// an event of a previous run is unknown, a reset moves the client to the last event
+	reset, err := client.WatchReviews(ctx, &reviewsv1.WatchReviewsRequest{LastEventId: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resetEvent, err := reset.Recv()
+	if err != nil || !resetEvent.GetHistoryReset() || resetEvent.GetId() != missed.GetId() || resetEvent.GetReview() != nil {
+		t.Errorf("Expected a reset to event %d, got %v %v", missed.GetId(), resetEvent, err)
+	}
+}
+
+// TestAuthentication verifies that calls need a valid API key when auth is enabled
+func TestAuthentication(t *testing.T) {
+	cfg := &config.Config{AppID: "test-app-id", AuthEnabled: true, StorageFilePath: filepath.Join(t.TempDir(), "reviews.json")}
+	client, appService := startTestServer(t, cfg)
+	_, secret, err := appService.APIKeys().Create("backend", models.RoleReadOnly)
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+
+	tests := []struct {
+		metadata []string
+		code     codes.Code
+	}{
+		{nil, codes.Unauthenticated},
+		{[]string{"x-api-key", "ark_unknown"}, codes.Unauthenticated},
+		{[]string{"x-api-key", secret}, codes.OK},
+		{[]string{"authorization", "Bearer " + secret}, codes.OK},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(tt.metadata...))
+		_, err := client.GetStats(ctx, &reviewsv1.GetStatsRequest{})
+		if status.Code(err) != tt.code {
+			t.Errorf("Expected %s with metadata %v, got %v", tt.code, tt.metadata, err)
+		}
+	}
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-api-key", "ark_unknown"))
+	stream, err := client.WatchReviews(ctx, &reviewsv1.WatchReviewsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected streams to be authenticated too, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+// Package sentiment scores review text with an embedded word lexicon, offline.
+// Scoring follows the VADER approach: word valences are summed, negated and intensified
+// by the words before them, and the clause after "but" weighs more than the one before.
+package sentiment
+
+import (
+	_ "embed"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/language"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// Sentiment labels
+const (
+	Positive = "positive"
+	Neutral  = "neutral"
+	Negative = "negative"
+)
+
+const (
+	// labelThreshold is the minimum absolute score of a positive or negative review
+	labelThreshold = 0.05
+	// negationWindow is the number of words after a negation that it flips
+	negationWindow = 3
+	// negationFactor flips and dampens a negated valence, "not good" is less negative than "bad"
+	negationFactor = -0.74
+	// intensifierBoost is added to the magnitude of the word after an intensifier
+	intensifierBoost = 0.3
+	// normalizationAlpha maps the sum of valences to (-1, 1), approaching the bounds around a sum of 15
+	normalizationAlpha = 15
+)
+
+//go:embed lexicon.txt
+var lexiconFile string
+
+var lexicon = parseLexicon(lexiconFile)
+
+var negations = setOf(
+	"not", "no", "never", "none", "nothing", "nobody", "neither", "nor", "cannot", "without",
+	"don't", "dont", "doesn't", "doesnt", "didn't", "didnt", "isn't", "isnt", "wasn't", "wasnt",
+	"aren't", "arent", "weren't", "won't", "wont", "can't", "cant", "couldn't", "couldnt",
+	"shouldn't", "wouldn't", "haven't", "hasn't", "hadn't", "ain't",
+)
+
+var intensifiers = setOf(
+	"very", "really", "so", "extremely", "super", "totally", "absolutely", "completely", "incredibly", "too", "most",
+)
+
+func setOf(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+func parseLexicon(data string) map[string]float64 {
+	words := make(map[string]float64)
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, valenceStr, found := strings.Cut(line, "\t")
+		valence, err := strconv.ParseFloat(strings.TrimSpace(valenceStr), 64)
+		if !found || err != nil {
+			panic("sentiment: invalid lexicon line " + strconv.Quote(line))
+		}
+		words[word] = valence
+	}
+	return words
+}
+
+// Score returns the sentiment of a text, from -1 (most negative) to 1 (most positive)
+func Score(text string) float64 {
+	tokens := Tokenize(text)
+
+	sum := 0.0
+	for i, token := range tokens {
+		valence, ok := lexicon[token]
+		if !ok {
+			continue
+		}
+
+		// intensifiers and negations only apply within the same sentence, marked by "" tokens
+		if i > 0 && intensifiers[tokens[i-1]] && lexicon[tokens[i-1]] == 0 {
+			valence += math.Copysign(intensifierBoost*math.Abs(valence), valence)
+		}
+		for j := i - 1; j >= 0 && j >= i-negationWindow && tokens[j] != ""; j-- {
+			if negations[tokens[j]] {
+				valence *= negationFactor
+				break
+			}
+		}
+
+		sum += valence * butWeight(tokens, i)
+	}
+
+	return sum / math.Sqrt(sum*sum+normalizationAlpha)
+}
+
+// butWeight lowers words before a "but" of their sentence and raises the words after it
+func butWeight(tokens []string, i int) float64 {
+	for j := i - 1; j >= 0 && tokens[j] != ""; j-- {
+		if tokens[j] == "but" {
+			return 1.5
+		}
+	}
+	for j := i + 1; j < len(tokens) && tokens[j] != ""; j++ {
+		if tokens[j] == "but" {
+			return 0.5
+		}
+	}
+	return 1
+}
+
+// Tokenize lowercases English text and splits it into words, keeping apostrophes inside words.
+// Sentence ends are kept as empty tokens so negations don't cross them.
+func Tokenize(text string) []string {
+	return language.Tokenize(text, language.English)
+}
+
+// Label returns the label of a score
+func Label(score float64) string {
+	switch {
+	case score >= labelThreshold:
+		return Positive
+	case score <= -labelThreshold:
+		return Negative
+	default:
+		return Neutral
+	}
+}
+
+// IsLabel reports whether label is a valid sentiment label
+func IsLabel(label string) bool {
+	return label == Positive || label == Neutral || label == Negative
+}
+
+// Annotate scores the title and content of review
+func Annotate(review *models.AppStoreReview) {
+	// the title ends a sentence of its own, negations in it don't reach the content
+	review.Sentiment = math.Round(Score(review.Title+".\n"+review.Content)*1000) / 1000
+	review.SentimentLabel = Label(review.Sentiment)
+}
+
+// AnnotateMissing scores review if it was stored before sentiment analysis, reporting whether it changed
+func AnnotateMissing(review *models.AppStoreReview) bool {
+	if review.SentimentLabel != "" {
+		return false
+	}
+	Annotate(review)
+	return true
+}
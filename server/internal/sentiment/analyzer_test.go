@@ -0,0 +1,86 @@
+package sentiment
+
+import (
+	"testing"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// TestScore_Polarity verifies the sign of clearly positive, negative and neutral texts
+func TestScore_Polarity(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"I love this app, it is amazing and fast", Positive},
+		{"Terrible update, it crashes all the time", Negative},
+		{"I opened it on my phone", Neutral},
+		{"", Neutral},
+		{"Great features but it keeps crashing and freezing", Negative},
+	}
+
+	for _, test := range tests {
+		if label := Label(Score(test.text)); label != test.expected {
+			t.Errorf("Expected %q to be %s, got %s (%v)", test.text, test.expected, label, Score(test.text))
+		}
+	}
+}
+
+// TestScore_Negation verifies that negations flip the following words within their sentence
+func TestScore_Negation(t *testing.T) {
+	if score := Score("This app is not good"); score >= 0 {
+		t.Errorf("Expected negated positive word to be negative, got %v", score)
+	}
+	if score := Score("It doesn't crash anymore"); score <= 0 {
+		t.Errorf("Expected negated negative word to be positive, got %v", score)
+	}
+	if Score("not very good") >= 0 {
+		t.Error("Expected negation to reach past an intensifier")
+	}
+	if score := Score("Not bad. Great app"); score <= Score("Great app") {
+		t.Errorf("Expected negation not to cross the sentence end, got %v", score)
+	}
+}
+
+// TestScore_Intensifiers verifies that intensifiers raise the magnitude of the next word
+func TestScore_Intensifiers(t *testing.T) {
+	if Score("very good") <= Score("good") {
+		t.Error("Expected very good to score higher than good")
+	}
+	if Score("really bad") >= Score("bad") {
+		t.Error("Expected really bad to score lower than bad")
+	}
+}
+
+// TestAnnotate_ScoresTitleAndContent verifies that a 4-star review complaining about bugs is negative
+func TestAnnotate_ScoresTitleAndContent(t *testing.T) {
+	review := models.AppStoreReview{
+		Title:   "Buggy since the update",
+		Content: "Used to be ok but now it crashes every time I open it",
+		Rating:  4,
+	}
+
+	Annotate(&review)
+
+	if review.SentimentLabel != Negative || review.Sentiment >= 0 {
+		t.Errorf("Expected negative sentiment, got %s (%v)", review.SentimentLabel, review.Sentiment)
+	}
+	if AnnotateMissing(&review) {
+		t.Error("Expected an annotated review not to be scored again")
+	}
+}
+
+// TestTokenize verifies lowercasing, apostrophes and sentence markers
+func TestTokenize(t *testing.T) {
+	tokens := Tokenize("Don’t BUY it! 'Worst' app")
+	expected := []string{"don't", "buy", "it", "", "worst", "app"}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+	for i := range expected {
+		if tokens[i] != expected[i] {
+			t.Errorf("Expected token %d to be %q, got %q", i, expected[i], tokens[i])
+		}
+	}
+}
@@ -1,4 +1,4 @@
-package appstore_reviews_poller
+package sources
 
 import (
 	"context"
@@ -12,19 +12,21 @@ import (
 	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
 )
 
-type FetcherInterface interface {
-	fetchReviews(ctx context.Context, appID string, latestReviewId *string) ([]models.AppStoreReview, error)
-}
+// AppStoreRSSBaseURL is the customer reviews feed of the US App Store, other storefronts replace the us
+const AppStoreRSSBaseURL = "https://itunes.apple.com/us/rss/customerreviews"
+
+// appStoreRSSMaxPages is the number of pages Apple serves, of 50 reviews each
+const appStoreRSSMaxPages = 10
 
-// Fetcher handles fetching reviews from the App Store RSS feed
-type Fetcher struct {
+// AppStoreRSS fetches reviews from the App Store RSS feed
+type AppStoreRSS struct {
 	client  *http.Client
 	baseURL string
 }
 
-// NewFetcher creates a new Fetcher instance
-func NewFetcher(baseURL string) *Fetcher {
-	return &Fetcher{
+// NewAppStoreRSS creates a source reading the feed at baseURL
+func NewAppStoreRSS(baseURL string) *AppStoreRSS {
+	return &AppStoreRSS{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -32,14 +34,46 @@ func NewFetcher(baseURL string) *Fetcher {
 	}
 }
 
+func (f *AppStoreRSS) Metadata() Metadata {
+	return Metadata{Kind: KindAppStoreRSS, Description: "App Store customer reviews RSS feed", Location: f.baseURL}
+}
+
+func (f *AppStoreRSS) Capabilities() Capabilities {
+	return Capabilities{Incremental: true, MaxReviewsPerFetch: appStoreRSSMaxPages * 50, Ratings: true}
+}
+
+// Fetch pages through the feed, newest first, until the review of the token, or the newest stored review
+// when there is no token yet. The token is the ID of the newest review of the feed.
+func (f *AppStoreRSS) Fetch(ctx context.Context, appID string, cursor Cursor) (FetchResult, error) {
+	var latestReviewId *string
+	if cursor.Token != "" {
+		latestReviewId = &cursor.Token
+	} else if cursor.LatestReviewID != "" {
+		latestReviewId = &cursor.LatestReviewID
+	}
+
+	reviews, err := f.fetchReviews(ctx, appID, latestReviewId)
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	result := FetchResult{Reviews: reviews, Token: cursor.Token}
+	if len(reviews) > 0 {
+		result.Token = reviews[0].ID
+	} else if latestReviewId != nil {
+		result.Token = *latestReviewId
+	}
+	return result, nil
+}
+
 // fetchReviews fetches all reviews with pagination support
-func (f *Fetcher) fetchReviews(ctx context.Context, appID string, latestReviewId *string) ([]models.AppStoreReview, error) {
+func (f *AppStoreRSS) fetchReviews(ctx context.Context, appID string, latestReviewId *string) ([]models.AppStoreReview, error) {
 	var allReviews []models.AppStoreReview
 
 	// Start with page 1 and continue
 	page := 1
 	foundLatestReview := false
-	const maxPages = 10 // Safety limit to prevent infinite loops and it is also the maximum number of pages Apple allows
+	const maxPages = appStoreRSSMaxPages // Safety limit to prevent infinite loops and it is also the maximum number of pages Apple allows
 
 	for page <= maxPages {
 		time.Sleep(200 * time.Millisecond) // sleep to avoid potential rate limiting
@@ -74,7 +108,7 @@ func (f *Fetcher) fetchReviews(ctx context.Context, appID string, latestReviewId
 }
 
 // fetchMostRecentReviewsPage fetches a specific page of reviews
-func (f *Fetcher) fetchMostRecentReviewsPage(ctx context.Context, appID string, page int) ([]models.AppStoreReview, error) {
+func (f *AppStoreRSS) fetchMostRecentReviewsPage(ctx context.Context, appID string, page int) ([]models.AppStoreReview, error) {
 	log.Printf(" > FETCH: fetching page: %d", page)
 
 	// Build URL with page parameter
@@ -1,4 +1,4 @@
-package appstore_reviews_poller
+package sources
 
 import (
 	"encoding/json"
@@ -1,4 +1,4 @@
-package appstore_reviews_poller
+package sources
 
 import (
 	"context"
@@ -49,7 +49,7 @@ func TestFetchMostRecentReviewsPage_Success(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	reviews, err := fetcher.fetchMostRecentReviewsPage(ctx, "123456789", 1)
@@ -81,7 +81,7 @@ func TestFetchMostRecentReviewsPage_HTTPError(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	reviews, err := fetcher.fetchMostRecentReviewsPage(ctx, "123456789", 1)
@@ -107,7 +107,7 @@ func TestFetchMostRecentReviewsPage_InvalidJSON(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	reviews, err := fetcher.fetchMostRecentReviewsPage(ctx, "123456789", 1)
@@ -134,7 +134,7 @@ func TestFetchMostRecentReviewsPage_Timeout(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
 
@@ -179,7 +179,7 @@ func TestFetchReviews_SinglePage(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	reviews, err := fetcher.fetchReviews(ctx, "123456789", nil)
@@ -246,7 +246,7 @@ func TestFetchReviews_MultiplePages(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	reviews, err := fetcher.fetchReviews(ctx, "123456789", nil)
@@ -307,7 +307,7 @@ func TestFetchReviews_WithLatestReviewId(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	latestReviewId := "review-latest"
@@ -335,7 +335,7 @@ func TestFetchReviews_EmptyResponse(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	reviews, err := fetcher.fetchReviews(ctx, "123456789", nil)
@@ -383,7 +383,7 @@ func TestFetchReviews_MaxPagesLimit(t *testing.T) {
 	}))
 	defer server.Close()
 
-	fetcher := NewFetcher(server.URL)
+	fetcher := NewAppStoreRSS(server.URL)
 	ctx := context.Background()
 
 	reviews, err := fetcher.fetchReviews(ctx, "123456789", nil)
@@ -396,3 +396,45 @@ func TestFetchReviews_MaxPagesLimit(t *testing.T) {
 		t.Errorf("Expected 10 reviews (maxPages limit), got %d", len(reviews))
 	}
 }
+
+// TestAppStoreRSS_Fetch verifies that fetches stop at the token, or at the latest stored review without one, and return the newest review ID as token
+func TestAppStoreRSS_Fetch(t *testing.T) {
+	mockResponse := `{
+		"feed": {
+			"entry": [
+				{"id": {"label": "review-new"}, "im:rating": {"label": "5"}, "updated": {"label": "2023-12-08T10:30:00-07:00"}},
+				{"id": {"label": "review-latest"}, "im:rating": {"label": "4"}, "updated": {"label": "2023-12-07T10:30:00-07:00"}},
+				{"id": {"label": "review-old"}, "im:rating": {"label": "3"}, "updated": {"label": "2023-12-06T10:30:00-07:00"}}
+			]
+		}
+	}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	source := NewAppStoreRSS(server.URL)
+
+	result, err := source.Fetch(context.Background(), "123456789", Cursor{LatestReviewID: "review-latest"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Reviews) != 1 || result.Reviews[0].ID != "review-new" {
+		t.Errorf("Expected the review newer than the latest stored one, got %v", result.Reviews)
+	}
+	if result.Token != "review-new" {
+		t.Errorf("Expected token 'review-new', got '%s'", result.Token)
+	}
+
+	result, err = source.Fetch(context.Background(), "123456789", Cursor{LatestReviewID: "review-old", Token: result.Token})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Reviews) != 0 {
+		t.Errorf("Expected no reviews newer than the token, got %v", result.Reviews)
+	}
+	if result.Token != "review-new" {
+		t.Errorf("Expected the token to be kept, got '%s'", result.Token)
+	}
+}
@@ -0,0 +1,157 @@
+package sources
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// CSVFolder imports the CSV files dropped in a folder per app, <folder>/<appId>/*.csv. Files start with a header
+// row naming the columns, in any order: id, title, content, author, rating and updatedAt, the ones of CSV
+// exports. Only id is required. Files are left in place, a file is imported again when it is modified.
+type CSVFolder struct {
+	folder string
+}
+
+// NewCSVFolder creates a source reading the app folders of folder, which must exist
+func NewCSVFolder(folder string) (*CSVFolder, error) {
+	if folder == "" {
+		return nil, errors.New("missing folder, expected csv-folder:/path/to/folder")
+	}
+	info, err := os.Stat(folder)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a folder", folder)
+	}
+	return &CSVFolder{folder: folder}, nil
+}
+
+func (f *CSVFolder) Metadata() Metadata {
+	return Metadata{Kind: KindCSVFolder, Description: "CSV files drop folder", Location: f.folder}
+}
+
+func (f *CSVFolder) Capabilities() Capabilities {
+	return Capabilities{Incremental: true, Ratings: true}
+}
+
+// Fetch reads the files of the app modified after the token, the modification time of the newest file read before.
+// Files that can't be parsed are logged and skipped until they are modified again.
+func (f *CSVFolder) Fetch(ctx context.Context, appID string, cursor Cursor) (FetchResult, error) {
+	var since time.Time
+	if cursor.Token != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339Nano, cursor.Token); err != nil {
+			return FetchResult{}, fmt.Errorf("invalid cursor token %q: %w", cursor.Token, err)
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(f.folder, filepath.Base(appID), "*.csv"))
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("listing files: %w", err)
+	}
+
+	result := FetchResult{Token: cursor.Token}
+	newest := since
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return FetchResult{}, err
+		}
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // removed since it was listed
+		}
+		if err != nil {
+			return FetchResult{}, fmt.Errorf("reading %s: %w", path, err)
+		}
+		if !info.ModTime().After(since) {
+			continue
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+
+		reviews, err := readCSVReviews(path)
+		if err != nil {
+			log.Printf("warning: skipping %s: %v", path, err)
+			continue
+		}
+		result.Reviews = append(result.Reviews, reviews...)
+	}
+
+	slices.SortStableFunc(result.Reviews, newestFirst)
+	if newest.After(since) {
+		result.Token = newest.UTC().Format(time.RFC3339Nano)
+	}
+	return result, nil
+}
+
+// readCSVReviews reads the reviews of a CSV file, failing on the first invalid row
+func readCSVReviews(path string) ([]models.AppStoreReview, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, errors.New("missing id column")
+	}
+	reader.FieldsPerRecord = len(header)
+
+	var reviews []models.AppStoreReview
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return reviews, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		value := func(column string) string {
+			if i, ok := columns[strings.ToLower(column)]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		review := models.AppStoreReview{ID: value("id"), Title: value("title"), Content: value("content"), Author: value("author")}
+		if review.ID == "" {
+			return nil, fmt.Errorf("line %d: missing id", line)
+		}
+		if rating := value("rating"); rating != "" {
+			if review.Rating, err = strconv.Atoi(rating); err != nil || review.Rating < 1 || review.Rating > 5 {
+				return nil, fmt.Errorf("line %d: invalid rating %q", line, rating)
+			}
+		}
+		if updatedAt := value("updatedAt"); updatedAt != "" {
+			if review.UpdatedAt, err = parseReviewTimeToUTC(updatedAt); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		reviews = append(reviews, review)
+	}
+}
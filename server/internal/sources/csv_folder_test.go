@@ -0,0 +1,83 @@
+package sources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCSV writes a CSV file of the app folder, modified at modTime
+func writeCSV(t *testing.T, folder, name, content string, modTime time.Time) {
+	t.Helper()
+	path := filepath.Join(folder, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set the modification time of %s: %v", path, err)
+	}
+}
+
+// TestNewCSVFolder_MissingFolder verifies that the folder must exist
+func TestNewCSVFolder_MissingFolder(t *testing.T) {
+	if _, err := NewCSVFolder(""); err == nil {
+		t.Error("Expected an error without folder")
+	}
+	if _, err := NewCSVFolder(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing folder")
+	}
+}
+
+// TestCSVFolder_Fetch verifies that the files of the app are read, invalid ones skipped, and that the next fetch
+// only reads the files modified since
+func TestCSVFolder_Fetch(t *testing.T) {
+	folder := t.TempDir()
+	appFolder := filepath.Join(folder, "123")
+	if err := os.Mkdir(appFolder, 0o755); err != nil {
+		t.Fatalf("Failed to create the app folder: %v", err)
+	}
+	modTime := time.Date(2023, 12, 8, 10, 0, 0, 0, time.UTC)
+	writeCSV(t, appFolder, "export.csv", "\ufeffRating,ID,Title,Content,Author,UpdatedAt\n"+
+		"3,review-old,Old,\"Slow, but works\",Old User,2023-12-06T10:30:00Z\n"+
+		"5,review-new,New,Great,New User,2023-12-08T10:30:00Z\n", modTime)
+	writeCSV(t, appFolder, "invalid.csv", "id,rating\nreview-bad,9\n", modTime)
+	writeCSV(t, appFolder, "notes.txt", "id\nreview-txt\n", modTime)
+
+	source, err := NewCSVFolder(folder)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := source.Fetch(context.Background(), "123", Cursor{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Reviews) != 2 || result.Reviews[0].ID != "review-new" || result.Reviews[1].ID != "review-old" {
+		t.Fatalf("Expected the reviews of export.csv, newest first, got %v", result.Reviews)
+	}
+	old := result.Reviews[1]
+	if old.Content != "Slow, but works" || old.Author != "Old User" || old.Rating != 3 || !old.UpdatedAt.Equal(time.Date(2023, 12, 6, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the columns to be read by name, got %+v", old)
+	}
+	if result.Token != modTime.Format(time.RFC3339Nano) {
+		t.Errorf("Expected the modification time of the files as token, got '%s'", result.Token)
+	}
+
+	writeCSV(t, appFolder, "later.csv", "id,rating\nreview-later,4\n", modTime.Add(time.Hour))
+	result, err = source.Fetch(context.Background(), "123", Cursor{Token: result.Token})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Reviews) != 1 || result.Reviews[0].ID != "review-later" {
+		t.Errorf("Expected only the review of the file modified since, got %v", result.Reviews)
+	}
+	if result.Token != modTime.Add(time.Hour).Format(time.RFC3339Nano) {
+		t.Errorf("Expected the modification time of later.csv as token, got '%s'", result.Token)
+	}
+
+	result, err = source.Fetch(context.Background(), "other-app", Cursor{})
+	if err != nil || len(result.Reviews) != 0 {
+		t.Errorf("Expected no reviews for an app without folder, got %v and %v", result.Reviews, err)
+	}
+}
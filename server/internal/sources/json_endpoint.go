@@ -0,0 +1,133 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// JSONEndpoint fetches reviews from an HTTP endpoint answering a JSON array of reviews, or an object with a
+// reviews array like GET /v1/reviews of this API. Reviews have the fields of the API: id, title, content,
+// author, rating and updatedAt.
+type JSONEndpoint struct {
+	client      *http.Client
+	urlTemplate string // {appId} is replaced by the app ID
+}
+
+// NewJSONEndpoint creates a source reading the http or https URL urlTemplate
+func NewJSONEndpoint(urlTemplate string) (*JSONEndpoint, error) {
+	parsedURL, err := url.Parse(strings.ReplaceAll(urlTemplate, "{appId}", "app"))
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: expected an http or https URL, {appId} standing for the app ID", urlTemplate)
+	}
+
+	return &JSONEndpoint{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		urlTemplate: urlTemplate,
+	}, nil
+}
+
+func (e *JSONEndpoint) Metadata() Metadata {
+	return Metadata{Kind: KindJSONEndpoint, Description: "JSON reviews endpoint", Location: e.urlTemplate}
+}
+
+func (e *JSONEndpoint) Capabilities() Capabilities {
+	return Capabilities{Incremental: true, Ratings: true}
+}
+
+// Fetch gets the reviews updated after the token, the update time of the newest review fetched before.
+// The token is sent as the since query parameter, so endpoints can leave older reviews out.
+func (e *JSONEndpoint) Fetch(ctx context.Context, appID string, cursor Cursor) (FetchResult, error) {
+	parsedURL, err := url.Parse(strings.ReplaceAll(e.urlTemplate, "{appId}", url.PathEscape(appID)))
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("parsing URL: %w", err)
+	}
+	var since time.Time
+	if cursor.Token != "" {
+		if since, err = time.Parse(time.RFC3339Nano, cursor.Token); err != nil {
+			return FetchResult{}, fmt.Errorf("invalid cursor token %q: %w", cursor.Token, err)
+		}
+		query := parsedURL.Query()
+		query.Set("since", cursor.Token)
+		parsedURL.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), nil)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return FetchResult{}, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	reviews, err := parseJSONReviews(body)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("parsing reviews: %w", err)
+	}
+
+	result := FetchResult{Token: cursor.Token}
+	for _, review := range reviews {
+		if review.ID == "" {
+			log.Printf("warning: skipping review without id from %s", e.urlTemplate)
+			continue
+		}
+		// endpoints ignoring since answer every review
+		if review.UpdatedAt.After(since) {
+			result.Reviews = append(result.Reviews, review)
+		}
+	}
+	slices.SortStableFunc(result.Reviews, newestFirst)
+	if len(result.Reviews) > 0 {
+		result.Token = result.Reviews[0].UpdatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return result, nil
+}
+
+// parseJSONReviews reads a JSON array of reviews or an object with a reviews array
+func parseJSONReviews(data []byte) ([]models.AppStoreReview, error) {
+	var reviews []models.AppStoreReview
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &reviews)
+		return reviews, err
+	}
+
+	var response struct {
+		Reviews *[]models.AppStoreReview `json:"reviews"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	if response.Reviews == nil {
+		return nil, fmt.Errorf("expected an array of reviews or an object with a reviews array")
+	}
+	return *response.Reviews, nil
+}
+
+// newestFirst orders reviews from the most recently updated
+func newestFirst(a, b models.AppStoreReview) int {
+	return b.UpdatedAt.Compare(a.UpdatedAt)
+}
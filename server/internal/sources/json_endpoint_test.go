@@ -0,0 +1,102 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNewJSONEndpoint_InvalidURL verifies that only http and https URLs are accepted
+func TestNewJSONEndpoint_InvalidURL(t *testing.T) {
+	for _, urlTemplate := range []string{"", "example.com/{appId}.json", "ftp://example.com/{appId}.json", "http://"} {
+		if _, err := NewJSONEndpoint(urlTemplate); err == nil {
+			t.Errorf("Expected an error for %q", urlTemplate)
+		}
+	}
+}
+
+// TestJSONEndpoint_Fetch verifies that arrays and objects with a reviews array are read, newest first,
+// and that the next fetch sends since and only returns the newer reviews
+func TestJSONEndpoint_Fetch(t *testing.T) {
+	responses := map[string]string{
+		"/array/123.json": `[
+			{"id": "review-old", "title": "Old", "rating": 3, "updatedAt": "2023-12-06T10:30:00Z"},
+			{"id": "", "title": "No id", "rating": 1, "updatedAt": "2023-12-09T10:30:00Z"},
+			{"id": "review-new", "title": "New", "rating": 5, "updatedAt": "2023-12-08T10:30:00Z"}
+		]`,
+		"/object/123.json": `{"reviews": [{"id": "review-1", "rating": 4, "updatedAt": "2023-12-07T10:30:00Z"}], "nextCursor": ""}`,
+	}
+	var since string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since = r.URL.Query().Get("since")
+		w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	source, err := NewJSONEndpoint(server.URL + "/array/{appId}.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := source.Fetch(context.Background(), "123", Cursor{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Reviews) != 2 || result.Reviews[0].ID != "review-new" || result.Reviews[1].ID != "review-old" {
+		t.Errorf("Expected the reviews with an id, newest first, got %v", result.Reviews)
+	}
+	if result.Token != "2023-12-08T10:30:00Z" {
+		t.Errorf("Expected the update time of the newest review as token, got '%s'", result.Token)
+	}
+	if since != "" {
+		t.Errorf("Expected no since parameter on the first fetch, got '%s'", since)
+	}
+
+	// the endpoint ignores since, the reviews fetched before are left out anyway
+	result, err = source.Fetch(context.Background(), "123", Cursor{Token: result.Token})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if since != "2023-12-08T10:30:00Z" {
+		t.Errorf("Expected the token as since parameter, got '%s'", since)
+	}
+	if len(result.Reviews) != 0 || result.Token != "2023-12-08T10:30:00Z" {
+		t.Errorf("Expected no reviews and the same token, got %v and '%s'", result.Reviews, result.Token)
+	}
+
+	source, _ = NewJSONEndpoint(server.URL + "/object/{appId}.json")
+	result, err = source.Fetch(context.Background(), "123", Cursor{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Reviews) != 1 || result.Reviews[0].ID != "review-1" || result.Reviews[0].Rating != 4 {
+		t.Errorf("Expected the review of the reviews array, got %v", result.Reviews)
+	}
+}
+
+// TestJSONEndpoint_FetchErrors verifies that error statuses and unexpected bodies fail the fetch
+func TestJSONEndpoint_FetchErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"error status", http.StatusInternalServerError, `[]`},
+		{"invalid JSON", http.StatusOK, `not json`},
+		{"object without reviews", http.StatusOK, `{"items": []}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			source, _ := NewJSONEndpoint(server.URL + "/{appId}.json")
+			if _, err := source.Fetch(context.Background(), "123", Cursor{}); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Package sources defines where the poller fetches reviews from. Every source implements ReviewSource
+// and is registered under a kind, which REVIEW_SOURCES entries like "json:https://example.com/{appId}.json"
+// refer to. The App Store RSS feed, generic JSON endpoints and CSV drop folders are built in.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+// ReviewSource fetches the reviews of the tracked apps from one place
+type ReviewSource interface {
+	// Metadata describes the source, for logs
+	Metadata() Metadata
+	// Capabilities describes what fetches return, so callers know what to expect
+	Capabilities() Capabilities
+	// Fetch returns the reviews of appID newer than cursor, newest first when the source knows the order,
+	// and the token the next fetch of appID gets in its cursor
+	Fetch(ctx context.Context, appID string, cursor Cursor) (FetchResult, error)
+}
+
+// Metadata describes a source
+type Metadata struct {
+	Kind        string // kind the source is registered under, like appstore-rss
+	Description string
+	Location    string // where reviews are read from, a URL or a folder
+}
+
+// Capabilities describes the fetches of a source
+type Capabilities struct {
+	// Incremental sources only return the reviews newer than the cursor, the others return every review
+	// they have on every fetch and rely on stored reviews being skipped
+	Incremental bool
+	// MaxReviewsPerFetch is the most reviews a fetch returns, 0 for no limit. Reaching it means
+	// reviews may have been missed, like Apple's feed only going back 10 pages.
+	MaxReviewsPerFetch int
+	// Ratings reports whether reviews have a star rating, reviews of sources without one have rating 0
+	Ratings bool
+}
+
+// Cursor is where the previous fetch of an app stopped
+type Cursor struct {
+	// LatestReviewID is the ID of the newest stored review of the app, from any source, empty when none is stored.
+	// Sources can fall back to it when they have no Token, after a restart.
+	LatestReviewID  string
+	LatestUpdatedAt time.Time // update time of that review
+	// Token is what the previous fetch of the app by this source returned, empty on the first fetch since startup
+	Token string
+}
+
+// FetchResult is the outcome of a fetch
+type FetchResult struct {
+	Reviews []models.AppStoreReview
+	Token   string // handed back in the cursor of the next fetch of the app
+}
+
+// Factory creates a source from the settings of its REVIEW_SOURCES entry, the text after the kind and a colon,
+// empty when there is none
+type Factory func(settings string) (ReviewSource, error)
+
+// Registry holds the factories of the source kinds
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry creates a registry without any kind
+func NewRegistry() *Registry {
+	return &Registry{factories: map[string]Factory{}}
+}
+
+// Register adds a kind of source, replacing the factory of a kind registered before
+func (r *Registry) Register(kind string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[kind] = factory
+}
+
+// Kinds returns the registered kinds, sorted
+func (r *Registry) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]string, 0, len(r.factories))
+	for kind := range r.factories {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// Build creates a source for every spec, "kind" or "kind:settings"
+func (r *Registry) Build(specs []string) ([]ReviewSource, error) {
+	var built []ReviewSource
+	for _, spec := range specs {
+		kind, settings, _ := strings.Cut(strings.TrimSpace(spec), ":")
+
+		r.mu.RLock()
+		factory, ok := r.factories[kind]
+		r.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown review source %q: expected one of %s", kind, strings.Join(r.Kinds(), ", "))
+		}
+
+		source, err := factory(settings)
+		if err != nil {
+			return nil, fmt.Errorf("review source %q: %w", spec, err)
+		}
+		built = append(built, source)
+	}
+	return built, nil
+}
+
+// Default is the registry the poller builds REVIEW_SOURCES from, with the built-in kinds
+var Default = NewRegistry()
+
+// Kinds of the built-in sources
+const (
+	KindAppStoreRSS  = "appstore-rss"
+	KindJSONEndpoint = "json"
+	KindCSVFolder    = "csv-folder"
+)
+
+func init() {
+	Default.Register(KindAppStoreRSS, func(settings string) (ReviewSource, error) {
+		if settings == "" {
+			settings = AppStoreRSSBaseURL
+		}
+		return NewAppStoreRSS(settings), nil
+	})
+	Default.Register(KindJSONEndpoint, func(settings string) (ReviewSource, error) {
+		return NewJSONEndpoint(settings)
+	})
+	Default.Register(KindCSVFolder, func(settings string) (ReviewSource, error) {
+		return NewCSVFolder(settings)
+	})
+}
+
+// Register adds a kind of source to the Default registry, call it before the poller is created
+func Register(kind string, factory Factory) {
+	Default.Register(kind, factory)
+}
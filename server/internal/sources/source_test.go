@@ -0,0 +1,57 @@
+package sources
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type stubSource struct {
+	settings string
+}
+
+func (s *stubSource) Metadata() Metadata         { return Metadata{Kind: "stub", Location: s.settings} }
+func (s *stubSource) Capabilities() Capabilities { return Capabilities{} }
+func (s *stubSource) Fetch(ctx context.Context, appID string, cursor Cursor) (FetchResult, error) {
+	return FetchResult{}, nil
+}
+
+// TestRegistry_Build verifies that specs are split into kind and settings, and that unknown kinds are listed in the error
+func TestRegistry_Build(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("stub", func(settings string) (ReviewSource, error) {
+		return &stubSource{settings: settings}, nil
+	})
+
+	built, err := registry.Build([]string{"stub", " stub:https://example.com/{appId}.json "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(built) != 2 || built[0].Metadata().Location != "" || built[1].Metadata().Location != "https://example.com/{appId}.json" {
+		t.Errorf("Expected the settings after the first colon, got %+v", built)
+	}
+
+	_, err = registry.Build([]string{"stub", "unknown"})
+	if err == nil || !strings.Contains(err.Error(), `"unknown"`) || !strings.Contains(err.Error(), "stub") {
+		t.Errorf("Expected an error naming the unknown kind and the registered ones, got %v", err)
+	}
+}
+
+// TestDefault_BuiltInKinds verifies the kinds of the Default registry and that their settings are validated
+func TestDefault_BuiltInKinds(t *testing.T) {
+	kinds := Default.Kinds()
+	if strings.Join(kinds, ",") != "appstore-rss,csv-folder,json" {
+		t.Errorf("Expected the built-in kinds, got %v", kinds)
+	}
+
+	built, err := Default.Build([]string{"appstore-rss"})
+	if err != nil || built[0].Metadata().Location != AppStoreRSSBaseURL {
+		t.Errorf("Expected the App Store feed to default to the US storefront, got %v", err)
+	}
+	if _, err := Default.Build([]string{"json"}); err == nil {
+		t.Error("Expected an error for a json source without URL")
+	}
+	if _, err := Default.Build([]string{"csv-folder"}); err == nil {
+		t.Error("Expected an error for a csv-folder source without folder")
+	}
+}
@@ -0,0 +1,117 @@
+// Package spam flags reviews that look like spam: near duplicates of earlier reviews,
+// bursts of reviews by the same author, links and character floods
+package spam
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/language"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+const (
+	// DuplicateSimilarity is the estimated Jaccard similarity of the content shingles from which reviews are near duplicates
+	DuplicateSimilarity = 0.8
+	// minDuplicateWords keeps short common reviews, like "great app, love it", from being duplicates
+	minDuplicateWords = 6
+
+	authorWindow     = 7 * 24 * time.Hour
+	maxAuthorReviews = 2 // reviews an author can post within authorWindow before the next ones are flagged
+
+	floodRunLength = 10 // times a character must repeat in a row to be a flood
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|io|ly|me|co|app|xyz|info|biz|top|ru|gg|link|site|online|shop)\b(?:/\S*)?`)
+
+// Detector flags reviews against the reviews it checked before. Reviews must be checked oldest first,
+// the later of two duplicates is the one flagged. It is safe for concurrent use.
+type Detector struct {
+	mu      sync.Mutex
+	index   *lshIndex
+	authors map[string][]time.Time // review times of each author, in checking order
+	checked map[string]bool
+}
+
+// New creates a Detector that has checked no review yet
+func New() *Detector {
+	return &Detector{
+		index:   newLSHIndex(),
+		authors: make(map[string][]time.Time),
+		checked: make(map[string]bool),
+	}
+}
+
+// Check returns the flags of review, nil if it looks legitimate, and remembers it for the next checks.
+// Checking a review again compares it with the others only.
+func (d *Detector) Check(review models.AppStoreReview) []models.ReviewFlag {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var flags []models.ReviewFlag
+
+	words := language.SearchTerms(review.Content, "")
+	if len(words) >= minDuplicateWords {
+		sig := minHash(shingles(words))
+		if id, similarity := d.index.mostSimilar(review.ID, sig); similarity >= DuplicateSimilarity {
+			flags = append(flags, models.ReviewFlag{
+				Reason: models.FlagDuplicate,
+				Detail: fmt.Sprintf("%.0f%% similar to review %s", similarity*100, id),
+			})
+		}
+		d.index.add(review.ID, sig)
+	}
+
+	if author := strings.ToLower(strings.TrimSpace(review.Author)); author != "" {
+		if !d.checked[review.ID] {
+			d.authors[author] = append(d.authors[author], review.UpdatedAt)
+		}
+		recent := 0
+		for _, at := range d.authors[author] {
+			if at.After(review.UpdatedAt.Add(-authorWindow)) && !at.After(review.UpdatedAt) {
+				recent++
+			}
+		}
+		if recent > maxAuthorReviews {
+			flags = append(flags, models.ReviewFlag{
+				Reason: models.FlagRepeatedAuthor,
+				Detail: fmt.Sprintf("%d reviews by %q within 7 days", recent, review.Author),
+			})
+		}
+	}
+	d.checked[review.ID] = true
+
+	if link := linkPattern.FindString(review.Title + "\n" + review.Content); link != "" {
+		flags = append(flags, models.ReviewFlag{Reason: models.FlagLink, Detail: "contains " + link})
+	}
+
+	if r, count := longestRun(review.Title + "\n" + review.Content); count >= floodRunLength {
+		flags = append(flags, models.ReviewFlag{
+			Reason: models.FlagCharacterFlood,
+			Detail: fmt.Sprintf("%q repeated %d times", r, count),
+		})
+	}
+
+	return flags
+}
+
+// longestRun returns the character repeated the most times in a row in text, spaces aside
+func longestRun(text string) (rune, int) {
+	var best, previous rune
+	bestCount, count := 0, 0
+	for _, r := range text {
+		if r == previous {
+			count++
+		} else {
+			previous, count = r, 1
+		}
+		if count > bestCount && !unicode.IsSpace(r) {
+			best, bestCount = r, count
+		}
+	}
+	return best, bestCount
+}
@@ -0,0 +1,122 @@
+package spam
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testReview(id, author, content string, hour int) models.AppStoreReview {
+	return models.AppStoreReview{ID: id, Author: author, Title: "Review", Content: content, Rating: 5, UpdatedAt: testStart.Add(time.Duration(hour) * time.Hour)}
+}
+
+func reasons(flags []models.ReviewFlag) []string {
+	var result []string
+	for _, flag := range flags {
+		result = append(result, flag.Reason)
+	}
+	return result
+}
+
+func hasReason(flags []models.ReviewFlag, reason string) bool {
+	for _, flag := range flags {
+		if flag.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+const promo = "Best app ever, download it now and get free coins every day with the daily bonus, five stars from me and my whole family"
+
+// TestCheck_NearDuplicates verifies that the later of two near duplicates is flagged, whatever the case and punctuation
+func TestCheck_NearDuplicates(t *testing.T) {
+	detector := New()
+
+	if flags := detector.Check(testReview("1", "ann", promo, 0)); flags != nil {
+		t.Fatalf("Expected first review to be clean, got %v", reasons(flags))
+	}
+
+	copied := strings.ToUpper(strings.ReplaceAll(promo, ",", "!!"))
+	flags := detector.Check(testReview("2", "bob", copied, 1))
+	if !hasReason(flags, models.FlagDuplicate) || !strings.Contains(flags[0].Detail, "review 1") {
+		t.Errorf("Expected exact copy to be a duplicate of review 1, got %+v", flags)
+	}
+
+	edited := strings.Replace(promo, "my whole family", "my brother", 1)
+	if flags := detector.Check(testReview("3", "cid", edited, 2)); !hasReason(flags, models.FlagDuplicate) {
+		t.Errorf("Expected lightly edited copy to be a duplicate, got %v", reasons(flags))
+	}
+
+	different := "The new update broke the login screen and support has not answered my emails for a week now"
+	if flags := detector.Check(testReview("4", "dan", different, 3)); flags != nil {
+		t.Errorf("Expected a different review to be clean, got %v", reasons(flags))
+	}
+}
+
+// TestCheck_ShortReviewsAreNotDuplicates verifies that short common reviews are never duplicates
+func TestCheck_ShortReviewsAreNotDuplicates(t *testing.T) {
+	detector := New()
+	for i := range 3 {
+		if flags := detector.Check(testReview(fmt.Sprint(i), fmt.Sprint("user", i), "Great app, love it", i)); flags != nil {
+			t.Errorf("Expected short review %d to be clean, got %v", i, reasons(flags))
+		}
+	}
+}
+
+// TestCheck_RecheckingComparesWithOthersOnly verifies that a review checked again isn't its own duplicate
+func TestCheck_RecheckingComparesWithOthersOnly(t *testing.T) {
+	detector := New()
+	review := testReview("1", "ann", promo, 0)
+	detector.Check(review)
+	detector.Check(review)
+	if flags := detector.Check(review); flags != nil {
+		t.Errorf("Expected rechecked review to stay clean, got %v", reasons(flags))
+	}
+}
+
+// TestCheck_RepeatedAuthor verifies that an author's reviews past the limit of the window are flagged
+func TestCheck_RepeatedAuthor(t *testing.T) {
+	detector := New()
+	contents := []string{"Nice", "Good game", "Fun to play", "Love the levels"}
+
+	for i, content := range contents[:2] {
+		if flags := detector.Check(testReview(fmt.Sprint(i), "Spammer", content, i*24)); flags != nil {
+			t.Errorf("Expected review %d to be clean, got %v", i, reasons(flags))
+		}
+	}
+	if flags := detector.Check(testReview("2", " spammer ", contents[2], 48)); !hasReason(flags, models.FlagRepeatedAuthor) {
+		t.Errorf("Expected third review in 7 days to be flagged, got %v", reasons(flags))
+	}
+	if flags := detector.Check(testReview("3", "Spammer", contents[3], 24*30)); flags != nil {
+		t.Errorf("Expected a review weeks later to be clean, got %v", reasons(flags))
+	}
+}
+
+// TestCheck_LinksAndFloods verifies the link and character flood heuristics
+func TestCheck_LinksAndFloods(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []string
+	}{
+		{"Get free gems at https://free-gems.example/now", []string{models.FlagLink}},
+		{"Visit www.cheap-coins.net for more", []string{models.FlagLink}},
+		{"Check freecoins.xyz today", []string{models.FlagLink}},
+		{"Version 2.1 fixed it. Thanks!", nil},
+		{"Best game!!!!!!!!!!!!", []string{models.FlagCharacterFlood}},
+		{"Sooooo good", nil},
+		{"😍😍😍😍😍😍😍😍😍😍😍 bit.ly/x", []string{models.FlagLink, models.FlagCharacterFlood}},
+	}
+
+	for i, test := range tests {
+		flags := New().Check(testReview(fmt.Sprint(i), "", test.content, 0))
+		if got := reasons(flags); strings.Join(got, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("Expected %q to be flagged %v, got %v", test.content, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package tagging
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// expr is a node of a boolean rule
+type expr interface {
+	eval(text string) bool
+}
+
+type termExpr struct{ phrase string }
+type notExpr struct{ operand expr }
+type andExpr struct{ left, right expr }
+type orExpr struct{ left, right expr }
+
+func (e termExpr) eval(text string) bool { return containsPhrase(text, e.phrase) }
+func (e notExpr) eval(text string) bool  { return !e.operand.eval(text) }
+func (e andExpr) eval(text string) bool  { return e.left.eval(text) && e.right.eval(text) }
+func (e orExpr) eval(text string) bool   { return e.left.eval(text) || e.right.eval(text) }
+
+// parseBoolean parses expressions like `(refund OR "charged twice") AND NOT trial`.
+// NOT binds tighter than AND, which binds tighter than OR. Adjacent terms are joined with AND.
+func parseBoolean(pattern string) (expr, error) {
+	tokens, err := lexBoolean(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &booleanParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].value)
+	}
+	return e, nil
+}
+
+type booleanToken struct {
+	value  string
+	phrase bool // a word or quoted phrase, not an operator or parenthesis
+}
+
+func lexBoolean(pattern string) ([]booleanToken, error) {
+	var tokens []booleanToken
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, booleanToken{value: string(r)})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			tokens = append(tokens, booleanToken{value: string(runes[i+1 : end]), phrase: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' && runes[end] != '"' {
+				end++
+			}
+			word := string(runes[i:end])
+			switch word {
+			case "AND", "OR", "NOT":
+				tokens = append(tokens, booleanToken{value: word})
+			default:
+				tokens = append(tokens, booleanToken{value: word, phrase: true})
+			}
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type booleanParser struct {
+	tokens []booleanToken
+	pos    int
+}
+
+func (p *booleanParser) peek() (booleanToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return booleanToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *booleanParser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		token, ok := p.peek()
+		if !ok || token.phrase || token.value != "OR" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+}
+
+func (p *booleanParser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		token, ok := p.peek()
+		if !ok || (!token.phrase && token.value != "AND" && token.value != "NOT" && token.value != "(") {
+			return left, nil
+		}
+		if !token.phrase && token.value == "AND" {
+			p.pos++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *booleanParser) parseNot() (expr, error) {
+	token, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if !token.phrase && token.value == "NOT" {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *booleanParser) parsePrimary() (expr, error) {
+	token, _ := p.peek()
+	p.pos++
+
+	if token.phrase {
+		phrase := normalize(token.value)
+		if strings.TrimSpace(phrase) == "" {
+			return nil, fmt.Errorf("empty term %q", token.value)
+		}
+		return termExpr{phrase}, nil
+	}
+	if token.value != "(" {
+		return nil, fmt.Errorf("unexpected %q", token.value)
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if closing, ok := p.peek(); !ok || closing.phrase || closing.value != ")" {
+		return nil, fmt.Errorf("missing closing parenthesis")
+	}
+	p.pos++
+	return e, nil
+}
@@ -0,0 +1,123 @@
+// Package tagging assigns topics to reviews with user defined rules
+package tagging
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/sentiment"
+)
+
+// matcher reports whether the normalized and raw text of a review match a rule
+type matcher func(normalized, raw string) bool
+
+type compiledRule struct {
+	tag   string
+	match matcher
+}
+
+// Tagger tags reviews with the tags of the rules they match. It is immutable, safe for concurrent use.
+type Tagger struct {
+	rules []compiledRule
+}
+
+// New compiles rules, returning the errors of the rules that don't compile. Those rules are skipped.
+func New(rules []models.TagRule) (*Tagger, []error) {
+	tagger := &Tagger{}
+	var errs []error
+	for _, rule := range rules {
+		match, err := compile(rule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tag rule %s (%s): %w", rule.ID, rule.Tag, err))
+			continue
+		}
+		tagger.rules = append(tagger.rules, compiledRule{tag: rule.Tag, match: match})
+	}
+	return tagger, errs
+}
+
+// Validate reports why rule is invalid, nil if it is valid
+func Validate(rule models.TagRule) error {
+	if strings.TrimSpace(rule.Tag) == "" {
+		return fmt.Errorf("tag must not be empty")
+	}
+	_, err := compile(rule)
+	return err
+}
+
+// compile builds the matcher of a rule
+func compile(rule models.TagRule) (matcher, error) {
+	switch rule.Type {
+	case models.TagRuleKeyword:
+		var phrases []string
+		for _, keyword := range strings.Split(rule.Pattern, ",") {
+			if phrase := normalize(keyword); strings.TrimSpace(phrase) != "" {
+				phrases = append(phrases, phrase)
+			}
+		}
+		if len(phrases) == 0 {
+			return nil, fmt.Errorf("pattern must list at least one keyword")
+		}
+		return func(normalized, _ string) bool {
+			return slices.ContainsFunc(phrases, func(phrase string) bool { return containsPhrase(normalized, phrase) })
+		}, nil
+
+	case models.TagRuleRegex:
+		re, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		return func(_, raw string) bool { return re.MatchString(raw) }, nil
+
+	case models.TagRuleBoolean:
+		e, err := parseBoolean(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean expression: %w", err)
+		}
+		return func(normalized, _ string) bool { return e.eval(normalized) }, nil
+	}
+
+	return nil, fmt.Errorf("unknown rule type %q", rule.Type)
+}
+
+// Tags returns the sorted tags of the rules matching the title or content of review, nil if none matches
+func (t *Tagger) Tags(review models.AppStoreReview) []string {
+	if len(t.rules) == 0 {
+		return nil
+	}
+
+	raw := review.Title + "\n" + review.Content
+	normalized := normalize(raw)
+
+	var tags []string
+	for _, rule := range t.rules {
+		if !slices.Contains(tags, rule.tag) && rule.match(normalized, raw) {
+			tags = append(tags, rule.tag)
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// Apply sets the tags of review, reporting whether they changed
+func (t *Tagger) Apply(review *models.AppStoreReview) bool {
+	tags := t.Tags(*review)
+	if slices.Equal(tags, review.Tags) {
+		return false
+	}
+	review.Tags = tags
+	return true
+}
+
+// normalize lowercases text into space separated words, with spaces around, so phrases match whole words only
+func normalize(text string) string {
+	return " " + strings.Join(sentiment.Tokenize(text), " ") + " "
+}
+
+// containsPhrase reports whether the normalized text contains the normalized phrase
+func containsPhrase(text, phrase string) bool {
+	return strings.Contains(text, phrase)
+}
@@ -0,0 +1,129 @@
+package tagging
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+)
+
+func review(title, content string) models.AppStoreReview {
+	return models.AppStoreReview{Title: title, Content: content}
+}
+
+// TestTags_KeywordRules verifies that keywords and phrases match whole words, case insensitively
+func TestTags_KeywordRules(t *testing.T) {
+	tagger, errs := New([]models.TagRule{
+		{ID: "1", Tag: "billing", Type: models.TagRuleKeyword, Pattern: "refund, charged twice, subscription"},
+		{ID: "2", Tag: "login", Type: models.TagRuleKeyword, Pattern: "log in,password"},
+	})
+	if len(errs) > 0 {
+		t.Fatalf("Expected rules to compile, got %v", errs)
+	}
+
+	tests := []struct {
+		review   models.AppStoreReview
+		expected []string
+	}{
+		{review("Want a REFUND", "I was Charged twice!"), []string{"billing"}},
+		{review("Can't log in", "and my subscription expired"), []string{"billing", "login"}},
+		{review("Refunded", "subscriptions are great"), nil},
+		{review("Charged", "Twice I tried"), nil},
+	}
+	for _, test := range tests {
+		if tags := tagger.Tags(test.review); !slices.Equal(tags, test.expected) {
+			t.Errorf("Expected %q to be tagged %v, got %v", test.review.Title+" "+test.review.Content, test.expected, tags)
+		}
+	}
+}
+
+// TestTags_RegexRules verifies that regex rules match the raw title and content, case insensitively
+func TestTags_RegexRules(t *testing.T) {
+	tagger, _ := New([]models.TagRule{
+		{ID: "1", Tag: "performance", Type: models.TagRuleRegex, Pattern: `\b(slow|lag+y?)\b|takes \d+ seconds`},
+	})
+
+	if tags := tagger.Tags(review("Laggy", "")); !slices.Equal(tags, []string{"performance"}) {
+		t.Errorf("Expected title to match, got %v", tags)
+	}
+	if tags := tagger.Tags(review("Bad", "It takes 30 seconds to open")); !slices.Equal(tags, []string{"performance"}) {
+		t.Errorf("Expected content to match, got %v", tags)
+	}
+	if tags := tagger.Tags(review("Slowly getting better", "")); tags != nil {
+		t.Errorf("Expected no tags, got %v", tags)
+	}
+}
+
+// TestTags_BooleanRules verifies operator precedence, parentheses, quoted phrases and implicit AND
+func TestTags_BooleanRules(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		matches bool
+	}{
+		{`crash AND NOT fixed`, "It crashes", false},
+		{`crash AND NOT fixed`, "crash on start", true},
+		{`crash AND NOT fixed`, "the crash was fixed", false},
+		{`crash OR freeze AND login`, "crash", true},
+		{`(crash OR freeze) AND login`, "crash", false},
+		{`(crash OR freeze) AND login`, "freeze after login", true},
+		{`"dark mode" ui`, "please add dark mode to the ui", true},
+		{`"dark mode" ui`, "the mode is dark in the ui", false},
+		{`NOT (ads OR ad)`, "no complaints", true},
+		{`not ads`, "too many ads", false},
+	}
+
+	for _, test := range tests {
+		tagger, errs := New([]models.TagRule{{ID: "1", Tag: "match", Type: models.TagRuleBoolean, Pattern: test.pattern}})
+		if len(errs) > 0 {
+			t.Fatalf("Expected %q to compile, got %v", test.pattern, errs)
+		}
+		if matched := tagger.Tags(review("", test.text)) != nil; matched != test.matches {
+			t.Errorf("Expected %q on %q to match %v, got %v", test.pattern, test.text, test.matches, matched)
+		}
+	}
+}
+
+// TestValidate_RejectsInvalidRules verifies that invalid rules are reported and skipped by New
+func TestValidate_RejectsInvalidRules(t *testing.T) {
+	invalid := []models.TagRule{
+		{Tag: "", Type: models.TagRuleKeyword, Pattern: "refund"},
+		{Tag: "billing", Type: models.TagRuleKeyword, Pattern: " , "},
+		{Tag: "billing", Type: models.TagRuleRegex, Pattern: "refund("},
+		{Tag: "billing", Type: models.TagRuleBoolean, Pattern: "refund AND"},
+		{Tag: "billing", Type: models.TagRuleBoolean, Pattern: "(refund OR charge"},
+		{Tag: "billing", Type: models.TagRuleBoolean, Pattern: `"refund`},
+		{Tag: "billing", Type: "fuzzy", Pattern: "refund"},
+	}
+	for _, rule := range invalid {
+		if err := Validate(rule); err == nil {
+			t.Errorf("Expected %+v to be invalid", rule)
+		}
+	}
+
+	tagger, errs := New(append(invalid[1:], models.TagRule{ID: "ok", Tag: "billing", Type: models.TagRuleKeyword, Pattern: "refund"}))
+	if len(errs) != len(invalid)-1 {
+		t.Errorf("Expected %d errors, got %v", len(invalid)-1, errs)
+	}
+	if tags := tagger.Tags(review("refund", "")); !slices.Equal(tags, []string{"billing"}) {
+		t.Errorf("Expected the valid rule to still apply, got %v", tags)
+	}
+}
+
+// TestApply_ReportsChanges verifies that Apply only reports a change when the tags differ
+func TestApply_ReportsChanges(t *testing.T) {
+	tagger, _ := New([]models.TagRule{{ID: "1", Tag: "billing", Type: models.TagRuleKeyword, Pattern: "refund"}})
+
+	r := review("refund please", "")
+	if !tagger.Apply(&r) || !slices.Equal(r.Tags, []string{"billing"}) {
+		t.Fatalf("Expected review to be tagged, got %v", r.Tags)
+	}
+	if tagger.Apply(&r) {
+		t.Error("Expected no change when applying the same rules again")
+	}
+
+	empty, _ := New(nil)
+	if !empty.Apply(&r) || r.Tags != nil {
+		t.Errorf("Expected tags to be removed, got %v", r.Tags)
+	}
+}
@@ -0,0 +1,21 @@
+// Package textfmt formats reviews for the plain text of notifications and digests
+package textfmt
+
+import "strings"
+
+// Stars renders rating as five filled or empty stars, all empty when it is out of range
+func Stars(rating int) string {
+	if rating < 0 || rating > 5 {
+		rating = 0
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+// Excerpt cuts text to at most maxRunes runes, adding an ellipsis when it was cut
+func Excerpt(text string, maxRunes int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= maxRunes {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
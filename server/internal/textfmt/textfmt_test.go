@@ -0,0 +1,23 @@
+package textfmt
+
+import "testing"
+
+// TestStars verifies that ratings are rendered as five stars
+func TestStars(t *testing.T) {
+	cases := map[int]string{0: "☆☆☆☆☆", 3: "★★★☆☆", 5: "★★★★★", 7: "☆☆☆☆☆"}
+	for rating, expected := range cases {
+		if got := Stars(rating); got != expected {
+			t.Errorf("Expected %q for rating %d, got %q", expected, rating, got)
+		}
+	}
+}
+
+// TestExcerpt verifies that long texts are cut on runes with an ellipsis
+func TestExcerpt(t *testing.T) {
+	if got := Excerpt("  short  ", 10); got != "short" {
+		t.Errorf("Expected short texts to be kept, got %q", got)
+	}
+	if got := Excerpt("ótimo app de verdade", 6); got != "ótimo…" {
+		t.Errorf("Expected %q, got %q", "ótimo…", got)
+	}
+}
@@ -0,0 +1,288 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/ids"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	EventReviewsCreated = "reviews.created"
+)
+
+// Payload is the JSON body POSTed to subscriptions
+type Payload struct {
+	Event      string                  `json:"event"`
+	DeliveryID string                  `json:"deliveryId"`
+	AppID      string                  `json:"appId"`
+	Reviews    []models.AppStoreReview `json:"reviews"`
+}
+
+// Dispatcher turns new review batches into signed deliveries and sends them, retrying failures
+// with exponential backoff until they are moved to the dead letters
+type Dispatcher struct {
+	repo        *repositories.WebhooksRepository
+	client      *http.Client
+	now         func() time.Time
+	wake        chan struct{}
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// New creates a Dispatcher storing subscriptions and deliveries in repo
+func New(repo *repositories.WebhooksRepository) *Dispatcher {
+	return &Dispatcher{
+		repo: repo,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+		maxAttempts: 8,
+		baseBackoff: 30 * time.Second,
+		maxBackoff:  1 * time.Hour,
+	}
+}
+
+// Subscribe creates a subscription, generating a secret when none is given
+func (d *Dispatcher) Subscribe(url, appID string, ratings []int, secret string) (models.WebhookSubscription, error) {
+	if secret == "" {
+		secret = ids.New()
+	}
+
+	subscription := models.WebhookSubscription{
+		ID:        ids.New(),
+		URL:       url,
+		AppID:     appID,
+		Ratings:   ratings,
+		Secret:    secret,
+		CreatedAt: d.now().UTC(),
+	}
+	if err := d.repo.AddSubscription(subscription); err != nil {
+		return models.WebhookSubscription{}, fmt.Errorf("saving subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// Unsubscribe deletes a subscription and its pending deliveries. Returns false if it doesn't exist
+func (d *Dispatcher) Unsubscribe(id string) (bool, error) {
+	return d.repo.DeleteSubscription(id)
+}
+
+func (d *Dispatcher) Subscriptions() []models.WebhookSubscription {
+	return d.repo.ListSubscriptions()
+}
+
+func (d *Dispatcher) Outbox() []models.WebhookDelivery {
+	return d.repo.ListOutbox()
+}
+
+func (d *Dispatcher) DeadLetters() []models.WebhookDelivery {
+	return d.repo.ListDeadLetters()
+}
+
+// RetryDeadLetter moves a dead letter back to the outbox to be sent right away. Returns false if it doesn't exist
+func (d *Dispatcher) RetryDeadLetter(id string) (bool, error) {
+	found, err := d.repo.RequeueDeadLetter(id, d.now().UTC())
+	if found && err == nil {
+		d.signal()
+	}
+	return found, err
+}
+
+// HandleBatch enqueues a delivery for every subscription matching the batch of new reviews.
+// It doesn't send anything, Run does, so the caller (the poller) is never slowed down by receivers.
+func (d *Dispatcher) HandleBatch(appID string, reviews []models.AppStoreReview) {
+	now := d.now().UTC()
+
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range d.repo.ListSubscriptions() {
+		if subscription.AppID != "" && subscription.AppID != appID {
+			continue
+		}
+
+		matching := make([]models.AppStoreReview, 0, len(reviews))
+		for _, review := range reviews {
+			if len(subscription.Ratings) == 0 || slices.Contains(subscription.Ratings, review.Rating) {
+				matching = append(matching, review)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+
+		deliveryID := ids.New()
+		payload, err := json.Marshal(Payload{
+			Event:      EventReviewsCreated,
+			DeliveryID: deliveryID,
+			AppID:      appID,
+			Reviews:    matching,
+		})
+		if err != nil {
+			log.Printf("WEBHOOKS: error encoding payload for subscription %s: %v", subscription.ID, err)
+			continue
+		}
+
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:             deliveryID,
+			SubscriptionID: subscription.ID,
+			URL:            subscription.URL,
+			Payload:        payload,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return
+	}
+	if err := d.repo.Enqueue(deliveries); err != nil {
+		log.Printf("WEBHOOKS: error saving %d deliveries to the outbox: %v", len(deliveries), err)
+	}
+	d.signal()
+}
+
+// Run sends due deliveries whenever new ones are enqueued and every checkInterval for retries.
+// Blocks until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	d.DeliverDue(ctx) // deliveries left in the outbox by a previous run
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("webhooks dispatcher stopping")
+			return
+		case <-ticker.C:
+			d.DeliverDue(ctx)
+		case <-d.wake:
+			d.DeliverDue(ctx)
+		}
+	}
+}
+
+// DeliverDue attempts every delivery in the outbox that is due
+func (d *Dispatcher) DeliverDue(ctx context.Context) {
+	secrets := make(map[string]string)
+	for _, subscription := range d.repo.ListSubscriptions() {
+		secrets[subscription.ID] = subscription.Secret
+	}
+
+	for _, delivery := range d.repo.DueDeliveries(d.now().UTC()) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		secret, ok := secrets[delivery.SubscriptionID]
+		if !ok {
+			continue // subscription deleted, its deliveries are being removed
+		}
+
+		err := d.send(ctx, delivery, secret)
+		if err == nil {
+			if err := d.repo.CompleteDelivery(delivery.ID); err != nil {
+				log.Printf("WEBHOOKS: error removing delivery %s from the outbox: %v", delivery.ID, err)
+			}
+			continue
+		}
+
+		d.fail(delivery, err)
+	}
+}
+
+// fail schedules the next attempt of a delivery with exponential backoff, or dead letters it
+func (d *Dispatcher) fail(delivery models.WebhookDelivery, sendErr error) {
+	delivery.Attempts++
+	delivery.LastError = sendErr.Error()
+
+	if delivery.Attempts >= d.maxAttempts {
+		log.Printf("WEBHOOKS: ❌ delivery %s to %s failed %d times, moving it to dead letters: %v", delivery.ID, delivery.URL, delivery.Attempts, sendErr)
+		if err := d.repo.DeadLetter(delivery); err != nil {
+			log.Printf("WEBHOOKS: error dead lettering delivery %s: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	backoff := d.baseBackoff << (delivery.Attempts - 1)
+	if backoff > d.maxBackoff || backoff <= 0 {
+		backoff = d.maxBackoff
+	}
+	delivery.NextAttemptAt = d.now().UTC().Add(backoff)
+
+	log.Printf("WEBHOOKS: delivery %s to %s failed (attempt %d), retrying in %s: %v", delivery.ID, delivery.URL, delivery.Attempts, backoff, sendErr)
+	if err := d.repo.RescheduleDelivery(delivery); err != nil {
+		log.Printf("WEBHOOKS: error rescheduling delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send POSTs a delivery payload signed with the subscription secret
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery, secret string) error {
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "appstore-rss-reviews-webhooks")
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Sign returns the signature header value of a payload: "sha256=" followed by the hex encoded
+// HMAC-SHA256 of "<timestamp>.<payload>" keyed by the subscription secret.
+// Receivers recompute it and compare in constant time, rejecting old timestamps to prevent replays.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value produced by Sign
+func Verify(secret, timestamp string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
@@ -0,0 +1,273 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/models"
+	"github.com/Gust4voSales/appstore-rss-reviews-app/server/internal/repositories"
+)
+
+// TestMain suppresses logs during all tests
+func TestMain(m *testing.M) {
+	// Suppress logs during testing
+	log.SetOutput(io.Discard)
+
+	// Run tests
+	code := m.Run()
+
+	// Restore log output
+	log.SetOutput(os.Stderr)
+
+	// Exit with the same code as the tests
+	os.Exit(code)
+}
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// receiver is a local webhook receiver recording the requests it gets
+type receiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int // status returned for each request, 200 once exhausted
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// createTestDispatcher creates a dispatcher persisting to a temp file, driven by a fake clock
+func createTestDispatcher(t *testing.T) (*Dispatcher, *fakeClock, string) {
+	filePath := filepath.Join(t.TempDir(), "webhooks.json")
+	clock := &fakeClock{now: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}
+
+	dispatcher := New(repositories.LoadWebhooks(filePath))
+	dispatcher.now = clock.Now
+
+	return dispatcher, clock, filePath
+}
+
+func startReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	rec := &receiver{statuses: statuses}
+	server := httptest.NewServer(rec)
+	t.Cleanup(server.Close)
+	return rec, server
+}
+
+func createTestReviews() []models.AppStoreReview {
+	return []models.AppStoreReview{
+		{ID: "review-1", Title: "Crashes", Content: "Crashes on launch", Author: "User1", Rating: 1, UpdatedAt: time.Now().UTC()},
+		{ID: "review-2", Title: "Great", Content: "Love it", Author: "User2", Rating: 5, UpdatedAt: time.Now().UTC()},
+	}
+}
+
+// TestHandleBatch_SendsSignedPayload verifies that a matching batch is POSTed with a valid signature
+func TestHandleBatch_SendsSignedPayload(t *testing.T) {
+	dispatcher, _, _ := createTestDispatcher(t)
+	rec, server := startReceiver(t)
+
+	subscription, err := dispatcher.Subscribe(server.URL, "", []int{1, 2}, "top-secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dispatcher.HandleBatch("test-app", createTestReviews())
+	dispatcher.DeliverDue(context.Background())
+
+	if rec.count() != 1 {
+		t.Fatalf("Expected 1 request, got %d", rec.count())
+	}
+
+	req, body := rec.requests[0], rec.bodies[0]
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON POST, got %s %s", req.Method, req.Header.Get("Content-Type"))
+	}
+	if !Verify(subscription.Secret, req.Header.Get(TimestampHeader), body, req.Header.Get(SignatureHeader)) {
+		t.Error("Expected signature to verify with the subscription secret")
+	}
+	if Verify("wrong-secret", req.Header.Get(TimestampHeader), body, req.Header.Get(SignatureHeader)) {
+		t.Error("Expected signature to fail with another secret")
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	if payload.Event != EventReviewsCreated || payload.AppID != "test-app" {
+		t.Errorf("Unexpected payload envelope: %+v", payload)
+	}
+	if len(payload.Reviews) != 1 || payload.Reviews[0].ID != "review-1" {
+		t.Errorf("Expected only the 1-star review, got %+v", payload.Reviews)
+	}
+	if payload.DeliveryID != req.Header.Get(DeliveryHeader) {
+		t.Errorf("Expected delivery header %s to match payload %s", req.Header.Get(DeliveryHeader), payload.DeliveryID)
+	}
+
+	if len(dispatcher.Outbox()) != 0 {
+		t.Errorf("Expected outbox to be empty after success, got %d deliveries", len(dispatcher.Outbox()))
+	}
+}
+
+// TestHandleBatch_SkipsNonMatchingSubscriptions verifies that rating and app filters are applied
+func TestHandleBatch_SkipsNonMatchingSubscriptions(t *testing.T) {
+	dispatcher, _, _ := createTestDispatcher(t)
+	rec, server := startReceiver(t)
+
+	dispatcher.Subscribe(server.URL, "", []int{3}, "")
+	dispatcher.Subscribe(server.URL, "other-app", nil, "")
+
+	dispatcher.HandleBatch("test-app", createTestReviews())
+	dispatcher.DeliverDue(context.Background())
+
+	if rec.count() != 0 {
+		t.Errorf("Expected no requests, got %d", rec.count())
+	}
+}
+
+// TestDeliverDue_RetriesWithExponentialBackoff verifies that failed deliveries are retried only when due, with doubling delays
+func TestDeliverDue_RetriesWithExponentialBackoff(t *testing.T) {
+	dispatcher, clock, _ := createTestDispatcher(t)
+	rec, server := startReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+	dispatcher.Subscribe(server.URL, "", nil, "")
+
+	dispatcher.HandleBatch("test-app", createTestReviews())
+	ctx := context.Background()
+
+	dispatcher.DeliverDue(ctx) // attempt 1 fails
+	outbox := dispatcher.Outbox()
+	if len(outbox) != 1 || outbox[0].Attempts != 1 || outbox[0].LastError == "" {
+		t.Fatalf("Expected delivery with 1 failed attempt in the outbox, got %+v", outbox)
+	}
+	if !outbox[0].NextAttemptAt.Equal(clock.Now().Add(dispatcher.baseBackoff)) {
+		t.Errorf("Expected next attempt after %s, got %s", dispatcher.baseBackoff, outbox[0].NextAttemptAt)
+	}
+
+	dispatcher.DeliverDue(ctx) // not due yet
+	if rec.count() != 1 {
+		t.Fatalf("Expected no retry before backoff elapsed, got %d requests", rec.count())
+	}
+
+	clock.Advance(dispatcher.baseBackoff)
+	dispatcher.DeliverDue(ctx) // attempt 2 fails
+	outbox = dispatcher.Outbox()
+	if !outbox[0].NextAttemptAt.Equal(clock.Now().Add(2 * dispatcher.baseBackoff)) {
+		t.Errorf("Expected backoff to double, next attempt at %s", outbox[0].NextAttemptAt)
+	}
+
+	clock.Advance(2 * dispatcher.baseBackoff)
+	dispatcher.DeliverDue(ctx) // attempt 3 succeeds
+	if rec.count() != 3 {
+		t.Errorf("Expected 3 requests, got %d", rec.count())
+	}
+	if len(dispatcher.Outbox()) != 0 {
+		t.Errorf("Expected outbox to be empty after success, got %+v", dispatcher.Outbox())
+	}
+}
+
+// TestDeliverDue_MovesToDeadLettersAfterMaxAttempts verifies dead lettering and retrying a dead letter
+func TestDeliverDue_MovesToDeadLettersAfterMaxAttempts(t *testing.T) {
+	dispatcher, clock, _ := createTestDispatcher(t)
+	dispatcher.maxAttempts = 3
+	rec, server := startReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	dispatcher.Subscribe(server.URL, "", nil, "")
+
+	dispatcher.HandleBatch("test-app", createTestReviews())
+	for i := 0; i < 3; i++ {
+		dispatcher.DeliverDue(context.Background())
+		clock.Advance(dispatcher.maxBackoff)
+	}
+
+	if len(dispatcher.Outbox()) != 0 {
+		t.Errorf("Expected outbox to be empty, got %d deliveries", len(dispatcher.Outbox()))
+	}
+	deadLetters := dispatcher.DeadLetters()
+	if len(deadLetters) != 1 || deadLetters[0].Attempts != 3 {
+		t.Fatalf("Expected 1 dead letter with 3 attempts, got %+v", deadLetters)
+	}
+
+	found, err := dispatcher.RetryDeadLetter(deadLetters[0].ID)
+	if err != nil || !found {
+		t.Fatalf("Expected dead letter to be requeued, got found=%v err=%v", found, err)
+	}
+	dispatcher.DeliverDue(context.Background())
+
+	if rec.count() != 4 {
+		t.Errorf("Expected the requeued delivery to be sent, got %d requests", rec.count())
+	}
+	if len(dispatcher.DeadLetters()) != 0 || len(dispatcher.Outbox()) != 0 {
+		t.Error("Expected requeued delivery to be completed")
+	}
+}
+
+// TestOutbox_PersistsAcrossRestarts verifies that pending deliveries are sent by a dispatcher loaded from storage
+func TestOutbox_PersistsAcrossRestarts(t *testing.T) {
+	dispatcher, _, filePath := createTestDispatcher(t)
+	rec, server := startReceiver(t, http.StatusServiceUnavailable)
+	dispatcher.Subscribe(server.URL, "", nil, "")
+
+	dispatcher.HandleBatch("test-app", createTestReviews())
+	dispatcher.DeliverDue(context.Background()) // fails, stays in the outbox
+
+	restarted := New(repositories.LoadWebhooks(filePath))
+	restarted.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	if len(restarted.Subscriptions()) != 1 || len(restarted.Outbox()) != 1 {
+		t.Fatalf("Expected subscription and pending delivery to be loaded, got %d and %d", len(restarted.Subscriptions()), len(restarted.Outbox()))
+	}
+
+	restarted.DeliverDue(context.Background())
+	if rec.count() != 2 || len(restarted.Outbox()) != 0 {
+		t.Errorf("Expected pending delivery to be sent after restart, got %d requests and %d pending", rec.count(), len(restarted.Outbox()))
+	}
+}
+
+// TestUnsubscribe_DropsPendingDeliveries verifies that deleting a subscription removes its outbox entries
+func TestUnsubscribe_DropsPendingDeliveries(t *testing.T) {
+	dispatcher, _, _ := createTestDispatcher(t)
+	subscription, _ := dispatcher.Subscribe("http://127.0.0.1:1/unreachable", "", nil, "")
+
+	dispatcher.HandleBatch("test-app", createTestReviews())
+	found, err := dispatcher.Unsubscribe(subscription.ID)
+	if err != nil || !found {
+		t.Fatalf("Expected subscription to be deleted, got found=%v err=%v", found, err)
+	}
+
+	if len(dispatcher.Outbox()) != 0 {
+		t.Errorf("Expected pending deliveries to be dropped, got %d", len(dispatcher.Outbox()))
+	}
+}
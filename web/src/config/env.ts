@@ -5,10 +5,12 @@
 
 interface Config {
   API_URL: string;
+  API_KEY?: string; // read-only API key, needed unless the server runs with AUTH_ENABLED=false
 }
 
 const config: Config = {
   API_URL: import.meta.env.VITE_API_URL,
+  API_KEY: import.meta.env.VITE_API_KEY,
 };
 
 // Validate required environment variables
@@ -10,7 +10,7 @@ export interface ReviewsResponse {
 
 export const fetchReviews = async (rating?: Rating, timeRange: TimeRange = DEFAULT_TIME_RANGE): Promise<ReviewsResponse> => {
   try {
-    const url = new URL(`${config.API_URL}/reviews`);
+    const url = new URL(`${config.API_URL}/v1/reviews`);
     if (rating !== undefined) {
       url.searchParams.append('rating', rating.toString());
     }
@@ -21,6 +21,7 @@ export const fetchReviews = async (rating?: Rating, timeRange: TimeRange = DEFAU
       method: 'GET',
       headers: {
         'Content-Type': 'application/json',
+        ...(config.API_KEY ? { 'X-API-Key': config.API_KEY } : {}),
       },
     });
 
@@ -8,6 +8,7 @@ interface ViteTypeOptions {
 
 interface ImportMetaEnv {
   readonly VITE_API_URL: string
+  readonly VITE_API_KEY?: string
 }
 
 interface ImportMeta {